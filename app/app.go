@@ -10,7 +10,6 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime/debug"
-	"strings"
 
 	"github.com/drep-project/DREP-Chain/common"
 	"github.com/drep-project/DREP-Chain/common/fileutil"
@@ -43,17 +42,23 @@ type DrepApp struct {
 	Context *ExecuteContext
 	*cli.App
 	options []Option
+	// error of adding the registered services, returned by Run
+	addErr error
 }
 
-// NewApp create a new app
+// NewApp create a new app, services registered by RegisterService are added into it
 func NewApp() *DrepApp {
-	return &DrepApp{
+	mApp := &DrepApp{
 		Context: &ExecuteContext{
 			Quit: make(chan struct{}),
 		},
 		App:     cli.NewApp(),
 		options: []Option{},
 	}
+	registerLock.Lock()
+	defer registerLock.Unlock()
+	mApp.addErr = mApp.AddServices(registeredServices...)
+	return mApp
 }
 
 // AddService add a server into context
@@ -61,7 +66,8 @@ func (mApp DrepApp) addServiceInstance(service Service) {
 	mApp.Context.AddService(service)
 }
 
-// AddServiceType add many services
+// AddServiceType add many services, the order is not important, services are sorted by their
+// dependencies and the dependency fields are set before the app runs
 func (mApp DrepApp) AddServices(serviceInstances ...interface{}) error {
 	nilService := reflect.TypeOf((*Service)(nil)).Elem()
 
@@ -74,54 +80,29 @@ func (mApp DrepApp) AddServices(serviceInstances ...interface{}) error {
 		if !serviceVal.Type().Implements(nilService) {
 			return ErrNotMatchedService
 		}
-		mApp.addService(serviceVal)
+		if mApp.hasServiceType(serviceVal.Type()) {
+			// already added by RegisterService
+			continue
+		}
+		mApp.addServiceInstance(serviceVal.Interface().(Service))
 	}
 	return nil
 }
 
-// addServiceType add a service and iterator all service that has added in and fields in current service,
-// if exist , set set service in the field
-func (mApp DrepApp) addService(serviceValue reflect.Value) {
-	serviceType := serviceValue.Type()
-	serviceNumFields := serviceType.Elem().NumField()
-	for i := 0; i < serviceNumFields; i++ {
-		serviceTypeField := serviceType.Elem().Field(i)
-		refServiceName := GetServiceTag(serviceTypeField)
-		if refServiceName != "" {
-			preAddServices := mApp.Context.Services
-			hasService := false
-			for _, addedService := range preAddServices {
-
-				if addedService.Name() == refServiceName {
-					//TODO the filed to be set must be set public field, but it wiil be better to set it as a private field ,
-					//TODO There are still some technical difficulties that need to be overcome.
-					//TODO UnsafePointer may help
-					serviceValue.Elem().Field(i).Set(reflect.ValueOf(addedService))
-					hasService = true
-				}
-			}
-
-			if !hasService {
-				fmt.Println(fmt.Sprintf("service not exist %s require %s", serviceValue.Interface().(Service).Name(), refServiceName))
-				//dlog.Debug("service not exist",  "Service", addedService.Name() ,"RefService", refServiceName)
-			}
+// hasServiceType check whether a service with the same type has been added
+func (mApp DrepApp) hasServiceType(serviceType reflect.Type) bool {
+	for _, service := range mApp.Context.Services {
+		if reflect.TypeOf(service) == serviceType {
+			return true
 		}
 	}
-	mApp.addServiceInstance(serviceValue.Interface().(Service))
+	return false
 }
 
 // GetServiceTag read service tag name to match service that has added in
 func GetServiceTag(field reflect.StructField) string {
-	serviceTagStr := field.Tag.Get("service")
-	if serviceTagStr == "" {
-		return ""
-	}
-	serviceName := strings.Split(serviceTagStr, ",")
-	if len(serviceName) == 0 {
-		return ""
-	} else {
-		return serviceName[0]
-	}
+	name, _ := parseServiceTag(field)
+	return name
 }
 
 // Init do something before app run
@@ -133,20 +114,34 @@ func (mApp *DrepApp) Option(option func()) {
 //Run read the global configuration, parse the global command parameters,
 // initialize the main process one by one, and execute the service before the main process starts.
 func (mApp *DrepApp) Run() error {
+	if mApp.addErr != nil {
+		return mApp.addErr
+	}
 	for _, op := range mApp.options {
 		op()
 	}
 
+	services, err := resolveServices(mApp.Context.Services)
+	if err != nil {
+		return err
+	}
+	mApp.Context.Services = services
+
 	mApp.Before = mApp.before
 	mApp.Flags = append(mApp.Flags, ConfigFileFlag)
 	mApp.Flags = append(mApp.Flags, HomeDirFlag)
 	mApp.Flags = append(mApp.Flags, PprofFlag)
+	mApp.Flags = append(mApp.Flags, StopTimeoutFlag)
 
 	allCommands, allFlags := mApp.Context.AggerateFlags()
 	for i := 0; i < len(allCommands); i++ {
 		allCommands[i].Flags = append(allCommands[i].Flags, allFlags...)
 		allCommands[i].Action = mApp.action
 	}
+	configCommand := ConfigCommand
+	configCommand.Flags = append(configCommand.Flags, allFlags...)
+	configCommand.Action = mApp.configAction
+	allCommands = append(allCommands, configCommand)
 	mApp.Flags = append(mApp.Flags, allFlags...)
 	mApp.App.Commands = allCommands
 	mApp.Action = mApp.action
@@ -156,61 +151,47 @@ func (mApp *DrepApp) Run() error {
 	return nil
 }
 
-// action used to init and run each services
-func (mApp *DrepApp) action(ctx *cli.Context) error {
+// action used to init and run each services, services are stopped in reverse order when
+// the app quits, the returned error carries the exit code of the process
+func (mApp *DrepApp) action(ctx *cli.Context) (err error) {
+	initialized := 0
 	defer func() {
-		if err := recover(); err != nil {
+		if panicErr := recover(); panicErr != nil {
 			debug.PrintStack()
-			fmt.Println("app action err", err)
+			fmt.Println("app action err", panicErr)
+			err = exitError(fmt.Errorf("app action err %v", panicErr), ExitCodePanic)
+		}
+		timeout := DefaultStopTimeout
+		if ctx.GlobalIsSet(StopTimeoutFlag.Name) {
+			timeout = ctx.GlobalDuration(StopTimeoutFlag.Name)
 		}
-		length := len(mApp.Context.Services)
-		for i := length; i > 0; i-- {
-			err := mApp.Context.Services[i-1].Stop(mApp.Context)
-			if err != nil {
-				return
-			}
+		stopErr := mApp.Context.stopServices(mApp.Context.Services[:initialized], timeout)
+		if stopErr != nil && err == nil {
+			err = exitError(stopErr, stopErr.(StopErrors).ExitCode())
 		}
 	}()
 	mApp.Context.Cli = ctx //NOTE this set is for different commmands-
+	if err := mApp.Context.loadConfigs(); err != nil {
+		fmt.Println(err)
+		return exitError(err, ExitCodeInvalidConfig)
+	}
 	for _, service := range mApp.Context.Services {
-		//config
-		serviceValue := reflect.ValueOf(service)
-		serviceType := serviceValue.Type()
-		var config reflect.Value
-		fieldValue := reflect.ValueOf(service).Elem().FieldByName("Config")
-		if hasMethod(serviceType, "DefaultConfig") {
-			defaultConfigVal := serviceValue.MethodByName("DefaultConfig").Call([]reflect.Value{})
-			if len(defaultConfigVal) > 0 && !defaultConfigVal[0].IsNil() {
-				config = defaultConfigVal[0]
-			} else {
-				t := fieldValue.Type()
-				config = reflect.New(t.Elem())
-			}
-		} else {
-			t := fieldValue.Type()
-			config = reflect.New(t.Elem())
-		}
-		fieldValue.Set(config)
-		//flag
-		err := mApp.Context.UnmashalConfig(service.Name(), fieldValue.Interface())
+		err := service.Init(mApp.Context)
 		if err != nil {
-			fmt.Println("unmashalConfig", service.Name(), err)
-			return err
-		}
-		err = service.Init(mApp.Context)
-		if err != nil {
-			return err
+			return exitError(&ServiceError{service.Name(), err}, ExitCodeInitFailed)
 		}
+		initialized++
 	}
 
 	for _, service := range mApp.Context.Services {
 		err := service.Start(mApp.Context)
 		if err != nil {
-			return err
+			return exitError(&ServiceError{service.Name(), err}, ExitCodeStartFailed)
 		}
 	}
-	exit := make(chan struct{})
+	exit := make(chan struct{}, 1)
 	exitSignal(exit)
+	reloadSignal(mApp.Context)
 	select {
 	case <-exit:
 	case <-mApp.Context.Quit:
@@ -233,12 +214,13 @@ func (mApp *DrepApp) before(ctx *cli.Context) error {
 	mApp.Context.CommonConfig = &CommonConfig{
 		HomeDir: homeDir,
 	}
-	phaseConfig, err := loadConfigFile(ctx, homeDir)
+	configFile, phaseConfig, err := loadConfigFile(ctx, homeDir)
 
 	if err != nil {
 		fmt.Println("before(),loadConfigFile err:", err)
 		return err
 	}
+	mApp.Context.CommonConfig.ConfigFile = configFile
 	mApp.Context.PhaseConfig = phaseConfig
 
 	if ctx.GlobalIsSet(PprofFlag.Name) {
@@ -251,15 +233,15 @@ func (mApp *DrepApp) before(ctx *cli.Context) error {
 	return nil
 }
 
-//	loadConfigFile sed to read configuration files
-func loadConfigFile(ctx *cli.Context, homeDir string) (map[string]json.RawMessage, error) {
+//	loadConfigFile sed to read configuration files, a default one is created if not exist
+func loadConfigFile(ctx *cli.Context, homeDir string) (string, map[string]json.RawMessage, error) {
 	configFile := filepath.Join(homeDir, "config.json")
 
 	if ctx.GlobalIsSet(ConfigFileFlag.Name) {
 		file := ctx.GlobalString(ConfigFileFlag.Name)
-		if fileutil.IsFileExists(file) {
+		if !fileutil.IsFileExists(file) {
 			//report error when user specify
-			return nil, ErrConfigiNotFound
+			return "", nil, ErrConfigiNotFound
 		}
 		configFile = file
 	}
@@ -272,20 +254,29 @@ func loadConfigFile(ctx *cli.Context, homeDir string) (map[string]json.RawMessag
 		}
 		originConfigBytes, err := json.MarshalIndent(cfg, "", "\t")
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		fileutil.EnsureFile(configFile)
 		file, err := os.OpenFile(configFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0777)
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		file.Write(originConfigBytes)
+		file.Close()
+	}
+	jsonPhase, err := readConfigFile(configFile)
+	if err != nil {
+		return "", nil, err
 	}
+	return configFile, jsonPhase, nil
+}
+
+// readConfigFile parse the config file into phases by service name
+func readConfigFile(configFile string) (map[string]json.RawMessage, error) {
 	content, err := ioutil.ReadFile(configFile)
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println(string(content))
 	jsonPhase := map[string]json.RawMessage{}
 	err = json.Unmarshal(content, &jsonPhase)
 	if err != nil {
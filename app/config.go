@@ -0,0 +1,286 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	secretMask = "******"
+)
+
+var (
+	// keys of CommonConfig that may appear at the top level of config.json
+	commonConfigKeys = map[string]bool{"homeDir": true, "configFile": true}
+	// config keys whose values are not printed by the config command
+	secretKeys      = []string{"password", "passphrase", "privatekey", "secret", "token"}
+	secretExactKeys = map[string]bool{"key": true}
+
+	ConfigCommand = cli.Command{
+		Name:  "config",
+		Usage: "Print the effective config of every service and check it",
+		Description: `The config of each service is merged from its defaults, config.json and command line flags,
+printed as json and validated. Errors are reported with the json path of the invalid value and the
+command exits with a non zero code if any is found.`,
+	}
+)
+
+// FlagConfigService is implemented by services that apply their command line flags to their
+// config without other side effects. It is called before Init so that the config command can
+// show and check the effective config.
+type FlagConfigService interface {
+	Service
+	ApplyFlags(executeContext *ExecuteContext)
+}
+
+// ConfigValidator is implemented by services that check their config before the node starts
+type ConfigValidator interface {
+	Service
+	ValidateConfig(executeContext *ExecuteContext) ConfigErrors
+}
+
+// PortService reports the ports a service will listen on keyed by the json path of the setting,
+// ports used by more than one setting are reported as collision
+type PortService interface {
+	Service
+	ListenPorts() map[string]int
+}
+
+// ConfigError describes an invalid config value, Path is a json pointer into config.json
+type ConfigError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (configErr *ConfigError) Error() string {
+	return fmt.Sprintf("%s: %s", configErr.Path, configErr.Message)
+}
+
+// NewConfigError create a config error of the value at path, path elements are joined as json pointer
+func NewConfigError(message string, path ...interface{}) *ConfigError {
+	elements := make([]string, len(path))
+	for i, element := range path {
+		elements[i] = strings.Replace(strings.Replace(fmt.Sprint(element), "~", "~0", -1), "/", "~1", -1)
+	}
+	return &ConfigError{Path: "/" + strings.Join(elements, "/"), Message: message}
+}
+
+// ConfigErrors collects all errors found in config
+type ConfigErrors []*ConfigError
+
+func (configErrs ConfigErrors) Error() string {
+	msgs := make([]string, len(configErrs))
+	for i, configErr := range configErrs {
+		msgs[i] = configErr.Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// loadConfigs fill the Config field of every service from defaults, config file and flags,
+// then validate them. All errors found are returned together.
+func (econtext *ExecuteContext) loadConfigs() error {
+	var configErrs ConfigErrors
+	for name := range econtext.PhaseConfig {
+		if !commonConfigKeys[name] && econtext.GetService(name) == nil {
+			configErrs = append(configErrs, NewConfigError("unknown service", name))
+		}
+	}
+	for _, service := range econtext.Services {
+		configErrs = append(configErrs, econtext.loadServiceConfig(service)...)
+		if flagService, ok := service.(FlagConfigService); ok {
+			flagService.ApplyFlags(econtext)
+		}
+	}
+	for _, service := range econtext.Services {
+		if validator, ok := service.(ConfigValidator); ok {
+			configErrs = append(configErrs, validator.ValidateConfig(econtext)...)
+		}
+	}
+	configErrs = append(configErrs, econtext.checkPorts()...)
+
+	if len(configErrs) == 0 {
+		return nil
+	}
+	sort.SliceStable(configErrs, func(i, j int) bool {
+		return configErrs[i].Path < configErrs[j].Path
+	})
+	return configErrs
+}
+
+// loadServiceConfig set the Config field of service with its default config overridden by its phase
+// in config file. Unknown keys and values of wrong type are reported.
+func (econtext *ExecuteContext) loadServiceConfig(service Service) ConfigErrors {
+	serviceValue := reflect.ValueOf(service)
+	serviceType := serviceValue.Type()
+	var config reflect.Value
+	fieldValue := serviceValue.Elem().FieldByName("Config")
+	if !fieldValue.IsValid() {
+		return nil
+	}
+	if hasMethod(serviceType, "DefaultConfig") {
+		defaultConfigVal := serviceValue.MethodByName("DefaultConfig").Call([]reflect.Value{})
+		if len(defaultConfigVal) > 0 && !defaultConfigVal[0].IsNil() {
+			config = defaultConfigVal[0]
+		} else {
+			config = reflect.New(fieldValue.Type().Elem())
+		}
+	} else {
+		config = reflect.New(fieldValue.Type().Elem())
+	}
+	fieldValue.Set(config)
+
+	phase := econtext.GetConfig(service.Name())
+	if phase == nil {
+		return nil
+	}
+	if err := json.Unmarshal(phase, fieldValue.Interface()); err != nil {
+		return ConfigErrors{jsonConfigError(service.Name(), err)}
+	}
+	// decode again into an empty config to find the keys not matching any field
+	decoder := json.NewDecoder(bytes.NewReader(phase))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(reflect.New(fieldValue.Type().Elem()).Interface()); err != nil {
+		return ConfigErrors{jsonConfigError(service.Name(), err)}
+	}
+	return nil
+}
+
+// jsonConfigError convert the error of decoding a config phase into config error
+func jsonConfigError(serviceName string, err error) *ConfigError {
+	switch jsonErr := err.(type) {
+	case *json.UnmarshalTypeError:
+		path := []interface{}{serviceName}
+		if jsonErr.Field != "" {
+			for _, element := range strings.Split(jsonErr.Field, ".") {
+				path = append(path, element)
+			}
+		}
+		return NewConfigError(fmt.Sprintf("expect %s, got %s", jsonErr.Type.String(), jsonErr.Value), path...)
+	case *json.SyntaxError:
+		return NewConfigError(fmt.Sprintf("invalid json at offset %d: %s", jsonErr.Offset, jsonErr.Error()), serviceName)
+	default:
+		return NewConfigError(strings.TrimPrefix(err.Error(), "json: "), serviceName)
+	}
+}
+
+// checkPorts report ports used by more than one setting
+func (econtext *ExecuteContext) checkPorts() ConfigErrors {
+	used := map[int][]string{}
+	for _, service := range econtext.Services {
+		portService, ok := service.(PortService)
+		if !ok {
+			continue
+		}
+		for path, port := range portService.ListenPorts() {
+			if port == 0 {
+				continue
+			}
+			used[port] = append(used[port], path)
+		}
+	}
+	var configErrs ConfigErrors
+	for port, paths := range used {
+		if len(paths) < 2 {
+			continue
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			others := []string{}
+			for _, other := range paths {
+				if other != path {
+					others = append(others, other)
+				}
+			}
+			configErrs = append(configErrs, &ConfigError{
+				Path:    path,
+				Message: fmt.Sprintf("port %d collides with %s", port, strings.Join(others, ", ")),
+			})
+		}
+	}
+	return configErrs
+}
+
+// EffectiveConfig return the config of every service by service name with secrets masked
+func (econtext *ExecuteContext) EffectiveConfig() (map[string]interface{}, error) {
+	configs := map[string]interface{}{}
+	for _, service := range econtext.Services {
+		fieldValue := reflect.ValueOf(service).Elem().FieldByName("Config")
+		if !fieldValue.IsValid() || fieldValue.IsNil() {
+			continue
+		}
+		content, err := json.Marshal(fieldValue.Interface())
+		if err != nil {
+			return nil, err
+		}
+		var config interface{}
+		if err := json.Unmarshal(content, &config); err != nil {
+			return nil, err
+		}
+		configs[service.Name()] = maskSecrets(config)
+	}
+	return configs, nil
+}
+
+// maskSecrets replace the values of secret keys so they are not printed
+func maskSecrets(value interface{}) interface{} {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		for key, item := range val {
+			if isSecretKey(key) && item != nil {
+				val[key] = secretMask
+				continue
+			}
+			val[key] = maskSecrets(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = maskSecrets(item)
+		}
+	}
+	return value
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	if secretExactKeys[key] {
+		return true
+	}
+	for _, secretKey := range secretKeys {
+		if strings.Contains(key, secretKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// configAction print the effective config and the errors found in it
+func (mApp *DrepApp) configAction(ctx *cli.Context) error {
+	mApp.Context.Cli = ctx
+	loadErr := mApp.Context.loadConfigs()
+
+	configs, err := mApp.Context.EffectiveConfig()
+	if err != nil {
+		return exitError(err, ExitCodeInvalidConfig)
+	}
+	content, err := json.MarshalIndent(configs, "", "\t")
+	if err != nil {
+		return exitError(err, ExitCodeInvalidConfig)
+	}
+	fmt.Println(string(content))
+
+	if loadErr != nil {
+		if configErrs, ok := loadErr.(ConfigErrors); ok {
+			content, _ := json.MarshalIndent(configErrs, "", "\t")
+			fmt.Println(string(content))
+		}
+		return exitError(loadErr, ExitCodeInvalidConfig)
+	}
+	fmt.Println("config is valid")
+	return nil
+}
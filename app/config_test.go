@@ -0,0 +1,66 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testConfig struct {
+	Port     int    `json:"port"`
+	Password string `json:"password"`
+}
+
+type configService struct {
+	testService
+	Config *testConfig
+}
+
+func (service *configService) ListenPorts() map[string]int {
+	return map[string]int{"/" + service.name + "/port": service.Config.Port}
+}
+
+func TestLoadConfigs(t *testing.T) {
+	econtext := &ExecuteContext{
+		PhaseConfig: map[string]json.RawMessage{
+			"homeDir": json.RawMessage(`"/tmp"`),
+			"a":       json.RawMessage(`{"port":80,"password":"123"}`),
+			"b":       json.RawMessage(`{"port":80,"pasword":"123"}`),
+			"c":       json.RawMessage(`{"port":"80"}`),
+			"d":       json.RawMessage(`{}`),
+		},
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		econtext.AddService(&configService{testService: testService{name: name}})
+	}
+
+	err := econtext.loadConfigs()
+	configErrs, ok := err.(ConfigErrors)
+	if !ok {
+		t.Fatalf("expect config errors, got %v", err)
+	}
+	expect := []string{"/a/port", "/b", "/b/port", "/c/port", "/d"}
+	if len(configErrs) != len(expect) {
+		t.Fatalf("expect %d errors, got %v", len(expect), configErrs)
+	}
+	for i, path := range expect {
+		if configErrs[i].Path != path {
+			t.Errorf("expect error at %s, got %s", path, configErrs[i].Error())
+		}
+	}
+
+	configs, err := econtext.EffectiveConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := configs["a"].(map[string]interface{})
+	if a["password"] != secretMask || a["port"] != float64(80) {
+		t.Errorf("unexpected effective config %v", a)
+	}
+}
+
+func TestNewConfigError(t *testing.T) {
+	configErr := NewConfigError("invalid", "consensus", "producers", 1, "a/b")
+	if configErr.Path != "/consensus/producers/1/a~1b" {
+		t.Errorf("unexpected path %s", configErr.Path)
+	}
+}
@@ -2,6 +2,8 @@ package app
 
 import (
 	"encoding/json"
+	"sync"
+
 	"github.com/asaskevich/EventBus"
 	"github.com/pkg/errors"
 	"gopkg.in/urfave/cli.v1"
@@ -76,6 +78,9 @@ type ExecuteContext struct {
 	GitCommit string
 	Usage     string
 	Quit      chan struct{}
+
+	configLock sync.RWMutex
+	reloadLock sync.Mutex
 }
 
 // AddService add a service to context, The application then initializes and starts the service.
@@ -98,6 +103,8 @@ func (econtext *ExecuteContext) GetService(name string) Service {
 //	each service only needs to obtain its own configuration data,
 //	and the parsing process is also controlled by each service itself.
 func (econtext *ExecuteContext) GetConfig(phaseName string) json.RawMessage {
+	econtext.configLock.RLock()
+	defer econtext.configLock.RUnlock()
 	phaseConfig, ok := econtext.PhaseConfig[phaseName]
 	if ok {
 		return phaseConfig
@@ -0,0 +1,181 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// optionalTag marks a dependency that may be absent, eg `service:"trace,optional"`
+	optionalTag = "optional"
+)
+
+var (
+	registerLock       sync.Mutex
+	registeredServices []interface{}
+)
+
+// RegisterService make services available to every app created afterwards, third-party
+// packages call it in their init function so they only need to be imported by main.
+func RegisterService(serviceInstances ...interface{}) {
+	nilService := reflect.TypeOf((*Service)(nil)).Elem()
+	registerLock.Lock()
+	defer registerLock.Unlock()
+	for _, serviceInstance := range serviceInstances {
+		serviceType := reflect.TypeOf(serviceInstance)
+		if serviceType.Kind() == reflect.Ptr {
+			serviceType = serviceType.Elem()
+		}
+		if !reflect.PtrTo(serviceType).Implements(nilService) {
+			panic(errors.Wrapf(ErrNotMatchedService, "register %s", serviceType.String()))
+		}
+		registeredServices = append(registeredServices, serviceInstance)
+	}
+}
+
+// serviceDependency is a field of service that refers another service by tag
+type serviceDependency struct {
+	fieldIndex int
+	fieldName  string
+	name       string
+	optional   bool
+}
+
+// serviceNode is a vertex in the dependency graph
+type serviceNode struct {
+	service      Service
+	value        reflect.Value
+	dependencies []serviceDependency
+}
+
+// parseServiceTag read the name and options in service tag
+func parseServiceTag(field reflect.StructField) (string, bool) {
+	serviceTagStr := field.Tag.Get("service")
+	if serviceTagStr == "" {
+		return "", false
+	}
+	parts := strings.Split(serviceTagStr, ",")
+	optional := false
+	for _, option := range parts[1:] {
+		if strings.TrimSpace(option) == optionalTag {
+			optional = true
+		}
+	}
+	return strings.TrimSpace(parts[0]), optional
+}
+
+func newServiceNode(service Service) *serviceNode {
+	value := reflect.ValueOf(service)
+	node := &serviceNode{service: service, value: value}
+	serviceType := value.Type().Elem()
+	for i := 0; i < serviceType.NumField(); i++ {
+		field := serviceType.Field(i)
+		name, optional := parseServiceTag(field)
+		if name == "" {
+			continue
+		}
+		node.dependencies = append(node.dependencies, serviceDependency{
+			fieldIndex: i,
+			fieldName:  field.Name,
+			name:       name,
+			optional:   optional,
+		})
+	}
+	return node
+}
+
+// resolveServices build the dependency graph of services from their tags, sort services so that
+// every service is behind its dependencies and set the dependency fields.
+// Duplicate names, missing required dependencies, unassignable fields and cycles are errors.
+func resolveServices(services []Service) ([]Service, error) {
+	nodes := make(map[string]*serviceNode, len(services))
+	order := make([]string, 0, len(services))
+	for _, service := range services {
+		name := service.Name()
+		if _, ok := nodes[name]; ok {
+			return nil, errors.Wrap(ErrDuplicateService, name)
+		}
+		nodes[name] = newServiceNode(service)
+		order = append(order, name)
+	}
+
+	// in-degree counts the distinct services each service depends on
+	inDegree := make(map[string]int, len(nodes))
+	dependents := make(map[string][]string, len(nodes))
+	for _, name := range order {
+		node := nodes[name]
+		seen := map[string]bool{}
+		for _, dep := range node.dependencies {
+			depNode, ok := nodes[dep.name]
+			if !ok {
+				if dep.optional {
+					continue
+				}
+				return nil, errors.Wrapf(ErrServiceDependencyMissing, "%s require %s", name, dep.name)
+			}
+			fieldType := node.value.Elem().Field(dep.fieldIndex).Type()
+			if !depNode.value.Type().AssignableTo(fieldType) {
+				return nil, errors.Wrapf(ErrServiceTypeMismatch, "%s.%s(%s) can not be set by %s", name, dep.fieldName, fieldType.String(), depNode.value.Type().String())
+			}
+			if dep.name == name {
+				return nil, errors.Wrapf(ErrServiceCycle, "%s depends on itself", name)
+			}
+			if !seen[dep.name] {
+				seen[dep.name] = true
+				inDegree[name]++
+				dependents[dep.name] = append(dependents[dep.name], name)
+			}
+		}
+	}
+
+	// Kahn's algorithm, services are picked in registration order when several are ready so
+	// that a valid manual order is kept as it is
+	sorted := make([]Service, 0, len(services))
+	done := make(map[string]bool, len(nodes))
+	for len(sorted) < len(order) {
+		progress := false
+		for _, name := range order {
+			if done[name] || inDegree[name] > 0 {
+				continue
+			}
+			done[name] = true
+			progress = true
+			sorted = append(sorted, nodes[name].service)
+			for _, dependent := range dependents[name] {
+				inDegree[dependent]--
+			}
+			break
+		}
+		if !progress {
+			cycle := []string{}
+			for _, name := range order {
+				if !done[name] {
+					cycle = append(cycle, name)
+				}
+			}
+			return nil, errors.Wrapf(ErrServiceCycle, "between %s", strings.Join(cycle, ", "))
+		}
+	}
+
+	for _, service := range sorted {
+		node := nodes[service.Name()]
+		for _, dep := range node.dependencies {
+			depNode, ok := nodes[dep.name]
+			if !ok {
+				continue
+			}
+			//TODO the filed to be set must be set public field, but it wiil be better to set it as a private field ,
+			//TODO UnsafePointer may help
+			field := node.value.Elem().Field(dep.fieldIndex)
+			if !field.CanSet() {
+				return nil, fmt.Errorf("field %s of service %s must be exported", dep.fieldName, service.Name())
+			}
+			field.Set(depNode.value)
+		}
+	}
+	return sorted, nil
+}
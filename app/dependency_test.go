@@ -0,0 +1,61 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+type depService struct {
+	name string
+	A    Service `service:"a"`
+	B    Service `service:"b,optional"`
+}
+
+func (service *depService) Name() string                               { return service.name }
+func (service *depService) Api() []API                                 { return nil }
+func (service *depService) CommandFlags() ([]cli.Command, []cli.Flag)  { return nil, nil }
+func (service *depService) Init(executeContext *ExecuteContext) error  { return nil }
+func (service *depService) Start(executeContext *ExecuteContext) error { return nil }
+func (service *depService) Stop(executeContext *ExecuteContext) error  { return nil }
+
+type rootService struct {
+	testService
+}
+
+func TestResolveServicesOrder(t *testing.T) {
+	a := &rootService{testService{name: "a"}}
+	c := &depService{name: "c"}
+	services, err := resolveServices([]Service{c, a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if services[0] != a || services[1] != c {
+		t.Fatalf("dependency not sorted before dependent")
+	}
+	if c.A != a {
+		t.Errorf("dependency field not set")
+	}
+	if c.B != nil {
+		t.Errorf("missing optional dependency should be nil")
+	}
+}
+
+func TestResolveServicesMissing(t *testing.T) {
+	_, err := resolveServices([]Service{&depService{name: "c"}})
+	if errors.Cause(err) != ErrServiceDependencyMissing {
+		t.Fatalf("expect missing dependency error, got %v", err)
+	}
+}
+
+func TestResolveServicesCycle(t *testing.T) {
+	_, err := resolveServices([]Service{&depService{name: "a"}})
+	if errors.Cause(err) != ErrServiceCycle {
+		t.Fatalf("expect cycle error, got %v", err)
+	}
+	_, err = resolveServices([]Service{&depService{name: "b"}, &depService{name: "a"}})
+	if errors.Cause(err) != ErrServiceCycle {
+		t.Fatalf("expect cycle error, got %v", err)
+	}
+}
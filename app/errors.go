@@ -6,4 +6,11 @@ var (
 	ErrNotMatchedService = errors.New("the service added not match service interface")
 	ErrConfigiNotFound   = errors.New("specify config file not exist")
 	ErrServiceNotFound   = errors.New("Service not found")
+	ErrStopTimeout       = errors.New("service stop timeout")
+	ErrStopSkipped       = errors.New("service stop skipped")
+
+	ErrDuplicateService         = errors.New("duplicate service name")
+	ErrServiceDependencyMissing = errors.New("service dependency not exist")
+	ErrServiceTypeMismatch      = errors.New("service dependency type not match field")
+	ErrServiceCycle             = errors.New("service dependency cycle")
 )
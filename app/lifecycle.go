@@ -0,0 +1,181 @@
+package app
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// exit codes reported by the process, so supervisors can tell why the node stopped
+const (
+	ExitCodeOK            = 0
+	ExitCodeInitFailed    = 1
+	ExitCodeStartFailed   = 2
+	ExitCodeStopFailed    = 3
+	ExitCodeStopTimeout   = 4
+	ExitCodePanic         = 5
+	ExitCodeInvalidConfig = 6
+	ExitCodeForceShutdown = 130
+)
+
+var (
+	DefaultStopTimeout = 30 * time.Second
+
+	StopTimeoutFlag = cli.DurationFlag{
+		Name:  "stoptimeout",
+		Usage: "Deadline for each service to stop during shutdown",
+		Value: DefaultStopTimeout,
+	}
+)
+
+// ServiceError records the error a service returned in one lifecycle phase
+type ServiceError struct {
+	Service string
+	Err     error
+}
+
+func (serviceErr *ServiceError) Error() string {
+	return fmt.Sprintf("%s: %v", serviceErr.Service, serviceErr.Err)
+}
+
+// StopErrors collects the errors of all services failing to stop
+type StopErrors []*ServiceError
+
+func (stopErrs StopErrors) Error() string {
+	msgs := make([]string, len(stopErrs))
+	for i, serviceErr := range stopErrs {
+		msgs[i] = serviceErr.Error()
+	}
+	return "stop services fail: " + strings.Join(msgs, "; ")
+}
+
+// ExitCode return ExitCodeStopTimeout if any service missed its deadline, otherwise ExitCodeStopFailed
+func (stopErrs StopErrors) ExitCode() int {
+	for _, serviceErr := range stopErrs {
+		if serviceErr.Err == ErrStopTimeout {
+			return ExitCodeStopTimeout
+		}
+	}
+	return ExitCodeStopFailed
+}
+
+// stopServices stops services in reverse order. Every service is stopped even if some of
+// them fail, each one gets timeout to finish and the errors are collected. A service whose Stop
+// missed its deadline may still use its dependencies, they are only stopped once it returns:
+// they wait another timeout for it and are skipped if it is still running.
+func (econtext *ExecuteContext) stopServices(services []Service, timeout time.Duration) error {
+	var (
+		stopErrs     StopErrors
+		dependencies = serviceDependencies(services)
+		running      = map[string]<-chan error{}
+	)
+	for i := len(services); i > 0; i-- {
+		service := services[i-1]
+		if dependent := waitDependents(service.Name(), running, dependencies, timeout); dependent != "" {
+			fmt.Println("skip stopping service", service.Name(), "while", dependent, "is stopping")
+			stopErrs = append(stopErrs, &ServiceError{service.Name(), errors.Wrapf(ErrStopSkipped, "%s is still stopping", dependent)})
+			continue
+		}
+		start := time.Now()
+		done := stopService(econtext, service)
+		err := waitStop(done, timeout)
+		if err == ErrStopTimeout {
+			running[service.Name()] = done
+		}
+		if err != nil {
+			fmt.Println("stop service", service.Name(), "err:", err)
+			stopErrs = append(stopErrs, &ServiceError{service.Name(), err})
+			continue
+		}
+		fmt.Println("stop service", service.Name(), "cost", time.Since(start))
+	}
+	if len(stopErrs) == 0 {
+		return nil
+	}
+	return stopErrs
+}
+
+// serviceDependencies return the names of the services each service depends on, directly or
+// through other services
+func serviceDependencies(services []Service) map[string]map[string]bool {
+	direct := map[string][]string{}
+	for _, service := range services {
+		for _, dep := range newServiceNode(service).dependencies {
+			direct[service.Name()] = append(direct[service.Name()], dep.name)
+		}
+	}
+	all := map[string]map[string]bool{}
+	for _, service := range services {
+		deps := map[string]bool{}
+		queue := append([]string{}, direct[service.Name()]...)
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			if deps[name] {
+				continue
+			}
+			deps[name] = true
+			queue = append(queue, direct[name]...)
+		}
+		all[service.Name()] = deps
+	}
+	return all
+}
+
+// waitDependents wait for the timed out Stop calls of the services depending on name, it returns
+// the first one still running after timeout, empty if all of them returned
+func waitDependents(name string, running map[string]<-chan error, dependencies map[string]map[string]bool, timeout time.Duration) string {
+	for dependent, done := range running {
+		if !dependencies[dependent][name] {
+			continue
+		}
+		if waitStop(done, timeout) == ErrStopTimeout {
+			return dependent
+		}
+		delete(running, dependent)
+	}
+	return ""
+}
+
+// stopService calls Stop of service in a goroutine, a panic in Stop is reported as error. The
+// returned channel receives the result of Stop.
+func stopService(econtext *ExecuteContext, service Service) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				debug.PrintStack()
+				done <- fmt.Errorf("panic while stopping: %v", err)
+			}
+		}()
+		done <- service.Stop(econtext)
+	}()
+	return done
+}
+
+// waitStop return the result of a Stop call, ErrStopTimeout if it does not return before timeout
+func waitStop(done <-chan error, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return ErrStopTimeout
+	}
+}
+
+// exitError wraps err with the exit code of the process
+func exitError(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(cli.ExitCoder); ok {
+		return err
+	}
+	return cli.NewExitError(err.Error(), code)
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+)
+
+type testService struct {
+	name    string
+	stopErr error
+	block   bool
+	stopped *[]string
+}
+
+func (service *testService) Name() string                               { return service.name }
+func (service *testService) Api() []API                                 { return nil }
+func (service *testService) CommandFlags() ([]cli.Command, []cli.Flag)  { return nil, nil }
+func (service *testService) Init(executeContext *ExecuteContext) error  { return nil }
+func (service *testService) Start(executeContext *ExecuteContext) error { return nil }
+func (service *testService) Stop(executeContext *ExecuteContext) error {
+	if service.block {
+		time.Sleep(time.Second)
+		return nil
+	}
+	*service.stopped = append(*service.stopped, service.name)
+	return service.stopErr
+}
+
+func TestStopServices(t *testing.T) {
+	stopped := []string{}
+	econtext := &ExecuteContext{}
+	services := []Service{
+		&testService{name: "a", stopped: &stopped},
+		&testService{name: "b", stopped: &stopped, stopErr: errors.New("b fail")},
+		&testService{name: "c", stopped: &stopped},
+	}
+
+	err := econtext.stopServices(services, time.Second)
+	if len(stopped) != 3 || stopped[0] != "c" || stopped[1] != "b" || stopped[2] != "a" {
+		t.Fatalf("services not stopped in reverse order: %v", stopped)
+	}
+	stopErrs, ok := err.(StopErrors)
+	if !ok || len(stopErrs) != 1 || stopErrs[0].Service != "b" {
+		t.Fatalf("unexpected stop error %v", err)
+	}
+	if stopErrs.ExitCode() != ExitCodeStopFailed {
+		t.Errorf("expect exit code %d, got %d", ExitCodeStopFailed, stopErrs.ExitCode())
+	}
+}
+
+func TestStopServiceTimeout(t *testing.T) {
+	stopped := []string{}
+	econtext := &ExecuteContext{}
+	services := []Service{
+		&testService{name: "a", stopped: &stopped},
+		&testService{name: "slow", stopped: &stopped, block: true},
+	}
+
+	err := econtext.stopServices(services, 10*time.Millisecond)
+	stopErrs, ok := err.(StopErrors)
+	if !ok || len(stopErrs) != 1 || stopErrs[0].Err != ErrStopTimeout {
+		t.Fatalf("expect timeout error, got %v", err)
+	}
+	if stopErrs.ExitCode() != ExitCodeStopTimeout {
+		t.Errorf("expect exit code %d, got %d", ExitCodeStopTimeout, stopErrs.ExitCode())
+	}
+}
+
+// testDependentService stops slowly and depends on the service named "a"
+type testDependentService struct {
+	testService
+	A *testService `service:"a"`
+}
+
+func TestStopServiceTimeoutKeepsDependencies(t *testing.T) {
+	stopped := []string{}
+	econtext := &ExecuteContext{}
+	services := []Service{
+		&testService{name: "a", stopped: &stopped},
+		&testService{name: "b", stopped: &stopped},
+		&testDependentService{testService: testService{name: "slow", stopped: &stopped, block: true}},
+	}
+
+	err := econtext.stopServices(services, 10*time.Millisecond)
+	if len(stopped) != 1 || stopped[0] != "b" {
+		t.Fatalf("dependency of a stopping service stopped: %v", stopped)
+	}
+	stopErrs, ok := err.(StopErrors)
+	if !ok || len(stopErrs) != 2 || stopErrs[0].Err != ErrStopTimeout || errors.Cause(stopErrs[1].Err) != ErrStopSkipped {
+		t.Fatalf("expect timeout and skip errors, got %v", err)
+	}
+
+	// a dependency is stopped once the slow service returns within the extra wait
+	stopped = stopped[:0]
+	err = econtext.stopServices(services, 600*time.Millisecond)
+	if len(stopped) != 2 || stopped[1] != "a" {
+		t.Fatalf("dependency not stopped after the slow service returned: %v, %v", stopped, err)
+	}
+}
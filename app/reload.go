@@ -0,0 +1,76 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ReloadableService is implemented by services that can apply a changed configuration
+// while running. Reload receives the new config phase of the service, or nil if the phase
+// was removed from the config file, and is called only when the phase changed.
+type ReloadableService interface {
+	Service
+	Reload(executeContext *ExecuteContext, phase json.RawMessage) error
+}
+
+// ReloadErrors collects the errors of all services failing to apply a new config
+type ReloadErrors []*ServiceError
+
+func (reloadErrs ReloadErrors) Error() string {
+	msgs := make([]string, len(reloadErrs))
+	for i, serviceErr := range reloadErrs {
+		msgs[i] = serviceErr.Error()
+	}
+	return "reload services fail: " + strings.Join(msgs, "; ")
+}
+
+// ReloadConfig read the config file again and hand the changed phases to the services
+// implementing ReloadableService. It returns the names of the services reloaded, a service
+// failing to reload does not stop the others.
+func (econtext *ExecuteContext) ReloadConfig() ([]string, error) {
+	econtext.reloadLock.Lock()
+	defer econtext.reloadLock.Unlock()
+
+	if econtext.CommonConfig == nil || econtext.CommonConfig.ConfigFile == "" {
+		return nil, ErrConfigiNotFound
+	}
+	phaseConfig, err := readConfigFile(econtext.CommonConfig.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	oldPhaseConfig := econtext.setPhaseConfig(phaseConfig)
+
+	reloaded := []string{}
+	var reloadErrs ReloadErrors
+	for _, service := range econtext.Services {
+		reloadable, ok := service.(ReloadableService)
+		if !ok {
+			continue
+		}
+		phase := phaseConfig[service.Name()]
+		if bytes.Equal(phase, oldPhaseConfig[service.Name()]) {
+			continue
+		}
+		if err := reloadable.Reload(econtext, phase); err != nil {
+			fmt.Println("reload service", service.Name(), "err:", err)
+			reloadErrs = append(reloadErrs, &ServiceError{service.Name(), err})
+			continue
+		}
+		reloaded = append(reloaded, service.Name())
+	}
+	if len(reloadErrs) > 0 {
+		return reloaded, reloadErrs
+	}
+	return reloaded, nil
+}
+
+// setPhaseConfig replace the config phases and return the old ones
+func (econtext *ExecuteContext) setPhaseConfig(phaseConfig map[string]json.RawMessage) map[string]json.RawMessage {
+	econtext.configLock.Lock()
+	defer econtext.configLock.Unlock()
+	old := econtext.PhaseConfig
+	econtext.PhaseConfig = phaseConfig
+	return old
+}
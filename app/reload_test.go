@@ -0,0 +1,57 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type reloadService struct {
+	testService
+	phases []json.RawMessage
+}
+
+func (service *reloadService) Reload(executeContext *ExecuteContext, phase json.RawMessage) error {
+	service.phases = append(service.phases, phase)
+	return nil
+}
+
+func TestReloadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "config.json")
+
+	a := &reloadService{testService: testService{name: "a"}}
+	b := &reloadService{testService: testService{name: "b"}}
+	econtext := &ExecuteContext{
+		CommonConfig: &CommonConfig{ConfigFile: configFile},
+		PhaseConfig: map[string]json.RawMessage{
+			"a": json.RawMessage(`{"level":1}`),
+			"b": json.RawMessage(`{"level":1}`),
+		},
+		Services: []Service{a, b, &testService{name: "c"}},
+	}
+
+	content := `{"a":{"level":1},"b":{"level":2},"c":{"level":2}}`
+	if err := ioutil.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := econtext.ReloadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded) != 1 || reloaded[0] != "b" {
+		t.Fatalf("expect only b reloaded, got %v", reloaded)
+	}
+	if len(a.phases) != 0 || len(b.phases) != 1 || string(b.phases[0]) != `{"level":2}` {
+		t.Errorf("unexpected phases a:%v b:%v", a.phases, b.phases)
+	}
+	if string(econtext.GetConfig("c")) != `{"level":2}` {
+		t.Errorf("phase config not replaced")
+	}
+}
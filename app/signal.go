@@ -1,21 +1,41 @@
 package app
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 )
 
+// exitSignal notify ch on the first interrupt or termination signal, services are then stopped
+// in order. A second signal forces the process to exit without waiting for the services.
+// SIGKILL and SIGSEGV are not listed as they cannot be caught.
 func exitSignal(ch chan struct{}) {
-	c := make(chan os.Signal)
-	signal.Notify(c, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGKILL, syscall.SIGSEGV)
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	go func() {
+		s := <-c
+		fmt.Println("receive signal", s, ", shutting down")
+		ch <- struct{}{}
 		for s := range c {
-			switch s {
-			case syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGKILL, syscall.SIGSEGV:
-				ch <- struct{}{}
-			default:
+			fmt.Println("receive signal", s, "again, force shutdown")
+			os.Exit(ExitCodeForceShutdown)
+		}
+	}()
+}
+
+// reloadSignal reload the config file each time SIGHUP is received
+func reloadSignal(econtext *ExecuteContext) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	go func() {
+		for s := range c {
+			fmt.Println("receive signal", s, ", reloading config")
+			reloaded, err := econtext.ReloadConfig()
+			if err != nil {
+				fmt.Println("reload config err:", err)
 			}
+			fmt.Println("services reloaded:", reloaded)
 		}
 	}()
 }
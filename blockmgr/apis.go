@@ -46,6 +46,28 @@ func (blockMgrApi *BlockMgrApi) SendRawTransaction(txbytes common.Bytes) (string
 	return tx.TxHash().String(), err
 }
 
+/*
+ name: buildTransaction
+ usage: 构建待离线签名的交易，未填写的nonce、gas价格和gas上限由节点补全，链id为本链id。签名后通过sendRawTransaction发送
+ params:
+	1. 交易参数
+		type: 交易类型 transfer|call|createCode|setAlias
+		from: 发送者地址
+		to: 接收者或合约地址（transfer和call必填）
+		amount: 金额（可选）
+		gasPrice: gas价格（可选）
+		gasLimit: gas上限（可选）
+		nonce: 交易序号（可选）
+		data: 调用参数、合约代码或别名
+ return: 待签名交易，包括发送者、交易数据和待签名的hash
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"blockmgr_buildTransaction","params":[{"type":"transfer","from":"0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","to":"0x3296d3336895b5baaa0eca3df911741bd0681c3f","amount":"0x111"}], "id": 3}' -H "Content-Type:application/json"
+ response:
+	{"jsonrpc":"2.0","id":3,"result":{"from":"0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","data":{"Version":1,"Nonce":12,"Type":0,"To":"0x3296d3336895b5baaa0eca3df911741bd0681c3f","ChainId":0,"Amount":"0x111","GasPrice":"0x110","GasLimit":"0x7530","Timestamp":1560403673,"Data":null},"hash":"0x3a3b59f90a21c2fd1b690aa3a2bc06dc2d40eb5bdc26fdd7ecb7e1105af2638e"}}
+*/
+func (blockMgrApi *BlockMgrApi) BuildTransaction(args BuildTxArgs) (*types.UnsignedTransaction, error) {
+	return blockMgrApi.blockMgr.BuildTransaction(&args)
+}
+
 func (blockMgrApi *BlockMgrApi) GasPrice() (*big.Int, error) {
 	return blockMgrApi.blockMgr.gpo.SuggestPrice()
 }
@@ -1,6 +1,7 @@
 package blockmgr
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"math/rand"
@@ -38,6 +39,7 @@ var (
 	DefaultChainConfig = &BlockMgrConfig{
 		GasPrice:    DefaultOracleConfig,
 		JournalFile: "txpool/txs",
+		TxPool:      txpool.DefaultTxPoolConfig,
 	}
 	span = uint64(params.MaxGasLimit / 360)
 	_    = IBlockMgr((*BlockMgr)(nil)) //compile check
@@ -68,6 +70,7 @@ type IBlockNotify interface {
 	//notify
 	SubscribeSyncBlockEvent(subchan chan event.SyncBlockEvent) event.Subscription
 	NewTxFeed() *event.Feed
+	IsSyncing() bool
 }
 
 type ISendMessage interface {
@@ -153,6 +156,7 @@ func NewBlockMgr(config *BlockMgrConfig, homeDir string, cs chain.ChainServiceIn
 
 	//TODO use disk db
 	blockMgr.transactionPool = txpool.NewTransactionPool(blockMgr.ChainService.GetDatabaseService().Db(), path.Join(homeDir, blockMgr.Config.JournalFile))
+	blockMgr.transactionPool.SetConfig(blockMgr.Config.TxPool)
 
 	blockMgr.P2pServer.AddProtocols([]p2p.Protocol{
 		p2p.Protocol{
@@ -198,6 +202,7 @@ func (blockMgr *BlockMgr) Init(executeContext *app.ExecuteContext) error {
 
 	//TODO use disk db
 	blockMgr.transactionPool = txpool.NewTransactionPool(blockMgr.ChainService.GetDatabaseService().Db(), path.Join(executeContext.CommonConfig.HomeDir, blockMgr.Config.JournalFile))
+	blockMgr.transactionPool.SetConfig(blockMgr.Config.TxPool)
 
 	blockMgr.P2pServer.AddProtocols([]p2p.Protocol{
 		p2p.Protocol{
@@ -240,6 +245,29 @@ func (blockMgr *BlockMgr) Stop(executeContext *app.ExecuteContext) error {
 	if blockMgr.quit != nil {
 		close(blockMgr.quit)
 	}
+	if blockMgr.transactionPool != nil {
+		return blockMgr.transactionPool.Stop()
+	}
+	return nil
+}
+
+// Reload apply the gas price oracle parameters and txpool limits of a changed config,
+// the journal file takes effect after restart
+func (blockMgr *BlockMgr) Reload(executeContext *app.ExecuteContext, phase json.RawMessage) error {
+	config := *blockMgr.Config
+	config.GasPrice = DefaultOracleConfig
+	config.TxPool = txpool.DefaultTxPoolConfig
+	if phase != nil {
+		if err := json.Unmarshal(phase, &config); err != nil {
+			return err
+		}
+	}
+	config.JournalFile = blockMgr.Config.JournalFile
+
+	blockMgr.gpo.SetConfig(config.GasPrice)
+	blockMgr.transactionPool.SetConfig(config.TxPool)
+	blockMgr.Config = &config
+	log.WithField("gasprice", config.GasPrice).WithField("txpool", config.TxPool).Info("blockmgr config reloaded")
 	return nil
 }
 
@@ -321,6 +349,18 @@ func (blockMgr *BlockMgr) SubscribeSyncBlockEvent(subchan chan event.SyncBlockEv
 	return blockMgr.syncBlockEvent.Subscribe(subchan)
 }
 
+// IsSyncing reports whether blocks are being fetched or the best peer is ahead of the local chain
+func (blockMgr *BlockMgr) IsSyncing() bool {
+	if blockMgr.state == event.StartSyncBlock {
+		return true
+	}
+	pi := blockMgr.GetBestPeerInfo()
+	if pi == nil {
+		return false
+	}
+	return pi.GetHeight() > blockMgr.ChainService.BestChain().Height()
+}
+
 func (blockMgr *BlockMgr) NewTxFeed() *event.Feed {
 	return blockMgr.transactionPool.NewTxFeed()
 }
@@ -1,8 +1,11 @@
 package blockmgr
 
+import "github.com/drep-project/DREP-Chain/blockmgr/txpool"
+
 type BlockMgrConfig struct {
-	GasPrice    OracleConfig `json:"gasprice"`
-	JournalFile string       `json:"journalFile"`
+	GasPrice    OracleConfig        `json:"gasprice"`
+	JournalFile string              `json:"journalFile"`
+	TxPool      txpool.TxPoolConfig `json:"txpool"`
 }
 
 type OracleConfig struct {
@@ -41,6 +41,27 @@ type Oracle struct {
 
 // NewOracle returns a new oracle.
 func NewOracle(chainService xxx.ChainServiceInterface, params OracleConfig) *Oracle {
+	gpo := &Oracle{
+		lastPrice:    new(big.Int).SetUint64(params.Default),
+		chainService: chainService,
+	}
+	gpo.setParams(params)
+	return gpo
+}
+
+// SetConfig apply new oracle parameters, the cached price is dropped so that the next
+// suggestion is computed with them
+func (gpo *Oracle) SetConfig(params OracleConfig) {
+	gpo.fetchLock.Lock()
+	defer gpo.fetchLock.Unlock()
+	gpo.setParams(params)
+
+	gpo.cacheLock.Lock()
+	gpo.lastHead = crypto.Hash{}
+	gpo.cacheLock.Unlock()
+}
+
+func (gpo *Oracle) setParams(params OracleConfig) {
 	blocks := params.Blocks
 	if blocks < 1 {
 		blocks = 1
@@ -52,15 +73,11 @@ func NewOracle(chainService xxx.ChainServiceInterface, params OracleConfig) *Ora
 	if percent > 100 {
 		percent = 100
 	}
-	return &Oracle{
-		lastPrice:    new(big.Int).SetUint64(params.Default),
-		maxPrice:     new(big.Int).SetUint64(params.MaxPrice),
-		checkBlocks:  blocks,
-		maxEmpty:     blocks / 2,
-		maxBlocks:    blocks * 5,
-		percentile:   percent,
-		chainService: chainService,
-	}
+	gpo.maxPrice = new(big.Int).SetUint64(params.MaxPrice)
+	gpo.checkBlocks = blocks
+	gpo.maxEmpty = blocks / 2
+	gpo.maxBlocks = blocks * 5
+	gpo.percentile = percent
 }
 
 // SuggestPrice returns the recommended gas price.
@@ -11,6 +11,12 @@ func (blockMgr *BlockMgr) verifyTransaction(tx *types.Transaction) error {
 	//db := blockMgr.ChainService.GetCurrentState()
 	//from, err := tx.From()
 
+	// Multi signatures are only accepted once they are active in the next block
+	nextHeight := blockMgr.ChainService.BestChain().Tip().Height + 1
+	if _, err := tx.FromAt(blockMgr.ChainService.ForkConfig().Rules(nextHeight)); err != nil {
+		return err
+	}
+
 	// Transactions can't be negative. This may never happen using RLP decoded
 	// transactions but may occur if you create a transaction using the RPC.
 	if tx.Amount().Sign() < 0 {
@@ -0,0 +1,94 @@
+package blockmgr
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+const (
+	TxTypeTransfer   = "transfer"
+	TxTypeCall       = "call"
+	TxTypeCreateCode = "createCode"
+	TxTypeSetAlias   = "setAlias"
+)
+
+// BuildTxArgs describes a transaction to build for offline signing, the fields left empty are
+// filled by the node: nonce from the pool, gas price from the oracle and gas limit by type
+type BuildTxArgs struct {
+	Type     string                `json:"type"`
+	From     crypto.CommonAddress  `json:"from"`
+	To       *crypto.CommonAddress `json:"to,omitempty"`
+	Amount   *common.Big           `json:"amount,omitempty"`
+	GasPrice *common.Big           `json:"gasPrice,omitempty"`
+	GasLimit *common.Big           `json:"gasLimit,omitempty"`
+	Nonce    *uint64               `json:"nonce,omitempty"`
+	// call input, contract byte code or alias
+	Data common.Bytes `json:"data,omitempty"`
+}
+
+// BuildTransaction build an unsigned transaction of the same types AccountApi sends with wallet
+func (blockMgr *BlockMgr) BuildTransaction(args *BuildTxArgs) (*types.UnsignedTransaction, error) {
+	var nonce uint64
+	if args.Nonce != nil {
+		nonce = *args.Nonce
+	} else {
+		nonce = blockMgr.GetTransactionCount(&args.From)
+	}
+	gasPrice := (*big.Int)(args.GasPrice)
+	if gasPrice == nil {
+		price, err := blockMgr.gpo.SuggestPrice()
+		if err != nil {
+			return nil, err
+		}
+		gasPrice = price
+	}
+	amount := new(big.Int)
+	if args.Amount != nil {
+		amount = (*big.Int)(args.Amount)
+	}
+	if amount.Sign() < 0 {
+		return nil, ErrNegativeAmount
+	}
+
+	var (
+		tx         *types.Transaction
+		defaultGas *big.Int
+	)
+	switch args.Type {
+	case TxTypeTransfer:
+		if args.To == nil {
+			return nil, fmt.Errorf("%s transaction requires to", args.Type)
+		}
+		defaultGas = types.TransferGas
+		tx = types.NewTransaction(*args.To, amount, gasPrice, defaultGas, nonce)
+	case TxTypeCall:
+		if args.To == nil {
+			return nil, fmt.Errorf("%s transaction requires to", args.Type)
+		}
+		defaultGas = types.CallContractGas
+		tx = types.NewCallContractTransaction(*args.To, args.Data, amount, gasPrice, defaultGas, nonce)
+	case TxTypeCreateCode:
+		if len(args.Data) == 0 {
+			return nil, fmt.Errorf("%s transaction requires byte code in data", args.Type)
+		}
+		defaultGas = types.CreateContractGas
+		tx = types.NewContractTransaction(args.Data, gasPrice, defaultGas, nonce)
+	case TxTypeSetAlias:
+		if len(args.Data) == 0 {
+			return nil, fmt.Errorf("%s transaction requires alias in data", args.Type)
+		}
+		defaultGas = types.SeAliasGas
+		tx = types.NewAliasTransaction(string(args.Data), gasPrice, defaultGas, nonce)
+	default:
+		return nil, fmt.Errorf("unknown transaction type %q, must be one of %s, %s, %s, %s", args.Type, TxTypeTransfer, TxTypeCall, TxTypeCreateCode, TxTypeSetAlias)
+	}
+	if args.GasLimit != nil {
+		tx.Data.GasLimit = *args.GasLimit
+	}
+	tx.Data.ChainId = blockMgr.ChainService.ChainID()
+	return types.NewUnsignedTransaction(args.From, tx), nil
+}
@@ -0,0 +1,30 @@
+package txpool
+
+// TxPoolConfig limits the number of transactions kept in the pool
+type TxPoolConfig struct {
+	MaxTxs         int `json:"maxTxs"`         //交易池所能容纳的总的交易数量
+	AccountQueue   int `json:"accountQueue"`   //单个地址对应的乱序队列中，最多容纳交易数目
+	AccountPending int `json:"accountPending"` //单个地址对应的有序队列中，最多容纳交易数目
+}
+
+var (
+	DefaultTxPoolConfig = TxPoolConfig{
+		MaxTxs:         maxAllTxsCount,
+		AccountQueue:   maxTxsOfQueue,
+		AccountPending: maxTxsOfPending,
+	}
+)
+
+// sanitize replace the limits not set with the default ones
+func (config TxPoolConfig) sanitize() TxPoolConfig {
+	if config.MaxTxs <= 0 {
+		config.MaxTxs = DefaultTxPoolConfig.MaxTxs
+	}
+	if config.AccountQueue <= 0 {
+		config.AccountQueue = DefaultTxPoolConfig.AccountQueue
+	}
+	if config.AccountPending <= 0 {
+		config.AccountPending = DefaultTxPoolConfig.AccountPending
+	}
+	return config
+}
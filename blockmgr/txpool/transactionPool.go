@@ -11,6 +11,7 @@ import (
 	"github.com/drep-project/DREP-Chain/common/event"
 	"github.com/drep-project/DREP-Chain/crypto"
 	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/pkgs/metrics"
 	"github.com/drep-project/DREP-Chain/types"
 )
 
@@ -47,11 +48,13 @@ type TransactionPool struct {
 	//日志
 	journal *txJournal
 	locals  map[crypto.CommonAddress]struct{} //本地节点包含的地址
+
+	config TxPoolConfig
 }
 
 //NewTransactionPool 创建一个交易池
 func NewTransactionPool(database *database.Database, journalPath string) *TransactionPool {
-	pool := &TransactionPool{database: database}
+	pool := &TransactionPool{database: database, config: DefaultTxPoolConfig}
 	pool.nonceCp = func(a interface{}, b interface{}) int {
 		ta, oka := a.(*types.Transaction)
 		tb, okb := b.(*types.Transaction)
@@ -80,6 +83,7 @@ func NewTransactionPool(database *database.Database, journalPath string) *Transa
 	pool.queue = make(map[crypto.CommonAddress]*txList)
 	pool.pending = make(map[crypto.CommonAddress]*txList)
 	pool.newBlockChan = make(chan *types.ChainEvent)
+	pool.quit = make(chan struct{})
 	pool.pendingNonce = make(map[crypto.CommonAddress]uint64)
 
 	pool.allTxs = make(map[string]*types.Transaction)
@@ -91,6 +95,14 @@ func NewTransactionPool(database *database.Database, journalPath string) *Transa
 	pool.journal.rotate(pool.local())
 	//todo 添加本地addr
 
+	metrics.NewGaugeFunc("txpool_pending", "Number of executable transactions in txpool", func() float64 {
+		pending, _ := pool.Stats()
+		return float64(pending)
+	})
+	metrics.NewGaugeFunc("txpool_queued", "Number of non-executable transactions in txpool", func() float64 {
+		_, queued := pool.Stats()
+		return float64(queued)
+	})
 	return pool
 }
 
@@ -233,7 +245,7 @@ func (pool *TransactionPool) addTx(tx *types.Transaction, isLocal bool) error {
 
 	//新的一个交易到来，先看看pool是否满；满的话，删除一些价格较低的tx
 	miniPrice := new(big.Int)
-	if len(pool.allTxs) >= maxAllTxsCount {
+	if len(pool.allTxs) >= pool.config.MaxTxs {
 		//todo 价格较低的交易将被丢弃
 		txs := pool.allPricedTxs.Discard(1, pool.locals)
 		for _, t := range txs {
@@ -284,7 +296,7 @@ func (pool *TransactionPool) addTx(tx *types.Transaction, isLocal bool) error {
 	//添加到queue
 	if list, ok := pool.queue[*addr]; ok {
 		//地址对应的队列空间是否已经满 ,删除一些老的tx
-		if list.Len() > maxTxsOfQueue {
+		if list.Len() > pool.config.AccountQueue {
 			//丢弃老的交易
 			txs := list.Cap(list.Len())
 			for _, delTx := range txs {
@@ -311,7 +323,7 @@ func (pool *TransactionPool) syncToPending(address *crypto.CommonAddress) {
 		pool.pending[*address] = newTxList(true)
 	}
 	listPending := pool.pending[*address]
-	if listPending.Len() > maxTxsOfPending {
+	if listPending.Len() > pool.config.AccountPending {
 		return
 	}
 
@@ -412,16 +424,32 @@ END:
 }
 
 //Start 开启交易池
+//SetConfig 修改交易池容量限制，已在池中的交易不受影响
+func (pool *TransactionPool) SetConfig(config TxPoolConfig) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.config = config.sanitize()
+}
+
 func (pool *TransactionPool) Start(feed *event.Feed) {
 	go pool.checkUpdate()
 	pool.eventNewBlockSub = feed.Subscribe(pool.newBlockChan)
 }
 
-//Stop 停止交易池
-func (pool *TransactionPool) Stop() {
+//Stop 停止交易池，停止前把本地交易写入日志文件
+func (pool *TransactionPool) Stop() error {
 	close(pool.quit)
-	pool.eventNewBlockSub.Unsubscribe()
-	pool.journal.close()
+	if pool.eventNewBlockSub != nil {
+		pool.eventNewBlockSub.Unsubscribe()
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if err := pool.journal.rotate(pool.local()); err != nil {
+		pool.journal.close()
+		return err
+	}
+	return pool.journal.close()
 }
 
 func (pool *TransactionPool) checkUpdate() {
@@ -549,6 +577,22 @@ func (pool *TransactionPool) GetTxInPool(hash string) (*types.Transaction, error
 	return nil, fmt.Errorf("hash:%s not in txpool", hash)
 }
 
+//Stats 获取交易池中pending和queue队列的交易数量
+func (pool *TransactionPool) Stats() (int, int) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pending := 0
+	for _, list := range pool.pending {
+		pending += list.Len()
+	}
+	queued := 0
+	for _, list := range pool.queue {
+		queued += list.Len()
+	}
+	return pending, queued
+}
+
 func (pool *TransactionPool) NewTxFeed() *event.Feed {
 	return &pool.txFeed
 }
@@ -61,6 +61,7 @@ type ChainServiceInterface interface {
 	BlockValidator() []IBlockValidator
 	AddBlockValidator(validator IBlockValidator)
 	GetConfig() *ChainConfig
+	ForkConfig() *params.ForkConfig
 	DetachBlockFeed() *event.Feed
 }
 
@@ -157,6 +158,23 @@ func (chainService *ChainService) ChainID() types.ChainIdType {
 	return chainService.chainId
 }
 
+// Producers returns the block producer set of the genesis config read by the producer native
+// contract, it is the same on every node unlike the producers of the local consensus config
+func (chainService *ChainService) Producers() []crypto.CommonAddress {
+	if chainService.Config == nil {
+		return nil
+	}
+	return chainService.Config.Producers
+}
+
+// ForkConfig returns the activation heights of the evm rule sets of the chain
+func (chainService *ChainService) ForkConfig() *params.ForkConfig {
+	if chainService.Config == nil {
+		return nil
+	}
+	return chainService.Config.Forks
+}
+
 func (chainService *ChainService) Name() string {
 	return MODULENAME
 }
@@ -166,7 +184,7 @@ func (chainService *ChainService) Api() []app.API {
 }
 
 func (chainService *ChainService) CommandFlags() ([]cli.Command, []cli.Flag) {
-	return nil, []cli.Flag{}
+	return nil, []cli.Flag{ReindexTxFlag}
 }
 
 func NewChainService(config *ChainConfig, ds *database.DatabaseService) *ChainService {
@@ -220,7 +238,11 @@ func (chainService *ChainService) Init(executeContext *app.ExecuteContext) error
 	chainService.transactionValidator = NewTransactionValidator(chainService)
 	chainService.blockValidator = []IBlockValidator{NewChainBlockValidator(chainService, chainService.transactionValidator)}
 
-	var err error
+	err := chainService.Config.Forks.Validate()
+	if err != nil {
+		log.Error("fork config err", err)
+		return err
+	}
 	chainService.genesisBlock = chainService.GetGenisiBlock(chainService.Config.GenesisAddr)
 	hash := chainService.genesisBlock.Header.Hash()
 	if !chainService.DatabaseService.HasBlock(hash) {
@@ -238,6 +260,15 @@ func (chainService *ChainService) Init(executeContext *app.ExecuteContext) error
 		return err
 	}
 
+	// databases written before the tx lookup index was stored with the blocks are indexed once
+	reindex := executeContext.Cli != nil && executeContext.Cli.GlobalBool(ReindexTxFlag.Name)
+	if reindex || !chainService.DatabaseService.TxLookupIndexed() {
+		if err := chainService.reindexTxLookup(); err != nil {
+			log.Error("reindex tx lookup err:", err)
+			return err
+		}
+	}
+
 	chainService.apis = []app.API{
 		app.API{
 			Namespace: MODULENAME,
@@ -256,7 +287,20 @@ func (chainService *ChainService) Start(executeContext *app.ExecuteContext) erro
 	return nil
 }
 
+// Stop flush the dirty block index nodes and the state trie of chain tip to disk
 func (chainService *ChainService) Stop(executeContext *app.ExecuteContext) error {
+	chainService.addBlockSync.Lock()
+	defer chainService.addBlockSync.Unlock()
+
+	if chainService.blockIndex != nil {
+		if err := chainService.blockIndex.FlushToDB(chainService.DatabaseService.PutBlockNode); err != nil {
+			return err
+		}
+	}
+	if chainService.bestChain != nil && chainService.bestChain.Tip() != nil {
+		triedb := chainService.DatabaseService.GetTriedDB()
+		return triedb.Commit(crypto.Bytes2Hash(chainService.bestChain.Tip().StateRoot), true)
+	}
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"github.com/drep-project/DREP-Chain/common"
 	"github.com/drep-project/DREP-Chain/common/hexutil"
 	"github.com/drep-project/DREP-Chain/crypto"
 	"github.com/drep-project/DREP-Chain/database"
@@ -161,6 +162,49 @@ func (chain *ChainApi) GetTransactionByBlockHeightAndIndex(height uint64, index
 	return block.Data.TxList[index], nil
 }
 
+/*
+ name: getTransactionByHash
+ usage: 根据交易hash获取主链上的交易
+ params:
+	1. 交易hash
+ return: 交易信息
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"chain_getTransactionByHash","params":["0xfa5c34114ff459b4c97e7cd268c507c0ccfcfc89d3ccdcf71e96402f9899d040"], "id": 3}' -H "Content-Type:application/json"
+ response:
+   {
+  "jsonrpc": "2.0",
+  "id": 3,
+  "result": {
+    "Hash": "0xfa5c34114ff459b4c97e7cd268c507c0ccfcfc89d3ccdcf71e96402f9899d040",
+    "From": "0x7923a30bbfbcb998a6534d56b313e68c8e0c594a",
+    "Version": 1,
+    "Nonce": 15632,
+    "Type": 0,
+    "To": "0x7923a30bbfbcb998a6534d56b313e68c8e0c594a",
+    "ChainId": "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+    "Amount": "0x111",
+    "GasPrice": "0x110",
+    "GasLimit": "0x30000",
+    "Timestamp": 1559322808,
+    "Data": null,
+    "Sig": "0x20f25b86c4bf73aa4fa0bcb01e2f5731de3a3917c8861d1ce0574a8d8331aedcf001e678000f6afc95d35a53ef623a2055fce687f85c2fd752dc455ab6db802b1f"
+  }
+}
+*/
+func (chain *ChainApi) GetTransactionByHash(txHash crypto.Hash) (*chainType.Transaction, error) {
+	entry, err := chain.dbService.GetTxLookupEntry(&txHash)
+	if err != nil {
+		return nil, ErrTxNotFound
+	}
+	block, err := chain.dbService.GetBlock(&entry.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Index >= uint64(len(block.Data.TxList)) {
+		return nil, ErrTxIndexOutOfRange
+	}
+	return block.Data.TxList[entry.Index], nil
+}
+
 /*
  name: getAliasByAddress
  usage: 根据地址获取地址对应的别名
@@ -203,18 +247,61 @@ func (chain *ChainApi) GetByteCode(addr *crypto.CommonAddress) hexutil.Bytes {
 	return chain.dbService.GetByteCode(addr)
 }
 
+/*
+ name: getStorageAt
+ usage: 查询合约存储中指定slot的值
+ params:
+	1. 合约地址
+	2. slot
+	3. 区块高度，可选，默认为最高区块，须不低于存储树分叉高度
+ return: slot的值，32字节
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"chain_getStorageAt","params":["0x8a8e541ddd1272d53729164c70197221a3c27486","0x0000000000000000000000000000000000000000000000000000000000000000",100], "id": 3}' -H "Content-Type:application/json"
+ response:
+   {"jsonrpc":"2.0","id":3,"result":"0x0000000000000000000000000000000000000000000000000000000000000001"}
+*/
+func (chain *ChainApi) GetStorageAt(addr crypto.CommonAddress, slot crypto.Hash, height *uint64) (hexutil.Bytes, error) {
+	number := chain.chainService.BestChain().Height()
+	if height != nil {
+		number = *height
+	}
+	// the slots written below the fork live outside the state
+	if !chain.chainService.ForkConfig().IsStorageTrie(number) {
+		return nil, ErrStorageNotVersioned
+	}
+	block, err := chain.GetBlock(number)
+	if err != nil {
+		return nil, err
+	}
+	value, err := chain.dbService.GetStateAt(crypto.Bytes2Hash(block.Header.StateRoot), &addr, slot)
+	if err != nil {
+		return nil, err
+	}
+	return common.LeftPadBytes(value, crypto.HashLength), nil
+}
+
 /*
  name: getReceipt
  usage: 根据txhash获取receipt信息
  params:
 	1. txhash
- return: receipt
+ return: receipt，包含交易所在区块的hash、高度(BlockNumber)和交易序号(TxIndex)，合约注册了abi时log的Event为解码后的事件名和参数
  example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"chain_getReceipt","params":["0x7d9dd32ca192e765ff2abd7c5f8931cc3f77f8f47d2d52170c7804c2ca2c5dd9"], "id": 3}' -H "Content-Type:application/json"
  response:
    {"jsonrpc":"2.0","id":3,"result":""}
 */
 func (chain *ChainApi) GetReceipt(txHash crypto.Hash) *chainType.Receipt {
-	return chain.dbService.GetReceipt(txHash)
+	receipt := chain.dbService.GetReceipt(txHash)
+	if receipt == nil {
+		return nil
+	}
+	// the receipt is written before the hash of its block is known
+	if entry, err := chain.dbService.GetTxLookupEntry(&txHash); err == nil {
+		receipt.BlockHash = entry.BlockHash
+		receipt.BlockNumber = entry.Height
+		receipt.TxIndex = uint(entry.Index)
+	}
+	chain.chainService.VmService.DecodeLogs(receipt.Logs)
+	return receipt
 }
 
 /*
@@ -222,12 +309,17 @@ func (chain *ChainApi) GetReceipt(txHash crypto.Hash) *chainType.Receipt {
  usage: 根据txhash获取交易log信息
  params:
 	1. txhash
- return: []log
+ return: []log，合约注册了abi时Event为解码后的事件名和参数
  example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"chain_getLogs","params":["0x7d9dd32ca192e765ff2abd7c5f8931cc3f77f8f47d2d52170c7804c2ca2c5dd9"], "id": 3}' -H "Content-Type:application/json"
  response:
    {"jsonrpc":"2.0","id":3,"result":""}
 */
 func (chain *ChainApi) GetLogs(txHash crypto.Hash) []*chainType.Log {
 	//return chain.dbService.GetLogs(txHash)
-	return chain.dbService.GetReceipt(txHash).Logs
+	receipt := chain.dbService.GetReceipt(txHash)
+	if receipt == nil {
+		return nil
+	}
+	chain.chainService.VmService.DecodeLogs(receipt.Logs)
+	return receipt.Logs
 }
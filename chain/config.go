@@ -2,6 +2,7 @@ package chain
 
 import (
 	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/params"
 	"github.com/drep-project/DREP-Chain/types"
 )
 
@@ -10,4 +11,8 @@ type ChainConfig struct {
 	RootChain   types.ChainIdType    `json:"rootChain,omitempty"`
 	ChainId     types.ChainIdType    `json:"chainId,omitempty"`
 	GenesisAddr crypto.CommonAddress `json:"genesisaddr"`
+	Forks       *params.ForkConfig   `json:"forks,omitempty"`
+	// Producers is the block producer set of the genesis read by contracts, it is part of the
+	// consensus so it must be identical on every node
+	Producers []crypto.CommonAddress `json:"producers,omitempty"`
 }
@@ -9,6 +9,7 @@ var (
 	ErrInvalidateBlockNumber     = errors.New("invalid block number")
 	ErrBlockNotFound             = errors.New("block not exist")
 	ErrTxIndexOutOfRange         = errors.New("tx index out of range")
+	ErrTxNotFound                = errors.New("transaction not exist")
 	ErrReachGasLimit             = errors.New("gas limit reached")
 	ErrInvalidateBlockMultisig   = errors.New("verify multisig error")
 	ErrUnsupportTxType           = errors.New("not support transaction type")
@@ -35,4 +36,5 @@ var (
 	ErrTooLongAlias              = errors.New("alias too long")
 	ErrUnsupportAliasChar        = errors.New("alias only support number and letter")
 	ErrReceiptRoot               = errors.New("receipt root not match")
+	ErrStorageNotVersioned       = errors.New("contract storage below the storage trie fork is not versioned")
 )
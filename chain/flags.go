@@ -0,0 +1,12 @@
+package chain
+
+import (
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	ReindexTxFlag = cli.BoolFlag{
+		Name:  "reindextx",
+		Usage: "rebuild the transaction lookup index of the stored main chain on start",
+	}
+)
@@ -2,6 +2,7 @@ package chain
 
 import (
 	dlog "github.com/drep-project/DREP-Chain/pkgs/log"
+	"github.com/drep-project/DREP-Chain/pkgs/metrics"
 )
 
 const (
@@ -10,4 +11,8 @@ const (
 
 var (
 	log = dlog.EnsureLogger(MODULENAME)
+
+	chainHeightGauge  = metrics.NewGauge("chain_height", "Height of the best chain tip")
+	reorgCounter      = metrics.NewCounter("chain_reorgs_total", "Number of chain reorganizations")
+	blockProcessTimer = metrics.NewHistogram("chain_block_process_seconds", "Time spent processing a block", nil)
 )
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/drep-project/DREP-Chain/crypto"
 	"github.com/drep-project/DREP-Chain/database"
@@ -17,6 +18,7 @@ import (
 func (chainService *ChainService) ProcessBlock(block *types.Block) (bool, bool, error) {
 	chainService.addBlockSync.Lock()
 	defer chainService.addBlockSync.Unlock()
+	defer blockProcessTimer.ObserveSince(time.Now())
 	blockHash := block.Header.Hash()
 	exist := chainService.BlockExists(blockHash)
 	if exist {
@@ -198,6 +200,14 @@ func (chainService *ChainService) connectBlock(db *database.Database, block *typ
 	}
 
 	if err == nil {
+		// the block is connected once it is stored with the index of its transactions, it is
+		// retried later if the write fails
+		if err = chainService.DatabaseService.PutConnectedBlock(block); err != nil {
+			if !db.RecoverTrie(chainService.bestChain.tip().StateRoot) {
+				log.Fatal("write tx lookup entries err and recover trie err")
+			}
+			return context, errors.Wrap(err, "write tx lookup entries")
+		}
 		chainService.blockIndex.SetStatusFlags(newNode, types.StatusValid)
 		chainService.flushIndexState()
 	} else {
@@ -279,6 +289,7 @@ func (chainService *ChainService) reorganizeChain(db *database.Database, detachN
 		lastBlock := elem.Value.(*types.BlockNode)
 		height := lastBlock.Height - 1
 		db.Rollback2Block(height, lastBlock.Hash)
+		reorgCounter.Inc()
 		log.WithField("Height", height).Info("REORGANIZE:RollBack state root")
 		chainService.markState(lastBlock.Parent)
 		elem = detachNodes.Front()
@@ -288,6 +299,9 @@ func (chainService *ChainService) reorganizeChain(db *database.Database, detachN
 			if err != nil {
 				return err
 			}
+			if writeErr := chainService.DatabaseService.DeleteTxLookupEntries(block); writeErr != nil {
+				log.WithField("Reason", writeErr).Warn("Error removing tx lookup entries from disk")
+			}
 			chainService.notifyDetachBlock(block)
 			elem = elem.Next()
 		}
@@ -344,6 +358,7 @@ func (chainService *ChainService) notifyDetachBlock(block *types.Block) {
 
 func (chainService *ChainService) markState(blockNode *types.BlockNode) {
 	chainService.BestChain().SetTip(blockNode)
+	chainHeightGauge.Set(float64(blockNode.Height))
 	triedb := chainService.DatabaseService.GetTriedDB()
 	triedb.Commit(crypto.Bytes2Hash(blockNode.StateRoot), true)
 }
@@ -425,6 +440,7 @@ func (chainService *ChainService) InitStates() error {
 
 	// Set the best chain view to the stored best state.
 	chainService.BestChain().SetTip(tip)
+	chainHeightGauge.Set(float64(tip.Height))
 
 	// Load the raw block bytes for the best block.
 	if !chainService.DatabaseService.HasBlock(tip.Hash) {
@@ -452,6 +468,30 @@ func (chainService *ChainService) InitStates() error {
 	return chainService.blockIndex.FlushToDB(chainService.DatabaseService.PutBlockNode)
 }
 
+// reindexTxLookup write the tx lookup entries of every block of the main chain, then mark the
+// index as complete
+func (chainService *ChainService) reindexTxLookup() error {
+	tip := chainService.BestChain().Tip()
+	log.WithField("height", tip.Height).Info("Indexing transactions of the main chain")
+	for height := uint64(0); height <= tip.Height; height++ {
+		node := chainService.BestChain().NodeByHeight(height)
+		if node == nil {
+			return errors.Wrapf(ErrBlockNotFound, "no block at height %d of the main chain", height)
+		}
+		block, err := chainService.DatabaseService.GetBlock(node.Hash)
+		if err != nil {
+			return errors.Wrapf(err, "read block %s", node.Hash)
+		}
+		if err := chainService.DatabaseService.PutTxLookupEntries(block); err != nil {
+			return err
+		}
+		if height%10000 == 0 && height > 0 {
+			log.WithField("height", height).Info("Indexing transactions of the main chain")
+		}
+	}
+	return chainService.DatabaseService.SetTxLookupIndexed()
+}
+
 //180000000/360
 func (chainService *ChainService) CalcGasLimit(parent *types.BlockHeader, gasFloor, gasCeil uint64) *big.Int {
 	limit := uint64(0)
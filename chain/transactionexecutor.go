@@ -18,7 +18,7 @@ func NewTransactionValidator(chain *ChainService) *TransactionValidator {
 }
 
 func (transactionValidator *TransactionValidator) ExecuteTransaction(db *database.Database, tx *types.Transaction, gp *GasPool, header *types.BlockHeader) (*types.Receipt, *big.Int, *big.Int, error) {
-	from, err := tx.From()
+	from, err := tx.FromAt(transactionValidator.chain.ForkConfig().Rules(header.Height))
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -16,7 +16,9 @@ import (
 	evmService "github.com/drep-project/DREP-Chain/pkgs/evm"
 	filterService "github.com/drep-project/DREP-Chain/pkgs/filter"
 	logServer "github.com/drep-project/DREP-Chain/pkgs/log"
+	"github.com/drep-project/DREP-Chain/pkgs/metrics"
 	"github.com/drep-project/DREP-Chain/pkgs/rpc"
+	"github.com/drep-project/DREP-Chain/pkgs/token"
 	"github.com/drep-project/DREP-Chain/pkgs/trace"
 	"github.com/drep-project/binary"
 
@@ -37,9 +39,11 @@ func main() {
 		blockService.BlockMgr{},
 		chainIndexerService.ChainIndexerService{},
 		filterService.FilterService{},
+		token.TokenService{},
 		accountService.AccountService{},
 		consensusService.ConsensusService{},
 		trace.TraceService{},
+		metrics.MetricsService{},
 		cliService.CliService{},
 	)
 
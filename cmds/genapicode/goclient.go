@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+
+	blockmgr "github.com/drep-project/DREP-Chain/blockmgr"
+	chainService "github.com/drep-project/DREP-Chain/chain"
+	"github.com/drep-project/DREP-Chain/crypto"
+	p2pService "github.com/drep-project/DREP-Chain/network/service"
+	accountService "github.com/drep-project/DREP-Chain/pkgs/accounts/service"
+	chainIndexerService "github.com/drep-project/DREP-Chain/pkgs/chain_indexer"
+	consensusService "github.com/drep-project/DREP-Chain/pkgs/consensus/service"
+	evmService "github.com/drep-project/DREP-Chain/pkgs/evm"
+	filterService "github.com/drep-project/DREP-Chain/pkgs/filter"
+	logService "github.com/drep-project/DREP-Chain/pkgs/log"
+	rpcService "github.com/drep-project/DREP-Chain/pkgs/rpc"
+	tokenService "github.com/drep-project/DREP-Chain/pkgs/token"
+	traceService "github.com/drep-project/DREP-Chain/pkgs/trace"
+	chainTypes "github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/rpc"
+)
+
+const (
+	goPackage = "rpcclient"
+	rpcPath   = "github.com/drep-project/rpc"
+
+	goFileTemplate = `// Code generated by genapicode. DO NOT EDIT.
+
+package %[1]s
+
+import (
+%[2]s)
+
+// %[3]sClient calls the %[4]s namespace of a node
+type %[3]sClient struct {
+	c *rpc.Client
+}
+%[5]s`
+
+	goClientTemplate = `// Code generated by genapicode. DO NOT EDIT.
+
+package %[1]s
+
+import "github.com/drep-project/rpc"
+
+// Client is a typed client of every namespace of a node
+type Client struct {
+	c *rpc.Client
+
+%[2]s}
+
+// NewClient wrap an established rpc connection
+func NewClient(c *rpc.Client) *Client {
+	return &Client{
+		c: c,
+%[3]s	}
+}
+`
+
+	goCallTemplate = `
+// %[1]s calls %[2]s_%[3]s
+func (api *%[4]sClient) %[1]s(ctx context.Context%[5]s) (%[6]s, error) {
+	var result %[6]s
+	err := api.c.CallContext(ctx, &result, "%[2]s_%[3]s"%[7]s)
+	return result, err
+}
+`
+
+	goExecTemplate = `
+// %[1]s calls %[2]s_%[3]s
+func (api *%[4]sClient) %[1]s(ctx context.Context%[5]s) error {
+	return api.c.CallContext(ctx, nil, "%[2]s_%[3]s"%[6]s)
+}
+`
+
+	goSubscribeTemplate = `
+// Subscribe%[1]s subscribes to %[2]s_%[3]s, notifications are sent to ch until the subscription
+// is unsubscribed or the connection is closed
+func (api *%[4]sClient) Subscribe%[1]s(ctx context.Context, ch chan<- %[5]s%[6]s) (*rpc.ClientSubscription, error) {
+	return api.c.Subscribe(ctx, "%[2]s", ch, "%[3]s"%[7]s)
+}
+`
+)
+
+// goApis are the namespaces of the typed go client
+var goApis = []struct {
+	namespace string
+	name      string
+	api       interface{}
+}{
+	{"p2p", "P2P", &p2pService.P2PApi{}},
+	{"account", "Account", &accountService.AccountApi{}},
+	{"blockmgr", "BlockMgr", &blockmgr.BlockMgrApi{}},
+	{"log", "Log", &logService.LogApi{}},
+	{"chain", "Chain", &chainService.ChainApi{}},
+	{"consensus", "Consensus", &consensusService.ConsensusApi{}},
+	{"trace", "Trace", &traceService.TraceApi{}},
+	{"filter", "Filter", &filterService.FilterApi{}},
+	{"admin", "Admin", &rpcService.AdminApi{}},
+	{"indexer", "Indexer", &chainIndexerService.ChainIndexerApi{}},
+	{"vm", "Vm", &evmService.EvmApi{}},
+	{"token", "Token", &tokenService.TokenApi{}},
+}
+
+// subscriptionResults map namespace_method of a subscription to the type of its notifications,
+// notifications of subscriptions not listed here are delivered as json.RawMessage
+var subscriptionResults = map[string]interface{}{
+	"filter_newHeads":               &chainTypes.BlockHeader{},
+	"filter_logs":                   &chainTypes.Log{},
+	"filter_newPendingTransactions": crypto.Hash{},
+}
+
+var (
+	contextType      = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType        = reflect.TypeOf((*error)(nil)).Elem()
+	subscriptionType = reflect.TypeOf(&rpc.Subscription{})
+	rawMessageType   = reflect.TypeOf(json.RawMessage{})
+)
+
+// generateGoClient write a typed go client of every namespace into dir
+func generateGoClient(dir string) error {
+	fields, inits := "", ""
+	for _, api := range goApis {
+		code, err := generateGoFile(api.namespace, api.name, reflect.TypeOf(api.api))
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, api.namespace+".go"), code, 0644); err != nil {
+			return err
+		}
+		fields += fmt.Sprintf("\t%s *%sClient\n", api.name, api.name)
+		inits += fmt.Sprintf("\t\t%s: &%sClient{c},\n", api.name, api.name)
+	}
+	code, err := format.Source([]byte(fmt.Sprintf(goClientTemplate, goPackage, fields, inits)))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "client.go"), code, 0644)
+}
+
+func generateGoFile(namespace, name string, vType reflect.Type) ([]byte, error) {
+	imports := newGoImports()
+	methods := []*goMethod{}
+	for i := 0; i < vType.NumMethod(); i++ {
+		method := newGoMethod(namespace, vType.Elem().Name(), vType.Method(i))
+		// resolve every package first, so that no parameter shadows an import
+		method.resolve(imports)
+		methods = append(methods, method)
+	}
+
+	body := &bytes.Buffer{}
+	for _, method := range methods {
+		method.write(body, imports, name)
+	}
+	return format.Source([]byte(fmt.Sprintf(goFileTemplate, goPackage, imports, name, namespace, body)))
+}
+
+// goMethod is an api method as called by the client
+type goMethod struct {
+	namespace string
+	name      string
+	params    []reflect.Type
+	names     []string
+	result    reflect.Type // nil if the method only return an error
+	subscribe bool
+}
+
+func newGoMethod(namespace, typeName string, method reflect.Method) *goMethod {
+	m := &goMethod{
+		namespace: namespace,
+		name:      method.Name,
+	}
+	for i := 1; i < method.Type.NumIn(); i++ {
+		m.params = append(m.params, method.Type.In(i))
+	}
+	if names := paramNames(typeName, method); len(names) == len(m.params) {
+		m.names = names
+	}
+	// the context is passed by the rpc server, not by the caller
+	if len(m.params) > 0 && m.params[0] == contextType {
+		m.params = m.params[1:]
+		if m.names != nil {
+			m.names = m.names[1:]
+		}
+	}
+	for i := 0; i < method.Type.NumOut(); i++ {
+		if out := method.Type.Out(i); out != errorType {
+			m.result = out
+			break
+		}
+	}
+	if m.result == subscriptionType {
+		m.subscribe = true
+		m.result = rawMessageType
+		if result, ok := subscriptionResults[namespace+"_"+Capitalize(method.Name)]; ok {
+			m.result = reflect.TypeOf(result)
+		}
+	}
+	return m
+}
+
+func (m *goMethod) resolve(imports *goImports) {
+	for _, param := range m.params {
+		imports.typeString(param)
+	}
+	if m.result != nil {
+		imports.typeString(m.result)
+	}
+}
+
+func (m *goMethod) write(w io.Writer, imports *goImports, client string) {
+	call := Capitalize(m.name)
+	params, args := "", ""
+	for i, param := range m.params {
+		name := fmt.Sprintf("arg%d", i)
+		if m.names != nil && !imports.reserved(m.names[i]) {
+			name = m.names[i]
+		}
+		params += ", " + name + " " + imports.typeString(param)
+		args += ", " + name
+	}
+
+	switch {
+	case m.subscribe:
+		fmt.Fprintf(w, goSubscribeTemplate, m.name, m.namespace, call, client, imports.typeString(m.result), params, args)
+	case m.result == nil:
+		fmt.Fprintf(w, goExecTemplate, m.name, m.namespace, call, client, params, args)
+	default:
+		fmt.Fprintf(w, goCallTemplate, m.name, m.namespace, call, client, params, imports.typeString(m.result), args)
+	}
+}
+
+// paramNames read the parameter names of method from its declaration, reflection only knows
+// the types
+func paramNames(typeName string, method reflect.Method) []string {
+	pc := method.Func.Pointer()
+	file, _ := runtime.FuncForPC(pc).FileLine(pc)
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		return nil
+	}
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Name.Name != method.Name || receiverName(fn) != typeName {
+			continue
+		}
+		names := []string{}
+		for _, field := range fn.Type.Params.List {
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+func receiverName(fn *ast.FuncDecl) string {
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// goImports are the packages used by a generated file
+type goImports struct {
+	names map[string]string // import path -> name in the file
+	paths map[string]string // name in the file -> import path
+}
+
+func newGoImports() *goImports {
+	imports := &goImports{
+		names: map[string]string{},
+		paths: map[string]string{},
+	}
+	imports.name("context", "context")
+	imports.name(rpcPath, "rpc")
+	return imports
+}
+
+func (imports *goImports) name(pkgPath, pkgName string) string {
+	if name, ok := imports.names[pkgPath]; ok {
+		return name
+	}
+	name := pkgName
+	for i := 2; imports.paths[name] != ""; i++ {
+		name = fmt.Sprintf("%s%d", pkgName, i)
+	}
+	imports.names[pkgPath] = name
+	imports.paths[name] = pkgPath
+	return name
+}
+
+func (imports *goImports) reserved(name string) bool {
+	switch name {
+	case "", "_", "api", "ctx", "ch", "result", "err":
+		return true
+	}
+	_, ok := imports.paths[name]
+	return ok
+}
+
+func (imports *goImports) typeString(t reflect.Type) string {
+	// json.RawMessage is an alias of jsontext.Value with the json v2 experiment
+	if t == rawMessageType {
+		return imports.name("encoding/json", "json") + ".RawMessage"
+	}
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		pkgName := t.String()[:len(t.String())-len(t.Name())-1]
+		return imports.name(t.PkgPath(), pkgName) + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + imports.typeString(t.Elem())
+	case reflect.Slice:
+		return "[]" + imports.typeString(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), imports.typeString(t.Elem()))
+	case reflect.Map:
+		return "map[" + imports.typeString(t.Key()) + "]" + imports.typeString(t.Elem())
+	}
+	return t.String()
+}
+
+func (imports *goImports) String() string {
+	pkgPaths := make([]string, 0, len(imports.names))
+	for pkgPath := range imports.names {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Strings(pkgPaths)
+
+	code := ""
+	for _, pkgPath := range pkgPaths {
+		if name := imports.names[pkgPath]; name != path.Base(pkgPath) {
+			code += fmt.Sprintf("\t%s %q\n", name, pkgPath)
+		} else {
+			code += fmt.Sprintf("\t%q\n", pkgPath)
+		}
+	}
+	return code
+}
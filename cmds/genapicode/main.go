@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	blockmgr "github.com/drep-project/DREP-Chain/blockmgr"
 	chainService "github.com/drep-project/DREP-Chain/chain"
@@ -75,6 +76,15 @@ func Capitalize(str string) string {
 }
 
 func main() {
+	goDir := flag.String("go", "", "write the typed go rpc client into the directory")
+	flag.Parse()
+	if *goDir != "" {
+		if err := generateGoClient(*goDir); err != nil {
+			fmt.Println("Failed to generate the go client", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
 
 	output := "std"
 	if len(os.Args) > 0 {
@@ -128,6 +138,10 @@ var %s = new Method({
 
 	for i := 0; i < methods; i++ {
 		m := vType.Method(i)
+		// subscriptions are only served over websocket
+		if m.Func.Type().NumOut() > 0 && m.Func.Type().Out(0) == subscriptionType {
+			continue
+		}
 		numIn := m.Func.Type().NumIn()
 		oNmae := m.Name
 		methodName := Capitalize(oNmae)
@@ -0,0 +1,135 @@
+// signer is the daemon holding the keys of a keystore for nodes configured with an external
+// keystore. Every request is checked against the rules file, prompted for approval on this
+// terminal if the rules require it and recorded in the audit log.
+//
+//	signer --keystore ./keystore --socket ./signer.ipc --rules rules.json --audit audit.log
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/drep-project/DREP-Chain/crypto/hdkey"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	accountComponent "github.com/drep-project/DREP-Chain/pkgs/accounts/component"
+	"github.com/drep-project/DREP-Chain/pkgs/accounts/signer"
+	"github.com/drep-project/DREP-Chain/types"
+	"golang.org/x/crypto/ssh/terminal"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	keyStoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "keystore directory holding the keys to sign with",
+	}
+	socketFlag = cli.StringFlag{
+		Name:  "socket",
+		Usage: "unix socket to listen on",
+		Value: "signer.ipc",
+	}
+	rulesFlag = cli.StringFlag{
+		Name:  "rules",
+		Usage: "json file of signing rules",
+	}
+	auditFlag = cli.StringFlag{
+		Name:  "audit",
+		Usage: "file the audit log is appended to",
+		Value: "signer-audit.log",
+	}
+	passwordFileFlag = cli.StringFlag{
+		Name:  "passwordfile",
+		Usage: "file containing the wallet password, prompted if not set",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "signer"
+	app.Usage = "sign transactions and blocks for nodes keeping no keys"
+	app.Flags = []cli.Flag{
+		keyStoreFlag, socketFlag, rulesFlag, auditFlag, passwordFileFlag,
+	}
+	app.Action = run
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx *cli.Context) error {
+	for _, flag := range []cli.StringFlag{keyStoreFlag, rulesFlag} {
+		if !ctx.GlobalIsSet(flag.Name) {
+			return fmt.Errorf("--%s is required", flag.Name)
+		}
+	}
+	rules, err := signer.LoadRules(ctx.GlobalString(rulesFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	password, err := readPassword(ctx)
+	if err != nil {
+		return err
+	}
+	// wallets store keys encrypted with the hash of password
+	auth := string(sha3.Keccak256([]byte(password)))
+	nodes, err := accountComponent.NewFileStore(ctx.GlobalString(keyStoreFlag.Name)).ExportKey(auth)
+	if err != nil {
+		return err
+	}
+	keys := []*types.Node{}
+	for _, node := range nodes {
+		// the master key of a mnemonic wallet only derives accounts
+		if node.Path == hdkey.RootPath.String() {
+			continue
+		}
+		keys = append(keys, node)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no key in keystore")
+	}
+
+	audit, err := signer.OpenAuditLog(ctx.GlobalString(auditFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer audit.Close()
+	api := signer.NewSignerApi(keys, rules, signer.NewTerminalApprover(os.Stdin, os.Stderr), audit)
+	listener, handler, err := signer.Listen(ctx.GlobalString(socketFlag.Name), api)
+	if err != nil {
+		return err
+	}
+	for _, addr := range api.Accounts() {
+		fmt.Fprintf(os.Stderr, "signing for %s\n", addr.String())
+	}
+	fmt.Fprintf(os.Stderr, "listening on %s\n", ctx.GlobalString(socketFlag.Name))
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	listener.Close()
+	handler.Stop()
+	return nil
+}
+
+func readPassword(ctx *cli.Context) (string, error) {
+	if ctx.GlobalIsSet(passwordFileFlag.Name) {
+		content, err := ioutil.ReadFile(ctx.GlobalString(passwordFileFlag.Name))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
@@ -0,0 +1,200 @@
+// signtx signs transactions built by blockmgr_buildTransaction with a key of a keystore, so that
+// keys never have to be on a network facing node. The output is the raw transaction to submit
+// with blockmgr_sendRawTransaction. The transaction is shown and has to be confirmed before it is
+// signed.
+//
+//	signtx --keystore ./keystore --in tx.json --out tx.raw
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/common/fileutil"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	accountComponent "github.com/drep-project/DREP-Chain/pkgs/accounts/component"
+	"github.com/drep-project/DREP-Chain/types"
+	"golang.org/x/crypto/ssh/terminal"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	keyStoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "keystore directory or key file of the sender, ethereum V3 key files are accepted",
+	}
+	inFlag = cli.StringFlag{
+		Name:  "in",
+		Usage: "unsigned transaction json file, read from stdin if not set",
+	}
+	outFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "file to write the raw transaction to, written to stdout if not set",
+	}
+	passwordFileFlag = cli.StringFlag{
+		Name:  "passwordfile",
+		Usage: "file containing the wallet password, prompted if not set",
+	}
+	yesFlag = cli.BoolFlag{
+		Name:  "yes",
+		Usage: "sign without asking for confirmation",
+	}
+
+	txTypeNames = map[types.TxType]string{
+		types.TransferType:       "transfer",
+		types.MinerType:          "miner",
+		types.CreateContractType: "create contract",
+		types.CallContractType:   "call contract",
+		types.CrossChainType:     "cross chain",
+		types.SetAliasType:       "set alias",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "signtx"
+	app.Usage = "sign transactions offline with a keystore"
+	app.Flags = []cli.Flag{
+		keyStoreFlag, inFlag, outFlag, passwordFileFlag, yesFlag,
+	}
+	app.Action = sign
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func sign(ctx *cli.Context) error {
+	if !ctx.GlobalIsSet(keyStoreFlag.Name) {
+		return fmt.Errorf("--%s is required", keyStoreFlag.Name)
+	}
+	// the password prompt and the confirmation read the terminal on stdin, it must not be the
+	// input of the transaction
+	prompt := ctx.GlobalIsSet(inFlag.Name) && terminal.IsTerminal(int(os.Stdin.Fd()))
+	if !prompt && !ctx.GlobalIsSet(passwordFileFlag.Name) {
+		return fmt.Errorf("cannot prompt the password on stdin, it holds the transaction or is not a terminal: set --%s, or --%s with stdin a terminal", passwordFileFlag.Name, inFlag.Name)
+	}
+	if !prompt && !ctx.GlobalBool(yesFlag.Name) {
+		return fmt.Errorf("cannot confirm the transaction on stdin, it holds the transaction or is not a terminal: set --%s, or --%s with stdin a terminal", yesFlag.Name, inFlag.Name)
+	}
+	var (
+		content []byte
+		err     error
+	)
+	if ctx.GlobalIsSet(inFlag.Name) {
+		content, err = ioutil.ReadFile(ctx.GlobalString(inFlag.Name))
+	} else {
+		content, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return err
+	}
+	utx := &types.UnsignedTransaction{}
+	if err := json.Unmarshal(content, utx); err != nil {
+		return fmt.Errorf("invalid unsigned transaction: %v", err)
+	}
+	printTransaction(utx)
+	if !ctx.GlobalBool(yesFlag.Name) {
+		confirmed, err := confirm()
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("transaction not signed")
+		}
+	}
+
+	password, err := readPassword(ctx)
+	if err != nil {
+		return err
+	}
+	// wallets store keys encrypted with the hash of password
+	auth := string(sha3.Keccak256([]byte(password)))
+	keyStore := ctx.GlobalString(keyStoreFlag.Name)
+	var node *types.Node
+	if fileutil.IsDirExists(keyStore) {
+		node, err = accountComponent.NewFileStore(keyStore).GetKey(&utx.From, auth)
+	} else {
+		var keyContent []byte
+		if keyContent, err = ioutil.ReadFile(keyStore); err == nil {
+			if accountComponent.IsEthKeyJSON(keyContent) {
+				// ethereum keystores are encrypted with the password itself
+				var key *secp256k1.PrivateKey
+				if key, err = accountComponent.DecryptEthKey(keyContent, password); err == nil {
+					node = &types.Node{PrivateKey: key}
+				}
+			} else {
+				node, err = accountComponent.BytesToCryptoNode(keyContent, auth)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("load key of %s: %v", utx.From.String(), err)
+	}
+
+	tx, err := utx.Sign(node.PrivateKey)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "signed transaction %s from %s nonce %d chain %d\n", tx.TxHash().String(), utx.From.String(), tx.Nonce(), tx.ChainId())
+	raw := common.Encode(tx.AsPersistentMessage())
+	if ctx.GlobalIsSet(outFlag.Name) {
+		return ioutil.WriteFile(ctx.GlobalString(outFlag.Name), []byte(raw+"\n"), 0600)
+	}
+	fmt.Println(raw)
+	return nil
+}
+
+// printTransaction show the fields of the transaction to sign on stderr
+func printTransaction(utx *types.UnsignedTransaction) {
+	data := utx.Data
+	txType, ok := txTypeNames[data.Type]
+	if !ok {
+		txType = fmt.Sprintf("unknown (%d)", data.Type)
+	}
+	fmt.Fprintf(os.Stderr, "type:      %s\n", txType)
+	fmt.Fprintf(os.Stderr, "from:      %s\n", utx.From.String())
+	fmt.Fprintf(os.Stderr, "to:        %s\n", data.To.String())
+	fmt.Fprintf(os.Stderr, "amount:    %s\n", data.Amount.ToInt().String())
+	fmt.Fprintf(os.Stderr, "gas price: %s\n", data.GasPrice.ToInt().String())
+	fmt.Fprintf(os.Stderr, "gas limit: %s\n", data.GasLimit.ToInt().String())
+	fmt.Fprintf(os.Stderr, "nonce:     %d\n", data.Nonce)
+	fmt.Fprintf(os.Stderr, "chain id:  %d\n", data.ChainId)
+	if len(data.Data) > 0 {
+		fmt.Fprintf(os.Stderr, "data:      %d bytes\n", len(data.Data))
+	}
+}
+
+// confirm ask on the terminal whether to sign the transaction
+func confirm() (bool, error) {
+	fmt.Fprint(os.Stderr, "Sign this transaction? [y/N]: ")
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func readPassword(ctx *cli.Context) (string, error) {
+	if ctx.GlobalIsSet(passwordFileFlag.Name) {
+		content, err := ioutil.ReadFile(ctx.GlobalString(passwordFileFlag.Name))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
@@ -0,0 +1,103 @@
+// Package hdkey implements BIP39 mnemonics and BIP32 hierarchical deterministic private key
+// derivation over secp256k1, so that keys derived from a mnemonic match those of other wallets.
+package hdkey
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+)
+
+const (
+	// HardenedKeyStart is the index of the first hardened child key
+	HardenedKeyStart = uint32(0x80000000)
+
+	// MinSeedLen and MaxSeedLen bound the length of master seeds in bytes
+	MinSeedLen = 16
+	MaxSeedLen = 64
+)
+
+var (
+	masterKey = []byte("Bitcoin seed")
+
+	ErrInvalidSeedLen = errors.New("seed length must be between 128 and 512 bits")
+	ErrUnusableSeed   = errors.New("seed derives an invalid master key")
+	ErrInvalidChild   = errors.New("child key at this index is invalid, use the next index")
+)
+
+// ExtendedKey is a private key with the chain code used to derive its children
+type ExtendedKey struct {
+	PrivateKey *secp256k1.PrivateKey
+	ChainCode  []byte
+	Depth      uint8
+	Index      uint32
+}
+
+// NewMaster derive the master key of seed
+func NewMaster(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < MinSeedLen || len(seed) > MaxSeedLen {
+		return nil, ErrInvalidSeedLen
+	}
+	mac := hmac.New(sha512.New, masterKey)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(secp256k1.S256().N) >= 0 {
+		return nil, ErrUnusableSeed
+	}
+	privateKey, _ := secp256k1.PrivKeyFromBytes(sum[:32])
+	return &ExtendedKey{
+		PrivateKey: privateKey,
+		ChainCode:  sum[32:],
+	}, nil
+}
+
+// Child derive the child key at index, indexes from HardenedKeyStart derive hardened keys
+func (key *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	var data []byte
+	if index >= HardenedKeyStart {
+		data = append([]byte{0x00}, key.PrivateKey.Serialize()...)
+	} else {
+		data = key.PrivateKey.PubKey().SerializeCompressed()
+	}
+	indexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(indexBytes, index)
+	data = append(data, indexBytes...)
+
+	mac := hmac.New(sha512.New, key.ChainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curveN := secp256k1.S256().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(curveN) >= 0 {
+		return nil, ErrInvalidChild
+	}
+	childKey := tweak.Add(tweak, key.PrivateKey.D)
+	childKey.Mod(childKey, curveN)
+	if childKey.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+	return &ExtendedKey{
+		PrivateKey: secp256k1.NewPrivateKey(childKey),
+		ChainCode:  sum[32:],
+		Depth:      key.Depth + 1,
+		Index:      index,
+	}, nil
+}
+
+// Derive the key at path relative to key
+func (key *ExtendedKey) Derive(path DerivationPath) (*ExtendedKey, error) {
+	var err error
+	for _, index := range path {
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
@@ -0,0 +1,66 @@
+package hdkey
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// test vector 1 of BIP32
+func TestDeriveVector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMaster(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path      string
+		key       string
+		chainCode string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"},
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca", "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4", "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8", "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e"},
+	}
+	for _, test := range tests {
+		path, err := ParseDerivationPath(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := master.Derive(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(key.PrivateKey.Serialize()); got != test.key {
+			t.Errorf("%s: expect key %s, got %s", test.path, test.key, got)
+		}
+		if got := hex.EncodeToString(key.ChainCode); got != test.chainCode {
+			t.Errorf("%s: expect chain code %s, got %s", test.path, test.chainCode, got)
+		}
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"m/44'/60'/0'/0/1", "m/44'/60'/0'/0/1"},
+		{"m/44h/60H/0'/0/1", "m/44'/60'/0'/0/1"},
+		{"5", "m/44'/60'/0'/0/5"},
+		{"m", "m"},
+	}
+	for _, test := range tests {
+		path, err := ParseDerivationPath(test.input)
+		if err != nil || path.String() != test.output {
+			t.Errorf("%s: expect %s, got %s %v", test.input, test.output, path, err)
+		}
+	}
+	for _, input := range []string{"", "m/x", "m/2147483648", "m//1"} {
+		if _, err := ParseDerivationPath(input); err == nil {
+			t.Errorf("%q: expect error", input)
+		}
+	}
+}
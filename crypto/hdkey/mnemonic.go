@@ -0,0 +1,112 @@
+package hdkey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// MinEntropyBits and MaxEntropyBits bound the entropy of BIP39 mnemonics, 12 to 24 words
+	MinEntropyBits = 128
+	MaxEntropyBits = 256
+
+	// each word encodes 11 bits of the entropy and its checksum
+	bitsPerWord = 11
+
+	seedIterations = 2048
+)
+
+var (
+	ErrInvalidEntropyLen = errors.New("entropy must be 128 to 256 bits and a multiple of 32 bits")
+	ErrInvalidMnemonic   = errors.New("invalid mnemonic")
+	ErrMnemonicChecksum  = errors.New("mnemonic checksum mismatch")
+
+	wordIndex = map[string]int{}
+)
+
+func init() {
+	for i, word := range englishWords {
+		wordIndex[word] = i
+	}
+}
+
+// NewEntropy read bits of random entropy for a new mnemonic
+func NewEntropy(bits int) ([]byte, error) {
+	if bits < MinEntropyBits || bits > MaxEntropyBits || bits%32 != 0 {
+		return nil, ErrInvalidEntropyLen
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+// NewMnemonic encode entropy as the english words of BIP39, the words are followed by a checksum
+// of entropy/32 bits taken from its sha256 hash
+func NewMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if bits < MinEntropyBits || bits > MaxEntropyBits || bits%32 != 0 {
+		return "", ErrInvalidEntropyLen
+	}
+	checksum := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), checksum[0])
+	words := make([]string, (bits+bits/32)/bitsPerWord)
+	for i := range words {
+		index := 0
+		for bit := i * bitsPerWord; bit < (i+1)*bitsPerWord; bit++ {
+			index = index<<1 | int(data[bit/8]>>(7-uint(bit%8))&1)
+		}
+		words[i] = englishWords[index]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decode the entropy of a mnemonic and verify its checksum
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	bits := len(words) * bitsPerWord
+	// the checksum is 1 bit for every 32 bits of entropy
+	entropyBits := bits * 32 / 33
+	if len(words)%3 != 0 || entropyBits < MinEntropyBits || entropyBits > MaxEntropyBits {
+		return nil, ErrInvalidMnemonic
+	}
+	data := make([]byte, (bits+7)/8)
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, ErrInvalidMnemonic
+		}
+		for j := 0; j < bitsPerWord; j++ {
+			if index>>(uint(bitsPerWord-1-j))&1 == 1 {
+				bit := i*bitsPerWord + j
+				data[bit/8] |= 1 << (7 - uint(bit%8))
+			}
+		}
+	}
+	entropy := data[:entropyBits/8]
+	checksumBits := uint(bits - entropyBits)
+	checksum := sha256.Sum256(entropy)
+	if data[entropyBits/8]>>(8-checksumBits) != checksum[0]>>(8-checksumBits) {
+		return nil, ErrMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+// IsMnemonicValid check the words and checksum of a mnemonic
+func IsMnemonicValid(mnemonic string) bool {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err == nil
+}
+
+// NewSeed derive the BIP39 seed of mnemonic protected by passphrase, words are joined by single
+// spaces as other wallets do
+func NewSeed(mnemonic, passphrase string) []byte {
+	normalized := strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(normalized), []byte("mnemonic"+passphrase), seedIterations, 64, sha512.New)
+}
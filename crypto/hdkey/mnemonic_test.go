@@ -0,0 +1,70 @@
+package hdkey
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// test vectors of BIP39 with passphrase "TREZOR"
+func TestMnemonicVector(t *testing.T) {
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			"808080808080808080808080808080808080808080808080",
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+			"107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+		},
+	}
+	for _, test := range tests {
+		entropy, _ := hex.DecodeString(test.entropy)
+		mnemonic, err := NewMnemonic(entropy)
+		if err != nil || mnemonic != test.mnemonic {
+			t.Errorf("%s: expect mnemonic %s, got %s %v", test.entropy, test.mnemonic, mnemonic, err)
+		}
+		decoded, err := MnemonicToEntropy(test.mnemonic)
+		if err != nil || hex.EncodeToString(decoded) != test.entropy {
+			t.Errorf("%s: decoded entropy %x %v", test.entropy, decoded, err)
+		}
+		if seed := hex.EncodeToString(NewSeed(test.mnemonic, "TREZOR")); seed != test.seed {
+			t.Errorf("%s: expect seed %s, got %s", test.entropy, test.seed, seed)
+		}
+	}
+}
+
+func TestInvalidMnemonic(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		err      error
+	}{
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon yellow", ErrMnemonicChecksum},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrInvalidMnemonic},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon aboutt", ErrInvalidMnemonic},
+		{"", ErrInvalidMnemonic},
+	}
+	for _, test := range tests {
+		if _, err := MnemonicToEntropy(test.mnemonic); err != test.err {
+			t.Errorf("%q: expect %v, got %v", test.mnemonic, test.err, err)
+		}
+	}
+	if _, err := NewEntropy(100); err != ErrInvalidEntropyLen {
+		t.Errorf("expect invalid entropy length, got %v", err)
+	}
+}
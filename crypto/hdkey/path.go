@@ -0,0 +1,87 @@
+package hdkey
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// DefaultBaseDerivationPath is the BIP44 path of external accounts, drep addresses are ethereum
+	// addresses so the ethereum coin type is used and accounts can be restored by other wallets
+	DefaultBaseDerivationPath = DerivationPath{HardenedKeyStart + 44, HardenedKeyStart + 60, HardenedKeyStart + 0, 0}
+
+	// RootPath is the path of the master key
+	RootPath = DerivationPath{}
+)
+
+// DerivationPath is the list of child indexes from the master key to a key
+type DerivationPath []uint32
+
+// ParseDerivationPath parse a BIP32 path like "m/44'/60'/0'/0/0", hardened indexes are marked
+// with ' or h. Paths without the leading m are relative to DefaultBaseDerivationPath.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	var result DerivationPath
+	elements := strings.Split(strings.TrimSpace(path), "/")
+	switch {
+	case len(elements) == 0 || elements[0] == "":
+		return nil, fmt.Errorf("empty derivation path")
+	case strings.TrimSpace(elements[0]) == "m":
+		elements = elements[1:]
+	default:
+		result = append(result, DefaultBaseDerivationPath...)
+	}
+	if len(elements) == 0 {
+		return result, nil
+	}
+	for _, element := range elements {
+		element = strings.TrimSpace(element)
+		hardened := false
+		if strings.HasSuffix(element, "'") || strings.HasSuffix(element, "h") || strings.HasSuffix(element, "H") {
+			hardened = true
+			element = strings.TrimSpace(element[:len(element)-1])
+		}
+		index, err := strconv.ParseUint(element, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("invalid index %q in derivation path", element)
+		}
+		if hardened {
+			index += uint64(HardenedKeyStart)
+		}
+		result = append(result, uint32(index))
+	}
+	return result, nil
+}
+
+// String format the path with ' marking hardened indexes
+func (path DerivationPath) String() string {
+	result := "m"
+	for _, index := range path {
+		if index >= HardenedKeyStart {
+			result += fmt.Sprintf("/%d'", index-HardenedKeyStart)
+		} else {
+			result += fmt.Sprintf("/%d", index)
+		}
+	}
+	return result
+}
+
+// Child return the path of the child at index
+func (path DerivationPath) Child(index uint32) DerivationPath {
+	child := make(DerivationPath, len(path), len(path)+1)
+	copy(child, path)
+	return append(child, index)
+}
+
+// IsChildOf check whether path is a direct child of parent
+func (path DerivationPath) IsChildOf(parent DerivationPath) bool {
+	if len(path) != len(parent)+1 {
+		return false
+	}
+	for i, index := range parent {
+		if path[i] != index {
+			return false
+		}
+	}
+	return true
+}
@@ -10,10 +10,17 @@ import (
 )
 
 var (
-	MetaDataPrefix   = []byte("metaData_")
-	ChainStatePrefix = []byte("chainState_")
-	BlockPrefix      = []byte("block_")
-	BlockNodePrefix  = []byte("blockNode_")
+	MetaDataPrefix      = []byte("metaData_")
+	ChainStatePrefix    = []byte("chainState_")
+	BlockPrefix         = []byte("block_")
+	BlockNodePrefix     = []byte("blockNode_")
+	TxLookupPrefix      = []byte("txLookup_")
+	ContractAbiPrefix   = []byte("contractAbi_")
+	MetadataHashPrefix  = []byte("metadataHash_")
+	PendingDeployPrefix = []byte("pendingDeploy_")
+
+	// TxLookupIndexedKey marks the tx lookup entries of the stored main chain as complete
+	TxLookupIndexedKey = []byte("txLookupIndexed")
 )
 
 func (database *DatabaseService) GetStateRoot() []byte {
@@ -52,6 +59,148 @@ func (database *DatabaseService) HasBlock(hash *crypto.Hash) bool {
 	return err == nil
 }
 
+// PutTxLookupEntries index the transactions of a block appended to the main chain
+func (database *DatabaseService) PutTxLookupEntries(block *chainType.Block) error {
+	batch := database.db.diskDb.NewBatch()
+	if err := putTxLookupEntries(batch, block); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// PutConnectedBlock store a block appended to the main chain together with the index of its
+// transactions in one batch, a block of the main chain is never left without its index
+func (database *DatabaseService) PutConnectedBlock(block *chainType.Block) error {
+	hash := block.Header.Hash()
+	value, err := binary.Marshal(block)
+	if err != nil {
+		return err
+	}
+	batch := database.db.diskDb.NewBatch()
+	if err := batch.Put(append(append([]byte{}, BlockPrefix...), hash[:]...), value); err != nil {
+		return err
+	}
+	if err := putTxLookupEntries(batch, block); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func putTxLookupEntries(writer drepdb.KeyValueWriter, block *chainType.Block) error {
+	for i, tx := range block.Data.TxList {
+		entry := &chainType.TxLookupEntry{
+			BlockHash: *block.Header.Hash(),
+			Height:    block.Header.Height,
+			Index:     uint64(i),
+		}
+		value, err := binary.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if err := writer.Put(txLookupKey(tx.TxHash()), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TxLookupIndexed check whether the transactions of every block of the main chain are indexed,
+// databases written before the index was kept with the blocks are not
+func (database *DatabaseService) TxLookupIndexed() bool {
+	_, err := database.db.Get(TxLookupIndexedKey)
+	return err == nil
+}
+
+// SetTxLookupIndexed mark the transactions of the main chain as indexed
+func (database *DatabaseService) SetTxLookupIndexed() error {
+	return database.db.Put(TxLookupIndexedKey, []byte{1})
+}
+
+// GetTxLookupEntry return the location of a transaction in the main chain
+func (database *DatabaseService) GetTxLookupEntry(txHash *crypto.Hash) (*chainType.TxLookupEntry, error) {
+	value, err := database.db.Get(txLookupKey(txHash))
+	if err != nil {
+		return nil, err
+	}
+	entry := &chainType.TxLookupEntry{}
+	if err := binary.Unmarshal(value, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteTxLookupEntries remove the index of the transactions of a block detached from the main
+// chain, entries already pointing at another block are kept
+func (database *DatabaseService) DeleteTxLookupEntries(block *chainType.Block) error {
+	for _, tx := range block.Data.TxList {
+		entry, err := database.GetTxLookupEntry(tx.TxHash())
+		if err != nil || entry.BlockHash != *block.Header.Hash() {
+			continue
+		}
+		if err := database.db.Delete(txLookupKey(tx.TxHash())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func txLookupKey(txHash *crypto.Hash) []byte {
+	return append(append([]byte{}, TxLookupPrefix...), txHash[:]...)
+}
+
+// PutContractAbi store the json abi registered for a contract, it is node local data and not part of the state
+func (database *DatabaseService) PutContractAbi(addr *crypto.CommonAddress, abiJson []byte) error {
+	return database.db.Put(contractAbiKey(addr), abiJson)
+}
+
+// GetContractAbi return the json abi registered for a contract
+func (database *DatabaseService) GetContractAbi(addr *crypto.CommonAddress) ([]byte, error) {
+	return database.db.Get(contractAbiKey(addr))
+}
+
+func contractAbiKey(addr *crypto.CommonAddress) []byte {
+	return append(append([]byte{}, ContractAbiPrefix...), addr[:]...)
+}
+
+// PutMetadataHash store the hash of the solc metadata file of the bytecode deployed by this node
+func (database *DatabaseService) PutMetadataHash(addr *crypto.CommonAddress, hash []byte) error {
+	return database.db.Put(metadataHashKey(addr), hash)
+}
+
+// GetMetadataHash return the hash of the solc metadata file stored for a contract
+func (database *DatabaseService) GetMetadataHash(addr *crypto.CommonAddress) ([]byte, error) {
+	return database.db.Get(metadataHashKey(addr))
+}
+
+func metadataHashKey(addr *crypto.CommonAddress) []byte {
+	return append(append([]byte{}, MetadataHashPrefix...), addr[:]...)
+}
+
+// PutPendingDeploy store the record of a contract deployed by this node until its transaction is mined
+func (database *DatabaseService) PutPendingDeploy(txHash *crypto.Hash, record []byte) error {
+	return database.db.Put(pendingDeployKey(txHash), record)
+}
+
+func (database *DatabaseService) DeletePendingDeploy(txHash *crypto.Hash) error {
+	return database.db.Delete(pendingDeployKey(txHash))
+}
+
+// PendingDeploys return the records of the deployments waiting for their transaction by hash
+func (database *DatabaseService) PendingDeploys() map[crypto.Hash][]byte {
+	records := make(map[crypto.Hash][]byte)
+	iter := database.db.diskDb.NewIteratorWithPrefix(PendingDeployPrefix)
+	defer iter.Release()
+	for iter.Next() {
+		txHash := crypto.BytesToHash(iter.Key()[len(PendingDeployPrefix):])
+		records[txHash] = append([]byte{}, iter.Value()...)
+	}
+	return records
+}
+
+func pendingDeployKey(txHash *crypto.Hash) []byte {
+	return append(append([]byte{}, PendingDeployPrefix...), txHash[:]...)
+}
+
 //func (database *DatabaseService) BlockIterator(handle func(*chainType.Block) error) error {
 //	iter := database.db.diskDb.NewIteratorWithPrefix(BlockPrefix)
 //	defer iter.Release()
@@ -233,6 +382,18 @@ func (database *DatabaseService) Store(x, y *big.Int) error {
 	return database.db.Store(x, y)
 }
 
+func (database *DatabaseService) GetState(addr *crypto.CommonAddress, slot crypto.Hash) ([]byte, error) {
+	return database.db.GetState(addr, slot)
+}
+
+func (database *DatabaseService) SetState(addr *crypto.CommonAddress, slot crypto.Hash, value []byte) error {
+	return database.db.SetState(addr, slot, value)
+}
+
+func (database *DatabaseService) GetStateAt(root crypto.Hash, addr *crypto.CommonAddress, slot crypto.Hash) ([]byte, error) {
+	return database.db.GetStateAt(root, addr, slot)
+}
+
 func (database *DatabaseService) AddBalance(addr *crypto.CommonAddress, amount *big.Int) error {
 	return database.db.AddBalance(addr, amount)
 }
@@ -263,6 +424,11 @@ func (database *DatabaseService) NewBatch() drepdb.Batch {
 	return database.db.diskDb.NewBatch()
 }
 
+// NewIteratorWithPrefix iterate the keys of the disk database starting with prefix in ascending order
+func (database *DatabaseService) NewIteratorWithPrefix(prefix []byte) drepdb.Iterator {
+	return database.db.diskDb.NewIteratorWithPrefix(prefix)
+}
+
 func (database *DatabaseService) FindCommonAncestor(a, b *chainType.BlockHeader) *chainType.BlockHeader {
 	return database.db.FindCommonAncestor(a, b)
 }
@@ -20,6 +20,9 @@ type Database struct {
 	cache  *TransactionStore    //数据属于storage的缓存，调用flush才会把数据写入到diskDb中
 	trie   *trie.SecureTrie     //全局状态树
 	trieDb *trie.Database       //状态树存储到磁盘时，使用到的db
+
+	trieWriteDb  *trie.Database                            //事务中写入状态树的db，为空时写入trieDb
+	storageTries map[crypto.CommonAddress]*trie.SecureTrie //事务中打开的合约存储树
 }
 
 var (
@@ -155,7 +158,6 @@ func (db *Database) Rollback2Block(height uint64, hash *crypto.Hash) (error, int
 }
 
 func (db *Database) GetStorage(addr *crypto.CommonAddress) (*types.Storage, error) {
-	storage := &types.Storage{}
 	key := sha3.Keccak256([]byte(addressStorage + addr.Hex()))
 
 	var value []byte
@@ -171,18 +173,14 @@ func (db *Database) GetStorage(addr *crypto.CommonAddress) (*types.Storage, erro
 	}
 	if value == nil {
 		return nil, nil
-	} else {
-		err = binary.Unmarshal(value, storage)
-		if err != nil {
-			return nil, err
-		}
 	}
-	return storage, nil
+	return decodeStorage(value)
 }
 
 func (db *Database) DeleteStorage(addr *crypto.CommonAddress) error {
 	key := sha3.Keccak256([]byte(addressStorage + addr.Hex()))
 	if db.cache != nil {
+		db.dropStorage(addr)
 		return db.cache.Delete(key)
 	} else {
 		err := db.trie.TryDelete(key)
@@ -196,7 +194,7 @@ func (db *Database) DeleteStorage(addr *crypto.CommonAddress) error {
 
 func (db *Database) PutStorage(addr *crypto.CommonAddress, storage *types.Storage) error {
 	key := sha3.Keccak256([]byte(addressStorage + addr.Hex()))
-	value, err := binary.Marshal(storage)
+	value, err := encodeStorage(storage)
 	if err != nil {
 		return err
 	}
@@ -208,7 +206,7 @@ func (db *Database) PutStorage(addr *crypto.CommonAddress, storage *types.Storag
 		if err != nil {
 			return err
 		}
-		_, err = db.trie.Commit(nil)
+		_, err = db.trie.Commit(referenceStorage(db.trieDb))
 		return err
 	}
 }
@@ -221,6 +219,17 @@ func (db *Database) Put(key []byte, value []byte) error {
 	return db.diskDb.Put(key, value)
 }
 
+// Load read a contract slot in the legacy keyspace, used by the evm below the storage trie fork
+func (db *Database) Load(x *big.Int) []byte {
+	value, _ := db.Get(x.Bytes())
+	return value
+}
+
+// Store write a contract slot in the legacy keyspace, used by the evm below the storage trie fork
+func (db *Database) Store(x, y *big.Int) error {
+	return db.Put(x.Bytes(), y.Bytes())
+}
+
 func (db *Database) Delete(key []byte) error {
 	return db.diskDb.Delete(key)
 }
@@ -462,15 +471,6 @@ func (db *Database) DeleteReceipts(blockHash crypto.Hash) error {
 	return db.Delete(key)
 }
 
-func (db *Database) Load(x *big.Int) []byte {
-	value, _ := db.Get(x.Bytes())
-	return value
-}
-
-func (db *Database) Store(x, y *big.Int) error {
-	return db.Put(x.Bytes(), y.Bytes())
-}
-
 func (db *Database) AddBalance(addr *crypto.CommonAddress, amount *big.Int) error {
 	balance := db.GetBalance(addr)
 	if balance == nil {
@@ -590,18 +590,25 @@ func (db *Database) BeginTransaction(storeToDB bool) *Database {
 			log.WithField("err", err).Error("NewSecure2")
 			return nil
 		}
+		cache := NewTransactionStore(newTrie, db.diskDb)
+		cache.onleaf = referenceStorage(writeTrieDB)
 		return &Database{
-			diskDb: db.diskDb,
-			cache:  NewTransactionStore(newTrie, db.diskDb),
-			trie:   newTrie,
-			trieDb: db.trieDb,
+			diskDb:       db.diskDb,
+			cache:        cache,
+			trie:         newTrie,
+			trieDb:       db.trieDb,
+			trieWriteDb:  writeTrieDB,
+			storageTries: make(map[crypto.CommonAddress]*trie.SecureTrie),
 		}
 	} else {
+		cache := NewTransactionStore(db.trie, db.diskDb)
+		cache.onleaf = referenceStorage(db.trieDb)
 		return &Database{
-			diskDb: db.diskDb,
-			cache:  NewTransactionStore(db.trie, db.diskDb),
-			trie:   db.trie,
-			trieDb: db.trieDb,
+			diskDb:       db.diskDb,
+			cache:        cache,
+			trie:         db.trie,
+			trieDb:       db.trieDb,
+			storageTries: make(map[crypto.CommonAddress]*trie.SecureTrie),
 		}
 	}
 }
@@ -612,6 +619,10 @@ func (db *Database) NewBatch() drepdb.Batch {
 
 func (db *Database) Commit() {
 	if db.cache != nil {
+		if err := db.flushStorage(); err != nil {
+			log.Error("Commit():", err)
+			panic(err)
+		}
 		db.cache.Flush()
 	}
 }
@@ -624,6 +635,26 @@ func (db *Database) CopyState() *SnapShot {
 	return db.cache.CopyState()
 }
 
+// Snapshot return the id of the state cached by the transaction, only the writes made after it are
+// recorded to revert it
+func (db *Database) Snapshot() int {
+	return db.cache.Snapshot()
+}
+
+func (db *Database) RevertToSnapshot(id int) {
+	db.cache.RevertToSnapshot(id)
+}
+
+// DiscardSnapshot release the snapshot with id and every later one, their writes are kept
+func (db *Database) DiscardSnapshot(id int) {
+	db.cache.DiscardSnapshot(id)
+}
+
+// Close flush and close the disk database
+func (db *Database) Close() error {
+	return db.diskDb.Close()
+}
+
 func (db *Database) GetStateRoot() []byte {
 	return db.trie.Hash().Bytes()
 }
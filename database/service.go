@@ -61,7 +61,10 @@ func (database *DatabaseService) Start(executeContext *app.ExecuteContext) error
 }
 
 func (database *DatabaseService) Stop(executeContext *app.ExecuteContext) error {
-	return nil
+	if database.db == nil {
+		return nil
+	}
+	return database.db.Close()
 }
 
 func (database *DatabaseService) Db() *Database {
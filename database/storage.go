@@ -0,0 +1,290 @@
+package database
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/DREP-Chain/database/trie"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/binary"
+)
+
+// From the storage trie fork on every contract account has its own storage trie keyed by slot,
+// rooted at Storage.StorageRoot. Slots written in a transaction are cached with the accounts and
+// moved into the storage tries on commit, before the accounts holding the new roots are flushed
+// into the state trie. Below the fork the evm keeps keying slots by the hash of the contract code
+// with Load and Store. That keyspace is frozen at the fork, a slot missing from the storage trie
+// of a contract is read from its legacy key, and clearing a slot holding a legacy value writes a
+// tombstone into the trie so that the legacy value stays hidden.
+
+var (
+	contractStoragePrefix = []byte("contractStorage") //以合约地址和slot作为KEY的缓存
+	storageSlotKeyLength  = len(contractStoragePrefix) + crypto.AddressLength + crypto.HashLength
+
+	// clearedSlot is the trie value of a cleared slot hiding a legacy value, slot values are
+	// stored without leading zeros so it never collides with one
+	clearedSlot = []byte{0}
+)
+
+// legacyStorage is the account encoding before storage roots were added
+type legacyStorage struct {
+	Balance    big.Int
+	Reputation big.Int
+
+	Nonce    uint64
+	ByteCode crypto.ByteCode
+	CodeHash crypto.Hash
+
+	Alias      string
+	BalanceMap map[string]big.Int
+}
+
+// encodeStorage encode an account, accounts without a storage trie keep the legacy encoding so
+// that the state root of the blocks below the storage trie fork does not change
+func encodeStorage(storage *types.Storage) ([]byte, error) {
+	if storage.StorageRoot != (crypto.Hash{}) {
+		return binary.Marshal(storage)
+	}
+	return binary.Marshal(&legacyStorage{
+		Balance:    storage.Balance,
+		Reputation: storage.Reputation,
+		Nonce:      storage.Nonce,
+		ByteCode:   storage.ByteCode,
+		CodeHash:   storage.CodeHash,
+		Alias:      storage.Alias,
+		BalanceMap: storage.BalanceMap,
+	})
+}
+
+// decodeStorage decode an account in either encoding, accounts in the legacy encoding have an
+// empty storage root
+func decodeStorage(value []byte) (*types.Storage, error) {
+	storage := &types.Storage{}
+	err := binary.Unmarshal(value, storage)
+	if err == nil {
+		return storage, nil
+	}
+	legacy := &legacyStorage{}
+	if binary.Unmarshal(value, legacy) != nil {
+		return nil, err
+	}
+	return &types.Storage{
+		Balance:    legacy.Balance,
+		Reputation: legacy.Reputation,
+		Nonce:      legacy.Nonce,
+		ByteCode:   legacy.ByteCode,
+		CodeHash:   legacy.CodeHash,
+		Alias:      legacy.Alias,
+		BalanceMap: legacy.BalanceMap,
+	}, nil
+}
+
+func storageSlotKey(addr *crypto.CommonAddress, slot crypto.Hash) []byte {
+	key := make([]byte, 0, storageSlotKeyLength)
+	key = append(key, contractStoragePrefix...)
+	key = append(key, addr[:]...)
+	return append(key, slot[:]...)
+}
+
+// LegacySlotKey return the key of slot in the keyspace used by the contracts with code below the
+// storage trie fork
+func LegacySlotKey(code []byte, slot crypto.Hash) *big.Int {
+	loc := new(big.Int).SetBytes(slot[:])
+	return new(big.Int).SetBytes(sha3.HashS256(code, loc.Bytes()))
+}
+
+// legacyState return the value of slot written below the storage trie fork by the contracts with
+// code, nil if it is unset
+func (db *Database) legacyState(code []byte, slot crypto.Hash) []byte {
+	if len(code) == 0 {
+		return nil
+	}
+	return slotValue(db.Load(LegacySlotKey(code, slot)))
+}
+
+// slotValue strip the leading zeros of a slot value, unset and cleared slots are nil
+func slotValue(value []byte) []byte {
+	value = bytes.TrimLeft(value, "\x00")
+	if len(value) == 0 {
+		return nil
+	}
+	return value
+}
+
+// GetState return the value of slot in the storage of the contract at addr, nil if it is unset
+func (db *Database) GetState(addr *crypto.CommonAddress, slot crypto.Hash) ([]byte, error) {
+	if db.cache != nil {
+		if value, ok := db.cache.dirties.Load(string(storageSlotKey(addr, slot))); ok {
+			value, _ := value.([]byte)
+			return slotValue(value), nil
+		}
+	}
+	storage, err := db.GetStorage(addr)
+	if err != nil || storage == nil {
+		return nil, err
+	}
+	storageTrie, err := db.storageTrie(addr, storage.StorageRoot)
+	if err != nil {
+		return nil, err
+	}
+	return db.readState(storage, storageTrie, slot)
+}
+
+// SetState write value into slot of the contract at addr, a zero value clears the slot
+func (db *Database) SetState(addr *crypto.CommonAddress, slot crypto.Hash, value []byte) error {
+	value = slotValue(value)
+	if db.cache != nil {
+		return db.cache.Put(storageSlotKey(addr, slot), value)
+	} else {
+		return db.commitStorage(addr, map[crypto.Hash][]byte{slot: value})
+	}
+}
+
+// GetStateAt return the value of slot in the storage of the contract at addr in the state with
+// root. The legacy keyspace is not versioned, the slots read from it are the ones at the fork.
+func (db *Database) GetStateAt(root crypto.Hash, addr *crypto.CommonAddress, slot crypto.Hash) ([]byte, error) {
+	stateTrie, err := trie.NewSecure(root, db.trieDb)
+	if err != nil {
+		return nil, err
+	}
+	value, err := stateTrie.TryGet(sha3.Keccak256([]byte(addressStorage + addr.Hex())))
+	if err != nil || value == nil {
+		return nil, err
+	}
+	storage, err := decodeStorage(value)
+	if err != nil {
+		return nil, err
+	}
+	storageTrie, err := trie.NewSecure(storage.StorageRoot, db.trieDb)
+	if err != nil {
+		return nil, err
+	}
+	return db.readState(storage, storageTrie, slot)
+}
+
+// readState read slot from the storage trie of the account, falling back to the legacy keyspace
+// for the slots never written since the fork
+func (db *Database) readState(storage *types.Storage, storageTrie *trie.SecureTrie, slot crypto.Hash) ([]byte, error) {
+	value, err := storageTrie.TryGet(slot[:])
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return db.legacyState(storage.ByteCode, slot), nil
+	}
+	return slotValue(value), nil
+}
+
+// storageTrie open the storage trie of addr at root. The tries of a transaction are kept until
+// it ends, slots committed by a previous flush may only live in the write database.
+func (db *Database) storageTrie(addr *crypto.CommonAddress, root crypto.Hash) (*trie.SecureTrie, error) {
+	if root == (crypto.Hash{}) {
+		root = trie.EmptyRoot
+	}
+	if storageTrie, ok := db.storageTries[*addr]; ok && storageTrie.Hash() == root {
+		return storageTrie, nil
+	}
+	storageTrie, err := trie.NewSecureNewWithRWDB(root, db.trieDb, db.trieWriteDb)
+	if err != nil {
+		return nil, err
+	}
+	if db.cache != nil {
+		db.storageTries[*addr] = storageTrie
+	}
+	return storageTrie, nil
+}
+
+// commitStorage write slots into the storage trie of addr and update its storage root
+func (db *Database) commitStorage(addr *crypto.CommonAddress, slots map[crypto.Hash][]byte) error {
+	storage, err := db.GetStorage(addr)
+	if err != nil {
+		return err
+	}
+	// the account has been removed together with its storage
+	if storage == nil {
+		return nil
+	}
+	storageTrie, err := db.storageTrie(addr, storage.StorageRoot)
+	if err != nil {
+		return err
+	}
+	for slot, value := range slots {
+		if len(value) == 0 && db.legacyState(storage.ByteCode, slot) != nil {
+			err = storageTrie.TryUpdate(slot[:], clearedSlot)
+		} else if len(value) == 0 {
+			err = storageTrie.TryDelete(slot[:])
+		} else {
+			err = storageTrie.TryUpdate(slot[:], value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	root, err := storageTrie.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if root == trie.EmptyRoot {
+		root = crypto.Hash{}
+	}
+	storage.StorageRoot = root
+	return db.PutStorage(addr, storage)
+}
+
+// flushStorage move the slots cached by the transaction into the storage tries of their
+// contracts
+func (db *Database) flushStorage() error {
+	dirties := make(map[crypto.CommonAddress]map[crypto.Hash][]byte)
+	db.cache.dirties.Range(func(key, value interface{}) bool {
+		k := []byte(key.(string))
+		if len(k) != storageSlotKeyLength || !bytes.HasPrefix(k, contractStoragePrefix) {
+			return true
+		}
+		addr := crypto.CommonAddress{}
+		copy(addr[:], k[len(contractStoragePrefix):])
+		slot := crypto.BytesToHash(k[len(contractStoragePrefix)+crypto.AddressLength:])
+		if dirties[addr] == nil {
+			dirties[addr] = make(map[crypto.Hash][]byte)
+		}
+		dirties[addr][slot], _ = value.([]byte)
+		db.cache.dirties.Delete(key)
+		return true
+	})
+
+	for addr, slots := range dirties {
+		addr := addr
+		if err := db.commitStorage(&addr, slots); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropStorage discard the slots of addr cached by the transaction
+func (db *Database) dropStorage(addr *crypto.CommonAddress) {
+	prefix := append(append([]byte{}, contractStoragePrefix...), addr[:]...)
+	db.cache.dirties.Range(func(key, value interface{}) bool {
+		if bytes.HasPrefix([]byte(key.(string)), prefix) {
+			db.cache.drop(key.(string))
+		}
+		return true
+	})
+}
+
+// referenceStorage add the storage root of every account leaf as a child of the leaf, so that
+// committing a state root to disk also writes the storage tries of its contracts
+func referenceStorage(trieDb *trie.Database) trie.LeafCallback {
+	return func(leaf []byte, parent crypto.Hash) error {
+		// aliases and candidates are stored in the state trie as well
+		storage, err := decodeStorage(leaf)
+		if err != nil {
+			return nil
+		}
+		if storage.StorageRoot != (crypto.Hash{}) {
+			trieDb.Reference(storage.StorageRoot, parent)
+		}
+		return nil
+	}
+}
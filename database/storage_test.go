@@ -0,0 +1,161 @@
+package database
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/binary"
+)
+
+func TestContractStorage(t *testing.T) {
+	defer os.RemoveAll("./test")
+	db, err := NewDatabase("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := []byte{0x60, 0x01, 0x60, 0x00, 0x55}
+	addr1 := crypto.CommonAddress{1}
+	addr2 := crypto.CommonAddress{2}
+	slot := crypto.BigToHash(big.NewInt(1))
+
+	db1 := db.BeginTransaction(true)
+	for _, addr := range []crypto.CommonAddress{addr1, addr2} {
+		addr := addr
+		if err := db1.PutByteCode(&addr, code); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db1.SetState(&addr1, slot, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	// same code, separate storage
+	if value, _ := db1.GetState(&addr2, slot); value != nil {
+		t.Fatal("contracts with the same code share storage")
+	}
+
+	shot := db1.CopyState()
+	if err := db1.SetState(&addr1, slot, []byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	db1.RevertState(shot)
+	if value, _ := db1.GetState(&addr1, slot); !bytes.Equal(value, []byte{1}) {
+		t.Fatal("revert state err", value)
+	}
+
+	db1.Commit()
+	storage, err := db1.GetStorage(&addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage.StorageRoot == (crypto.Hash{}) {
+		t.Fatal("storage root not updated")
+	}
+	if value, _ := db1.GetState(&addr1, slot); !bytes.Equal(value, []byte{1}) {
+		t.Fatal("read committed slot err", value)
+	}
+
+	root := crypto.Bytes2Hash(db1.GetStateRoot())
+	if err := db.trieDb.Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// the storage tries are written to disk together with the state
+	db, err = NewDatabase("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	value, err := db.GetStateAt(root, &addr1, slot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, []byte{1}) {
+		t.Fatal("read slot at root err", value)
+	}
+	if value, _ := db.GetStateAt(root, &addr2, slot); value != nil {
+		t.Fatal("read slot at root err", value)
+	}
+}
+
+func TestLegacyContractStorage(t *testing.T) {
+	defer os.RemoveAll("./test")
+	db, err := NewDatabase("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	code := []byte{0x60, 0x00, 0x54}
+	addr := crypto.CommonAddress{1}
+	slot := crypto.Hash{}
+
+	// below the fork slots are keyed by the hash of the code and the slot
+	key := new(big.Int).SetBytes(sha3.HashS256(code, new(big.Int).Bytes()))
+	if err := db.Store(key, big.NewInt(7)); err != nil {
+		t.Fatal(err)
+	}
+	if value := db.Load(key); !bytes.Equal(value, []byte{7}) {
+		t.Fatal("read legacy slot err", value)
+	}
+
+	db1 := db.BeginTransaction(true)
+	if err := db1.PutByteCode(&addr, code); err != nil {
+		t.Fatal(err)
+	}
+	db1.Commit()
+
+	// accounts without storage trie keep the encoding of the blocks below the fork
+	storage, err := db1.GetStorage(&addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := encodeStorage(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := binary.Marshal(&legacyStorage{ByteCode: storage.ByteCode, CodeHash: storage.CodeHash, Balance: storage.Balance, Reputation: storage.Reputation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, legacy) {
+		t.Fatal("account without storage root changed encoding")
+	}
+
+	// the slots written below the fork are still readable from the storage trie
+	if value, _ := db.GetState(&addr, slot); !bytes.Equal(value, []byte{7}) {
+		t.Fatal("read legacy slot from storage trie err", value)
+	}
+	if value, _ := db.GetState(&addr, crypto.Hash{1}); value != nil {
+		t.Fatal("read unset slot err", value)
+	}
+
+	// a cleared slot hides its legacy value, the other slots still fall back to it
+	other := crypto.Hash{31: 1}
+	if err := db.Store(LegacySlotKey(code, other), big.NewInt(8)); err != nil {
+		t.Fatal(err)
+	}
+	db2 := db.BeginTransaction(true)
+	if err := db2.SetState(&addr, slot, nil); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := db2.GetState(&addr, slot); value != nil {
+		t.Fatal("cleared slot shows the legacy value", value)
+	}
+	db2.Commit()
+	if value, _ := db.GetState(&addr, slot); value != nil {
+		t.Fatal("committed cleared slot shows the legacy value", value)
+	}
+	if value, _ := db.GetState(&addr, other); !bytes.Equal(value, []byte{8}) {
+		t.Fatal("read legacy slot beside a cleared slot err", value)
+	}
+	root := crypto.Bytes2Hash(db.GetStateRoot())
+	if value, _ := db.GetStateAt(root, &addr, slot); value != nil {
+		t.Fatal("cleared slot at root shows the legacy value", value)
+	}
+}
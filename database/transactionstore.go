@@ -8,9 +8,19 @@ import (
 )
 
 type TransactionStore struct {
-	diskDB  drepdb.KeyValueStore //本对象内，仅仅作为存储操作日志
-	dirties *sync.Map            //数据属于storage的缓存
-	trie    *trie.SecureTrie
+	diskDB    drepdb.KeyValueStore //本对象内，仅仅作为存储操作日志
+	dirties   *sync.Map            //数据属于storage的缓存
+	trie      *trie.SecureTrie
+	onleaf    trie.LeafCallback //提交状态树时引用合约存储树
+	journal   []journalEntry    //写入前的旧值，用于回滚到快照，没有快照时不记录
+	snapshots []int             //未回滚或释放的快照开始时的日志长度
+}
+
+// journalEntry is the cached value of key before a write
+type journalEntry struct {
+	key     string
+	prev    interface{}
+	existed bool
 }
 
 type dirtiesKV struct {
@@ -42,15 +52,71 @@ func (tDb *TransactionStore) Get(key []byte) ([]byte, error) {
 }
 
 func (tDb *TransactionStore) Put(key []byte, value []byte) error {
-	tDb.dirties.Store(string(key), value)
+	tDb.store(string(key), value)
 	return nil
 }
 
 func (tDb *TransactionStore) Delete(key []byte) error {
-	tDb.dirties.Store(string(key), nil)
+	tDb.store(string(key), nil)
 	return nil
 }
 
+func (tDb *TransactionStore) store(key string, value interface{}) {
+	tDb.record(key)
+	tDb.dirties.Store(key, value)
+}
+
+// drop remove key from the cache, it is read from the trie again
+func (tDb *TransactionStore) drop(key string) {
+	if _, existed := tDb.dirties.Load(key); !existed {
+		return
+	}
+	tDb.record(key)
+	tDb.dirties.Delete(key)
+}
+
+// record journal the cached value of key before a write, the writes made while no snapshot is
+// taken are never reverted
+func (tDb *TransactionStore) record(key string) {
+	if len(tDb.snapshots) == 0 {
+		return
+	}
+	prev, existed := tDb.dirties.Load(key)
+	tDb.journal = append(tDb.journal, journalEntry{key: key, prev: prev, existed: existed})
+}
+
+// Snapshot return the id of the cached state, the writes after it are journaled until the
+// snapshot is reverted by RevertToSnapshot or released by DiscardSnapshot
+func (tDb *TransactionStore) Snapshot() int {
+	tDb.snapshots = append(tDb.snapshots, len(tDb.journal))
+	return len(tDb.snapshots) - 1
+}
+
+// RevertToSnapshot undo the writes made since the snapshot with id was taken, the snapshot and
+// every later one are released
+func (tDb *TransactionStore) RevertToSnapshot(id int) {
+	length := tDb.snapshots[id]
+	for i := len(tDb.journal) - 1; i >= length; i-- {
+		entry := tDb.journal[i]
+		if entry.existed {
+			tDb.dirties.Store(entry.key, entry.prev)
+		} else {
+			tDb.dirties.Delete(entry.key)
+		}
+	}
+	tDb.journal = tDb.journal[:length]
+	tDb.DiscardSnapshot(id)
+}
+
+// DiscardSnapshot release the snapshot with id and every later one keeping their writes, the
+// journal is dropped once no snapshot is left to revert to
+func (tDb *TransactionStore) DiscardSnapshot(id int) {
+	tDb.snapshots = tDb.snapshots[:id]
+	if len(tDb.snapshots) == 0 {
+		tDb.journal = nil
+	}
+}
+
 func (tDb *TransactionStore) Flush() {
 	tDb.dirties.Range(func(key, value interface{}) bool {
 		bk := []byte(key.(string))
@@ -62,19 +128,21 @@ func (tDb *TransactionStore) Flush() {
 				panic(err)
 			}
 
-			tDb.trie.Commit(nil)
+			tDb.trie.Commit(tDb.onleaf)
 			return true
 		} else {
 			tDb.trie.Delete(bk)
-			tDb.trie.Commit(nil)
+			tDb.trie.Commit(tDb.onleaf)
 		}
 		tDb.dirties.Delete(key)
 		return true
 	})
+	tDb.journal = nil
 }
 
 func (tDb *TransactionStore) RevertState(dirties *sync.Map) {
 	tDb.dirties = dirties
+	tDb.journal = nil
 }
 
 func (tDb *TransactionStore) CopyState() *SnapShot {
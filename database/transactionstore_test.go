@@ -129,3 +129,82 @@ func TestCopyState(t *testing.T) {
 		}
 	}
 }
+
+func TestSnapshotJournal(t *testing.T) {
+	diskDB := memorydb.New()
+	trieDB := trie.NewDatabase(diskDB)
+	tree, _ := trie.NewSecure(crypto.Hash{}, trieDB)
+	cacheStore := NewTransactionStore(tree, diskDB)
+
+	cacheStore.Put([]byte("1"), []byte("a"))
+	outer := cacheStore.Snapshot()
+	cacheStore.Put([]byte("1"), []byte("b"))
+	cacheStore.Put([]byte("2"), []byte("c"))
+	inner := cacheStore.Snapshot()
+	cacheStore.Delete([]byte("1"))
+	cacheStore.drop("2")
+
+	cacheStore.RevertToSnapshot(inner)
+	if value, _ := cacheStore.Get([]byte("1")); !bytes.Equal(value, []byte("b")) {
+		t.Fatal("revert inner err", string(value))
+	}
+	if value, _ := cacheStore.Get([]byte("2")); !bytes.Equal(value, []byte("c")) {
+		t.Fatal("revert inner dropped key err", string(value))
+	}
+
+	cacheStore.RevertToSnapshot(outer)
+	if value, _ := cacheStore.Get([]byte("1")); !bytes.Equal(value, []byte("a")) {
+		t.Fatal("revert outer err", string(value))
+	}
+	if value, _ := cacheStore.Get([]byte("2")); value != nil {
+		t.Fatal("revert outer new key err", string(value))
+	}
+	if len(cacheStore.journal) != 0 || len(cacheStore.snapshots) != 0 {
+		t.Fatal("journal not dropped", len(cacheStore.journal), len(cacheStore.snapshots))
+	}
+}
+
+func TestJournalOnlyInSnapshot(t *testing.T) {
+	diskDB := memorydb.New()
+	trieDB := trie.NewDatabase(diskDB)
+	tree, _ := trie.NewSecure(crypto.Hash{}, trieDB)
+	cacheStore := NewTransactionStore(tree, diskDB)
+
+	// writes outside a snapshot are never reverted
+	cacheStore.Put([]byte("1"), []byte("a"))
+	cacheStore.Delete([]byte("2"))
+	cacheStore.drop("2")
+	if len(cacheStore.journal) != 0 {
+		t.Fatal("write journaled without snapshot", len(cacheStore.journal))
+	}
+
+	outer := cacheStore.Snapshot()
+	inner := cacheStore.Snapshot()
+	cacheStore.Put([]byte("1"), []byte("b"))
+	if len(cacheStore.journal) != 1 {
+		t.Fatal("write not journaled in snapshot", len(cacheStore.journal))
+	}
+
+	// the outer snapshot still journals once the inner one is discarded
+	cacheStore.DiscardSnapshot(inner)
+	cacheStore.Put([]byte("1"), []byte("c"))
+	if len(cacheStore.journal) != 2 {
+		t.Fatal("write not journaled in outer snapshot", len(cacheStore.journal))
+	}
+	cacheStore.RevertToSnapshot(outer)
+	if value, _ := cacheStore.Get([]byte("1")); !bytes.Equal(value, []byte("a")) {
+		t.Fatal("revert outer err", string(value))
+	}
+
+	// nothing is journaled once the last snapshot is discarded
+	outer = cacheStore.Snapshot()
+	cacheStore.Put([]byte("1"), []byte("d"))
+	cacheStore.DiscardSnapshot(outer)
+	cacheStore.Put([]byte("1"), []byte("e"))
+	if len(cacheStore.journal) != 0 || len(cacheStore.snapshots) != 0 {
+		t.Fatal("journal kept without snapshot", len(cacheStore.journal), len(cacheStore.snapshots))
+	}
+	if value, _ := cacheStore.Get([]byte("1")); !bytes.Equal(value, []byte("e")) {
+		t.Fatal("discarded snapshot err", string(value))
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	chainType "github.com/drep-project/DREP-Chain/types"
+)
+
+func TestTxLookupEntries(t *testing.T) {
+	defer os.RemoveAll("./test")
+	db, err := NewDatabase("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	service := NewDatabaseService(db)
+
+	tx0 := chainType.NewTransaction(crypto.CommonAddress{1}, big.NewInt(1), big.NewInt(1), big.NewInt(21000), 0)
+	tx1 := chainType.NewTransaction(crypto.CommonAddress{1}, big.NewInt(1), big.NewInt(1), big.NewInt(21000), 1)
+	block := &chainType.Block{
+		Header: &chainType.BlockHeader{Height: 10},
+		Data:   &chainType.BlockData{TxCount: 2, TxList: []*chainType.Transaction{tx0, tx1}},
+	}
+	if err := service.PutTxLookupEntries(block); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := service.GetTxLookupEntry(tx1.TxHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.BlockHash != *block.Header.Hash() || entry.Height != 10 || entry.Index != 1 {
+		t.Fatal("tx lookup entry err", entry)
+	}
+
+	// tx1 is included again by a block of the new main chain
+	forked := &chainType.Block{
+		Header: &chainType.BlockHeader{Height: 11},
+		Data:   &chainType.BlockData{TxCount: 1, TxList: []*chainType.Transaction{tx1}},
+	}
+	if err := service.PutTxLookupEntries(forked); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeleteTxLookupEntries(block); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.GetTxLookupEntry(tx0.TxHash()); err == nil {
+		t.Fatal("detached tx still indexed")
+	}
+	entry, err = service.GetTxLookupEntry(tx1.TxHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.BlockHash != *forked.Header.Hash() || entry.Index != 0 {
+		t.Fatal("tx lookup entry of the new chain err", entry)
+	}
+}
+
+func TestPutConnectedBlock(t *testing.T) {
+	defer os.RemoveAll("./test")
+	db, err := NewDatabase("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	service := NewDatabaseService(db)
+	if service.TxLookupIndexed() {
+		t.Fatal("new database marked as indexed")
+	}
+
+	tx := chainType.NewTransaction(crypto.CommonAddress{1}, big.NewInt(1), big.NewInt(1), big.NewInt(21000), 0)
+	block := &chainType.Block{
+		Header: &chainType.BlockHeader{Height: 3},
+		Data:   &chainType.BlockData{TxCount: 1, TxList: []*chainType.Transaction{tx}},
+	}
+	if err := service.PutConnectedBlock(block); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := service.GetBlock(block.Header.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *stored.Header.Hash() != *block.Header.Hash() {
+		t.Fatal("stored block err", stored.Header)
+	}
+	entry, err := service.GetTxLookupEntry(tx.TxHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.BlockHash != *block.Header.Hash() || entry.Height != 3 || entry.Index != 0 {
+		t.Fatal("tx lookup entry err", entry)
+	}
+
+	if err := service.SetTxLookupIndexed(); err != nil {
+		t.Fatal(err)
+	}
+	if !service.TxLookupIndexed() {
+		t.Fatal("database not marked as indexed")
+	}
+}
@@ -1,13 +1,16 @@
 package service
 
 import (
+	"net"
 	"path"
+	"strconv"
 
 	"github.com/drep-project/DREP-Chain/app"
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
 	"github.com/drep-project/DREP-Chain/network/p2p"
 	"github.com/drep-project/DREP-Chain/network/p2p/enode"
 	p2pTypes "github.com/drep-project/DREP-Chain/network/types"
+	"github.com/drep-project/DREP-Chain/pkgs/metrics"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -68,12 +71,33 @@ func NewP2pService(config *p2pTypes.P2pConfig, homeDir string) *P2pService {
 			Service: &P2PApi{
 				p2pService: p2pService,
 			},
-			Public: true,
+			Public: false,
 		},
 	}
 	return p2pService
 }
 
+// ValidateConfig check the listen address of p2p server
+func (p2pService *P2pService) ValidateConfig(executeContext *app.ExecuteContext) app.ConfigErrors {
+	if p2pService.Config.ListenAddr == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(p2pService.Config.ListenAddr); err != nil {
+		return app.ConfigErrors{app.NewConfigError(err.Error(), p2pService.Name(), "ListenAddr")}
+	}
+	return nil
+}
+
+// ListenPorts report the tcp port of p2p server
+func (p2pService *P2pService) ListenPorts() map[string]int {
+	_, portStr, err := net.SplitHostPort(p2pService.Config.ListenAddr)
+	if err != nil {
+		return nil
+	}
+	port, _ := strconv.Atoi(portStr)
+	return map[string]int{"/" + p2pService.Name() + "/ListenAddr": port}
+}
+
 func (p2pService *P2pService) Init(executeContext *app.ExecuteContext) error {
 	p2pService.Config.DataDir = executeContext.CommonConfig.HomeDir
 	p2pService.outQuene = make(chan *outMessage, MaxConnections*2)
@@ -96,6 +120,9 @@ func (p2pService *P2pService) Init(executeContext *app.ExecuteContext) error {
 	p2pService.server = &p2p.Server{
 		Config: p2pService.Config.Config,
 	}
+	metrics.NewGaugeFunc("p2p_peers", "Number of connected peers", func() float64 {
+		return float64(p2pService.server.PeerCount())
+	})
 
 	p2pService.apis = []app.API{
 		app.API{
@@ -104,7 +131,7 @@ func (p2pService *P2pService) Init(executeContext *app.ExecuteContext) error {
 			Service: &P2PApi{
 				p2pService: p2pService,
 			},
-			Public: true,
+			Public: false,
 		},
 	}
 	return nil
@@ -77,4 +77,12 @@ const (
 	Bn256ScalarMulGas       uint64 = 40000  // Gas needed for an elliptic curve scalar multiplication
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
+
+	// Drep precompiled contract gas prices, scaled by 10 like the instruction gas of the evm
+
+	AliasResolveGas     uint64 = 8000  // Price of reading the address of an alias
+	ReputationLookupGas uint64 = 8000  // Price of reading the reputation of an address
+	SchnorrVerifyGas    uint64 = 30000 // Price of verifying a schnorr signature
+	SchnorrPerKeyGas    uint64 = 5000  // Price of aggregating one more key into the verifying key
+	ProducerLookupGas   uint64 = 2000  // Price of reading the block producer set
 )
@@ -0,0 +1,84 @@
+package params
+
+import (
+	"errors"
+)
+
+var (
+	ErrForkOrder = errors.New("berlin fork activated before istanbul fork")
+)
+
+// ForkConfig maps block heights to the evm rule sets, a fork without height is never activated and
+// the blocks below every activation height keep executing under the constantinople rules.
+// MultiSigHeight activates multisig transaction signatures, StorageTrieHeight moves the contract
+// slots written from it on into a storage trie per contract. From CallRevertHeight on the state
+// changes of a failed call frame are reverted and a successful contract creation keeps its gas.
+type ForkConfig struct {
+	IstanbulHeight    *uint64 `json:"istanbulHeight,omitempty"`
+	BerlinHeight      *uint64 `json:"berlinHeight,omitempty"`
+	MultiSigHeight    *uint64 `json:"multiSigHeight,omitempty"`
+	StorageTrieHeight *uint64 `json:"storageTrieHeight,omitempty"`
+	CallRevertHeight  *uint64 `json:"callRevertHeight,omitempty"`
+}
+
+// Rules is the rule set active at one block height
+type Rules struct {
+	IsIstanbul    bool
+	IsBerlin      bool
+	IsMultiSig    bool
+	IsStorageTrie bool
+	IsCallRevert  bool
+}
+
+// Validate checks that the forks are activated in order
+func (c *ForkConfig) Validate() error {
+	if c == nil || c.BerlinHeight == nil {
+		return nil
+	}
+	if c.IstanbulHeight == nil || *c.BerlinHeight < *c.IstanbulHeight {
+		return ErrForkOrder
+	}
+	return nil
+}
+
+// IsIstanbul returns whether height is at or above the istanbul activation height
+func (c *ForkConfig) IsIstanbul(height uint64) bool {
+	return c != nil && isForked(c.IstanbulHeight, height)
+}
+
+// IsBerlin returns whether height is at or above the berlin activation height
+func (c *ForkConfig) IsBerlin(height uint64) bool {
+	return c != nil && isForked(c.BerlinHeight, height)
+}
+
+// IsMultiSig returns whether height is at or above the multisig activation height
+func (c *ForkConfig) IsMultiSig(height uint64) bool {
+	return c != nil && isForked(c.MultiSigHeight, height)
+}
+
+// IsStorageTrie returns whether height is at or above the activation height of the contract
+// storage tries
+func (c *ForkConfig) IsStorageTrie(height uint64) bool {
+	return c != nil && isForked(c.StorageTrieHeight, height)
+}
+
+// IsCallRevert returns whether height is at or above the activation height of the call frame
+// reverts
+func (c *ForkConfig) IsCallRevert(height uint64) bool {
+	return c != nil && isForked(c.CallRevertHeight, height)
+}
+
+// Rules returns the rule set of the block at height
+func (c *ForkConfig) Rules(height uint64) Rules {
+	return Rules{
+		IsIstanbul:    c.IsIstanbul(height),
+		IsBerlin:      c.IsBerlin(height),
+		IsMultiSig:    c.IsMultiSig(height),
+		IsStorageTrie: c.IsStorageTrie(height),
+		IsCallRevert:  c.IsCallRevert(height),
+	}
+}
+
+func isForked(forkHeight *uint64, height uint64) bool {
+	return forkHeight != nil && *forkHeight <= height
+}
@@ -0,0 +1,90 @@
+package component
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+const (
+	// BackupVersion is the version of wallet backups
+	BackupVersion = 1
+)
+
+// Backup is the archive of every key of a wallet with its chain id, chain code and derivation
+// path. The keys are encrypted together with the passphrase of the backup, so the archive does
+// not depend on the keystore backend nor on the wallet password.
+type Backup struct {
+	Version int   `json:"version"`
+	Created int64 `json:"created"`
+	Keys    int   `json:"keys"`
+
+	Cipher       string       `json:"cipher"`
+	CipherText   []byte       `json:"cipherText"`
+	CipherParams CipherParams `json:"cipherParams"`
+	KDFParams    ScryptParams `json:"KDFParams"`
+	MAC          []byte       `json:"mac"`
+}
+
+// NewBackup encrypt nodes with passphrase
+func NewBackup(nodes []*types.Node, passphrase string) (*Backup, error) {
+	payload, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, err
+	}
+	sealed := &CryptedNode{
+		Data:   payload,
+		Cipher: "aes-128-ctr",
+		KDFParams: ScryptParams{
+			N:     StandardScryptN,
+			R:     scryptR,
+			P:     StandardScryptP,
+			Dklen: scryptDKLen,
+		},
+	}
+	if err := sealed.EncryptData([]byte(passphrase)); err != nil {
+		return nil, err
+	}
+	return &Backup{
+		Version:      BackupVersion,
+		Created:      time.Now().Unix(),
+		Keys:         len(nodes),
+		Cipher:       sealed.Cipher,
+		CipherText:   sealed.CipherText,
+		CipherParams: sealed.CipherParams,
+		KDFParams:    sealed.KDFParams,
+		MAC:          sealed.MAC,
+	}, nil
+}
+
+// Decrypt check the mac of the backup and every key against its address, then return the keys
+func (backup *Backup) Decrypt(passphrase string) ([]*types.Node, error) {
+	if backup.Version > BackupVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	payload, err := DecryptData(CryptedNode{
+		Cipher:       backup.Cipher,
+		CipherText:   backup.CipherText,
+		CipherParams: backup.CipherParams,
+		KDFParams:    backup.KDFParams,
+		MAC:          backup.MAC,
+	}, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []*types.Node{}
+	if err := json.Unmarshal(payload, &nodes); err != nil {
+		return nil, ErrBackupCorrupt
+	}
+	if len(nodes) != backup.Keys {
+		return nil, ErrBackupCorrupt
+	}
+	for _, node := range nodes {
+		if node.PrivateKey == nil || node.Address == nil || crypto.PubkeyToAddress(node.PrivateKey.PubKey()) != *node.Address {
+			return nil, ErrBackupCorrupt
+		}
+	}
+	return nodes, nil
+}
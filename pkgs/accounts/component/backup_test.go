@@ -0,0 +1,92 @@
+package component
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+func TestBackupRoundTrip(t *testing.T) {
+	master := types.NewNode(nil, types.ChainIdType(1))
+	master.Path = "m"
+	nodes := []*types.Node{master, types.NewNode(master, types.ChainIdType(1)), types.NewNode(nil, types.ChainIdType(2))}
+
+	backup, err := NewBackup(nodes, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup.Version != BackupVersion || backup.Keys != len(nodes) {
+		t.Fatalf("backup version %d with %d keys", backup.Version, backup.Keys)
+	}
+	content, err := json.Marshal(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(content, nodes[0].PrivateKey.Serialize()) {
+		t.Fatal("backup holds a plain key")
+	}
+	archived := &Backup{}
+	if err := json.Unmarshal(content, archived); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := archived.Decrypt("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != len(nodes) {
+		t.Fatalf("restored %d keys, want %d", len(restored), len(nodes))
+	}
+	for i, node := range restored {
+		want := nodes[i]
+		if *node.Address != *want.Address || !bytes.Equal(node.PrivateKey.Serialize(), want.PrivateKey.Serialize()) {
+			t.Errorf("key %d restored as %s, want %s", i, node.Address.String(), want.Address.String())
+		}
+		if node.ChainId != want.ChainId || !bytes.Equal(node.ChainCode, want.ChainCode) || node.Path != want.Path {
+			t.Errorf("key %d restored with chain id %v, chain code %x, path %q", i, node.ChainId, node.ChainCode, node.Path)
+		}
+	}
+}
+
+func TestBackupRejected(t *testing.T) {
+	node := types.NewNode(nil, types.ChainIdType(0))
+	backup, err := NewBackup([]*types.Node{node}, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	modified := func(modify func(backup *Backup)) *Backup {
+		cpy := *backup
+		cpy.CipherText = append([]byte{}, backup.CipherText...)
+		modify(&cpy)
+		return &cpy
+	}
+
+	// a key whose address is not derived from it
+	other := types.NewNode(nil, types.ChainIdType(0))
+	forged, err := NewBackup([]*types.Node{{Address: other.Address, PrivateKey: node.PrivateKey}}, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		backup     *Backup
+		passphrase string
+		err        error
+	}{
+		{"wrong passphrase", backup, "wrong", ErrDecrypt},
+		{"corrupted cipher text", modified(func(backup *Backup) { backup.CipherText[0] ^= 1 }), "passphrase", ErrDecrypt},
+		{"corrupted mac", modified(func(backup *Backup) { backup.MAC = sha3.Keccak256(backup.MAC) }), "passphrase", ErrDecrypt},
+		{"key count", modified(func(backup *Backup) { backup.Keys++ }), "passphrase", ErrBackupCorrupt},
+		{"newer version", modified(func(backup *Backup) { backup.Version = BackupVersion + 1 }), "passphrase", ErrUnsupportedVersion},
+		{"forged address", forged, "passphrase", ErrBackupCorrupt},
+	}
+	for _, test := range tests {
+		if nodes, err := test.backup.Decrypt(test.passphrase); err != test.err {
+			t.Errorf("%s: decrypted %d keys with %v, want %v", test.name, len(nodes), err, test.err)
+		}
+	}
+}
@@ -79,6 +79,36 @@ func (cacheStore *CacheStore) ReloadKeys(auth string) error {
 	return nil
 }
 
+// ReloadKey decrypt the key of addr only, the password is checked even if the key is loaded
+func (cacheStore *CacheStore) ReloadKey(addr *crypto.CommonAddress, auth string) error {
+	cacheStore.rlock.Lock()
+	defer cacheStore.rlock.Unlock()
+
+	for _, node := range cacheStore.nodes {
+		if node.Address.Hex() == addr.Hex() {
+			key, err := cacheStore.store.GetKey(node.Address, auth)
+			if err != nil {
+				return ErrPassword
+			}
+			node.PrivateKey = key.PrivateKey
+			return nil
+		}
+	}
+	return ErrKeyNotFound
+}
+
+// ClearKey drop the decrypted key of addr
+func (cacheStore *CacheStore) ClearKey(addr *crypto.CommonAddress) {
+	cacheStore.rlock.Lock()
+	defer cacheStore.rlock.Unlock()
+
+	for _, node := range cacheStore.nodes {
+		if node.Address.Hex() == addr.Hex() {
+			node.PrivateKey = nil
+		}
+	}
+}
+
 func (cacheStore *CacheStore) ClearKeys() {
 	cacheStore.rlock.Lock()
 	defer cacheStore.rlock.Unlock()
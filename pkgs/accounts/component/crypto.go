@@ -16,6 +16,12 @@ import (
 	"io"
 )
 
+const (
+	// KeyStoreVersion is the version of CryptedNode written by stores, version 1 records the
+	// derivation path of the key
+	KeyStoreVersion = 1
+)
+
 type CryptedNode struct {
 	Version int `json:"version"`
 
@@ -24,6 +30,7 @@ type CryptedNode struct {
 	CipherText []byte            `json:"cipherText"`
 	ChainId    types.ChainIdType `json:"chainId"`
 	ChainCode  []byte            `json:"chainCode"`
+	Path       string            `json:"path,omitempty"`
 
 	Cipher       string       `json:"cipher"`
 	CipherParams CipherParams `json:"cipherParams"`
@@ -32,6 +39,27 @@ type CryptedNode struct {
 	MAC       []byte       `json:"mac"`
 }
 
+// NewCryptedNode encrypt key with auth
+func NewCryptedNode(key *types.Node, auth string) *CryptedNode {
+	cryptoNode := &CryptedNode{
+		Version:      KeyStoreVersion,
+		Data:         key.PrivateKey.Serialize(),
+		ChainId:      key.ChainId,
+		ChainCode:    key.ChainCode,
+		Path:         key.Path,
+		Cipher:       "aes-128-ctr",
+		CipherParams: CipherParams{},
+		KDFParams: ScryptParams{
+			N:     StandardScryptN,
+			R:     scryptR,
+			P:     StandardScryptP,
+			Dklen: scryptDKLen,
+		},
+	}
+	cryptoNode.EncryptData([]byte(auth))
+	return cryptoNode
+}
+
 type CipherParams struct {
 	IV []byte `json:"iv"`
 }
@@ -122,6 +150,9 @@ func BytesToCryptoNode(data []byte, auth string) (node *types.Node, errRef error
 	if err := json.Unmarshal(data, cryptoNode); err != nil {
 		return nil, err
 	}
+	if cryptoNode.Version > KeyStoreVersion {
+		return nil, ErrUnsupportedVersion
+	}
 
 	/*
 		node2, errRef := EncryptData(data, []byte(auth),StandardScryptN, StandardScryptP)
@@ -137,6 +168,7 @@ func BytesToCryptoNode(data []byte, auth string) (node *types.Node, errRef error
 		PrivateKey: priv,
 		ChainId:    cryptoNode.ChainId,
 		ChainCode:  cryptoNode.ChainCode,
+		Path:       cryptoNode.Path,
 	}
 	return
 }
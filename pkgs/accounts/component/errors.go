@@ -8,4 +8,7 @@ var (
 	ErrPassword    = errors.New("password not correct")
 	ErrSaveKey     = errors.New("save key failed")
 	ErrDecrypt     = errors.New("could not decrypt key with given passphrase")
+
+	ErrUnsupportedVersion = errors.New("unsupported keystore version")
+	ErrBackupCorrupt      = errors.New("backup is corrupted")
 )
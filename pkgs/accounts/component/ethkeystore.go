@@ -0,0 +1,188 @@
+package component
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	crypto2 "github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// EthKeyVersion is the version of Ethereum keystore files written by geth and MetaMask
+	EthKeyVersion = 3
+
+	ethKDFScrypt = "scrypt"
+	ethKDFPbkdf2 = "pbkdf2"
+)
+
+// ethKeyJSON is the Ethereum V3 keystore format (Web3 Secret Storage), keys are encrypted with
+// the plain password and addresses are derived the same way as drep addresses
+type ethKeyJSON struct {
+	Address string        `json:"address"`
+	Crypto  ethCryptoJSON `json:"crypto"`
+	Id      string        `json:"id"`
+	Version int           `json:"version"`
+}
+
+type ethCryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams ethCipherParamsJSON    `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type ethCipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// IsEthKeyJSON check whether content is an Ethereum V3 keystore file rather than a CryptedNode
+func IsEthKeyJSON(content []byte) bool {
+	key := &ethKeyJSON{}
+	if err := json.Unmarshal(content, key); err != nil {
+		return false
+	}
+	return key.Version == EthKeyVersion && key.Crypto.CipherText != ""
+}
+
+// EncryptEthKey encrypt key with password into an Ethereum V3 keystore file using scrypt
+func EncryptEthKey(key *secp256k1.PrivateKey, password string, scryptN, scryptP int) ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	cipherText, err := aesCTRXOR(derivedKey[:16], key.Serialize(), iv)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, id); err != nil {
+		return nil, err
+	}
+	// random uuid, version 4 variant 1
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+
+	addr := crypto2.PubkeyToAddress(key.PubKey())
+	return json.Marshal(&ethKeyJSON{
+		Address: hex.EncodeToString(addr[:]),
+		Crypto: ethCryptoJSON{
+			Cipher:       "aes-128-ctr",
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: ethCipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          ethKDFScrypt,
+			KDFParams: map[string]interface{}{
+				"n":     scryptN,
+				"r":     scryptR,
+				"p":     scryptP,
+				"dklen": scryptDKLen,
+				"salt":  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(sha3.Keccak256(derivedKey[16:32], cipherText)),
+		},
+		Id:      fmt.Sprintf("%x-%x-%x-%x-%x", id[:4], id[4:6], id[6:8], id[8:10], id[10:]),
+		Version: EthKeyVersion,
+	})
+}
+
+// DecryptEthKey decrypt an Ethereum V3 keystore file encrypted with scrypt or pbkdf2
+func DecryptEthKey(content []byte, password string) (*secp256k1.PrivateKey, error) {
+	key := &ethKeyJSON{}
+	if err := json.Unmarshal(content, key); err != nil {
+		return nil, err
+	}
+	if key.Version != EthKeyVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	if key.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("Cipher not supported: %v", key.Crypto.Cipher)
+	}
+	mac, err := hex.DecodeString(key.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(key.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := hex.DecodeString(key.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	derivedKey, err := ethKDFKey(key.Crypto, password)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(sha3.Keccak256(derivedKey[16:32], cipherText), mac) {
+		return nil, ErrDecrypt
+	}
+	plainText, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return nil, err
+	}
+	priv, pub := secp256k1.PrivKeyFromScalar(plainText)
+	addr := crypto2.PubkeyToAddress(pub)
+	if key.Address != "" && !strings.EqualFold(strings.TrimPrefix(key.Address, "0x"), hex.EncodeToString(addr[:])) {
+		return nil, fmt.Errorf("key content mismatch: have address %x, want %s", addr, key.Address)
+	}
+	return priv, nil
+}
+
+func ethKDFKey(cryptoJSON ethCryptoJSON, password string) ([]byte, error) {
+	salt, err := hex.DecodeString(kdfString(cryptoJSON.KDFParams, "salt"))
+	if err != nil {
+		return nil, err
+	}
+	dkLen := kdfInt(cryptoJSON.KDFParams, "dklen")
+	if dkLen < 32 {
+		return nil, fmt.Errorf("invalid derived key length %d", dkLen)
+	}
+
+	switch cryptoJSON.KDF {
+	case ethKDFScrypt:
+		n := kdfInt(cryptoJSON.KDFParams, "n")
+		r := kdfInt(cryptoJSON.KDFParams, "r")
+		p := kdfInt(cryptoJSON.KDFParams, "p")
+		return scrypt.Key([]byte(password), salt, n, r, p, dkLen)
+	case ethKDFPbkdf2:
+		if prf := kdfString(cryptoJSON.KDFParams, "prf"); prf != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported PBKDF2 PRF: %s", prf)
+		}
+		c := kdfInt(cryptoJSON.KDFParams, "c")
+		if c <= 0 {
+			return nil, fmt.Errorf("invalid PBKDF2 iteration count %d", c)
+		}
+		return pbkdf2.Key([]byte(password), salt, c, dkLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("unsupported KDF: %s", cryptoJSON.KDF)
+}
+
+// kdf params are decoded as interface{}, numbers come as float64
+func kdfInt(params map[string]interface{}, name string) int {
+	value, _ := params[name].(float64)
+	return int(value)
+}
+
+func kdfString(params map[string]interface{}, name string) string {
+	value, _ := params[name].(string)
+	return value
+}
@@ -0,0 +1,61 @@
+package component
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+// test vectors of the Web3 Secret Storage definition
+var ethKeyVectors = map[string]string{
+	"pbkdf2": `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+	"scrypt": `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+}
+
+func Test_DecryptEthKey(t *testing.T) {
+	want, _ := hex.DecodeString("7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d")
+	for kdf, content := range ethKeyVectors {
+		if !IsEthKeyJSON([]byte(content)) {
+			t.Fatalf("%s vector is not detected as ethereum keystore", kdf)
+		}
+		key, err := DecryptEthKey([]byte(content), "testpassword")
+		if err != nil {
+			t.Fatalf("%s: %v", kdf, err)
+		}
+		if !bytes.Equal(key.Serialize(), want) {
+			t.Fatalf("%s: expect key %x, got %x", kdf, want, key.Serialize())
+		}
+		if _, err := DecryptEthKey([]byte(content), "wrongpassword"); err != ErrDecrypt {
+			t.Fatalf("%s: expect %v, got %v", kdf, ErrDecrypt, err)
+		}
+	}
+}
+
+func Test_EncryptEthKey(t *testing.T) {
+	key, _ := secp256k1.GeneratePrivateKey(nil)
+	content, err := EncryptEthKey(key, "password", LightScryptN, LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEthKeyJSON(content) {
+		t.Fatal("encrypted key is not detected as ethereum keystore")
+	}
+	decrypted, err := DecryptEthKey(content, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted.Serialize(), key.Serialize()) {
+		t.Fatal("decrypted key differs from encrypted key")
+	}
+
+	// keys of this project are not ethereum keystores
+	node := NewCryptedNode(&types.Node{PrivateKey: key}, "password")
+	cryptedContent, _ := json.Marshal(node)
+	if IsEthKeyJSON(cryptedContent) {
+		t.Fatal("crypted node is detected as ethereum keystore")
+	}
+}
@@ -74,21 +74,7 @@ func (fs FileStore) GetKey(addr *crypto.CommonAddress, auth string) (*types.Node
 
 // store the key in file encrypto
 func (fs FileStore) StoreKey(key *types.Node, auth string) error {
-	cryptoNode := &CryptedNode{
-		Version:      0,
-		Data:         key.PrivateKey.Serialize(),
-		ChainId:      key.ChainId,
-		ChainCode:    key.ChainCode,
-		Cipher:       "aes-128-ctr",
-		CipherParams: CipherParams{},
-		KDFParams: ScryptParams{
-			N:     StandardScryptN,
-			R:     scryptR,
-			P:     StandardScryptP,
-			Dklen: scryptDKLen,
-		},
-	}
-	cryptoNode.EncryptData([]byte(auth))
+	cryptoNode := NewCryptedNode(key, auth)
 	content, err := json.Marshal(cryptoNode)
 	if err != nil {
 		return err
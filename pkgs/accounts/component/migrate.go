@@ -0,0 +1,90 @@
+package component
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/drep-project/DREP-Chain/common/fileutil"
+)
+
+// Migrator is implemented by stores whose keys written by older versions can be upgraded
+type Migrator interface {
+	// Migrate upgrade keys to KeyStoreVersion and return the number of keys upgraded
+	Migrate() (int, error)
+}
+
+// migrateCryptedNode upgrade an encoded CryptedNode to KeyStoreVersion. Keys of version 0 were
+// created with the derivation of types.NewNode or imported, they have no derivation path, so
+// only the version is changed and the cipher text is kept as it is.
+func migrateCryptedNode(content []byte) ([]byte, bool, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil, false, err
+	}
+	version := 0
+	if raw, ok := fields["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, false, err
+		}
+	}
+	if version > KeyStoreVersion {
+		return nil, false, ErrUnsupportedVersion
+	}
+	if version == KeyStoreVersion {
+		return content, false, nil
+	}
+	fields["version"], _ = json.Marshal(KeyStoreVersion)
+	migrated, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false, err
+	}
+	return migrated, true, nil
+}
+
+// Migrate upgrade the key files of older versions in place
+func (fs FileStore) Migrate() (int, error) {
+	migrated := 0
+	err := fileutil.EachChildFile(fs.keysDirPath, func(path string) (bool, error) {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		newContents, changed, err := migrateCryptedNode(contents)
+		if err != nil {
+			log.WithField("path", path).WithField("err", err).Warn("skip key file can not be migrated")
+			return true, nil
+		}
+		if !changed {
+			return true, nil
+		}
+		if err := writeKeyFile(path, newContents); err != nil {
+			return false, err
+		}
+		migrated++
+		return true, nil
+	})
+	return migrated, err
+}
+
+// Migrate upgrade the keys of older versions in db
+func (dbStore *DbStore) Migrate() (int, error) {
+	migrated := 0
+	iter := dbStore.db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		newContents, changed, err := migrateCryptedNode(iter.Value())
+		if err != nil {
+			log.WithField("key", iter.Key()).WithField("err", err).Warn("skip key can not be migrated")
+			continue
+		}
+		if !changed {
+			continue
+		}
+		key := append([]byte{}, iter.Key()...)
+		if err := dbStore.db.Put(key, newContents, nil); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, iter.Error()
+}
@@ -0,0 +1,25 @@
+package component
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMigrateCryptedNode(t *testing.T) {
+	legacy := []byte(`{"version":0,"cipherText":"AQI=","chainId":"0x00","chainCode":"AwQ=","cipher":"aes-128-ctr","mac":"BQY="}`)
+	migrated, changed, err := migrateCryptedNode(legacy)
+	if err != nil || !changed {
+		t.Fatalf("expect legacy key migrated, got %v %v", changed, err)
+	}
+	fields := map[string]json.RawMessage{}
+	json.Unmarshal(migrated, &fields)
+	if string(fields["version"]) != "1" || string(fields["cipherText"]) != `"AQI="` || string(fields["mac"]) != `"BQY="` {
+		t.Errorf("expect only version changed, got %s", migrated)
+	}
+	if _, changed, err = migrateCryptedNode(migrated); changed || err != nil {
+		t.Errorf("expect current key unchanged, got %v %v", changed, err)
+	}
+	if _, _, err = migrateCryptedNode([]byte(`{"version":2}`)); err != ErrUnsupportedVersion {
+		t.Errorf("expect newer version rejected, got %v", err)
+	}
+}
@@ -2,18 +2,24 @@ package service
 
 import (
 	"encoding/hex"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"github.com/drep-project/DREP-Chain/pkgs/accounts/addrgenerator"
 	"math/big"
+	"strings"
+	"time"
 
 	"github.com/drep-project/DREP-Chain/blockmgr"
 
 	"github.com/drep-project/DREP-Chain/common"
 	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/hdkey"
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
 	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/pkgs/evm"
+	"github.com/drep-project/DREP-Chain/pkgs/evm/abi"
 	"github.com/drep-project/DREP-Chain/types"
+	"github.com/pkg/errors"
 )
 
 /*
@@ -31,7 +37,7 @@ type AccountApi struct {
 
 /*
  name: listAddress
- usage: 列出所有本地地址
+ usage: 列出所有本地地址，包括只读地址
  return: 地址数组
  example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_listAddress","params":[], "id": 3}' -H "Content-Type:application/json"
  response:
@@ -81,6 +87,92 @@ func (accountapi *AccountApi) CreateWallet(password string) error {
 	return accountapi.OpenWallet(password)
 }
 
+/*
+ name: createMnemonicWallet
+ usage: 创建助记词钱包，账号按BIP44路径m/44'/60'/0'/0/i派生，返回的BIP39助记词可在其他钱包中恢复
+ params:
+	1. 钱包密码
+ return: 助记词
+ example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_createMnemonicWallet","params":["123"], "id": 3}' -H "Content-Type:application/json"
+ response:
+	  {"jsonrpc":"2.0","id":3,"result":"legal winner thank year wave sausage worth useful legal winner thank yellow"}
+*/
+func (accountapi *AccountApi) CreateMnemonicWallet(password string) (string, error) {
+	mnemonic, err := NewMnemonic()
+	if err != nil {
+		return "", err
+	}
+	if err := accountapi.RestoreMnemonicWallet(mnemonic, password); err != nil {
+		return "", err
+	}
+	return mnemonic, nil
+}
+
+/*
+ name: restoreMnemonicWallet
+ usage: 从助记词恢复钱包，恢复第一个账号及链上有交易或余额的账号，连续20个未使用的账号后停止查找，其余账号可通过createAccount依次派生
+ params:
+	1. 助记词
+	2. 钱包密码
+ return: 无
+ example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_restoreMnemonicWallet","params":["legal winner thank year wave sausage worth useful legal winner thank yellow","123"], "id": 3}' -H "Content-Type:application/json"
+ response:
+	  {"jsonrpc":"2.0","id":3,"result":null}
+*/
+func (accountapi *AccountApi) RestoreMnemonicWallet(mnemonic, password string) error {
+	if accountapi.Wallet.IsOpen() {
+		return ErrOpenedWallet
+	}
+	err := accountapi.accountService.CreateHDWallet(password, mnemonic)
+	if err != nil {
+		return err
+	}
+	return accountapi.OpenWallet(password)
+}
+
+/*
+ name: deriveAccount
+ usage: 从助记词钱包按BIP32路径派生账号，不以m开头的路径为m/44'/60'/0'/0下的相对路径
+ params:
+	1. 派生路径
+ return: 账号地址
+ example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_deriveAccount","params":["m/44'/60'/0'/0/3"], "id": 3}' -H "Content-Type:application/json"
+ response:
+	  {"jsonrpc":"2.0","id":3,"result":"0x2944c15c466fad03ec1282bab579dec5a0cf0fa3"}
+*/
+func (accountapi *AccountApi) DeriveAccount(path string) (*crypto.CommonAddress, error) {
+	if !accountapi.Wallet.IsOpen() {
+		return nil, ErrClosedWallet
+	}
+	derivationPath, err := hdkey.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	node, err := accountapi.Wallet.DeriveAccount(derivationPath)
+	if err != nil {
+		return nil, err
+	}
+	return node.Address, nil
+}
+
+/*
+ name: accountPath
+ usage: 查询账号的BIP32派生路径，导入或旧版本创建的账号返回空
+ params:
+	1. 账号地址
+ return: 派生路径
+ example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_accountPath","params":["0x2944c15c466fad03ec1282bab579dec5a0cf0fa3"], "id": 3}' -H "Content-Type:application/json"
+ response:
+	  {"jsonrpc":"2.0","id":3,"result":"m/44'/60'/0'/0/3"}
+*/
+func (accountapi *AccountApi) AccountPath(address crypto.CommonAddress) (string, error) {
+	node, err := accountapi.Wallet.GetAccountByAddress(&address)
+	if err != nil {
+		return "", err
+	}
+	return node.Path, nil
+}
+
 /*
  name: lockWallet
  usage: 锁定钱包（无法发起需要私钥的相关工作）
@@ -94,7 +186,7 @@ func (accountapi *AccountApi) LockWallet() error {
 	if !accountapi.Wallet.IsOpen() {
 		return ErrClosedWallet
 	}
-	if !accountapi.Wallet.IsLock() {
+	if !accountapi.Wallet.IsLock() || accountapi.Wallet.HasUnLockedAccount() {
 		return accountapi.Wallet.Lock()
 	}
 	return ErrLockedWallet
@@ -102,22 +194,102 @@ func (accountapi *AccountApi) LockWallet() error {
 
 /*
  name: lockWallet
- usage: 解锁钱包
+ usage: 解锁钱包，指定地址时只解锁该地址，指定时长时到期后自动锁定
  params:
 	1. 钱包密码
+	2. 地址（可选）
+	3. 解锁时长，单位秒（可选，不填则直到锁定钱包）
  return: 无
- example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_openWallet","params":["123"], "id": 3}' -H "Content-Type:application/json"
+ example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_unLockWallet","params":["123","0x3ebcbe7cb440dd8c52940a2963472380afbb56c5",300], "id": 3}' -H "Content-Type:application/json"
  response:
 	 {"jsonrpc":"2.0","id":3,"result":null}
 */
-func (accountapi *AccountApi) UnLockWallet(password string) error {
+func (accountapi *AccountApi) UnLockWallet(password string, address *crypto.CommonAddress, duration *uint64) error {
 	if !accountapi.Wallet.IsOpen() {
 		return ErrClosedWallet
 	}
-	if accountapi.Wallet.IsLock() {
-		return accountapi.Wallet.UnLock(password)
+	if !accountapi.Wallet.IsLock() {
+		return ErrAlreadyUnLocked
+	}
+	var unlockDuration time.Duration
+	if duration != nil {
+		unlockDuration = time.Duration(*duration) * time.Second
+	}
+	if address != nil {
+		return accountapi.Wallet.UnLockAccount(address, password, unlockDuration)
 	}
-	return ErrAlreadyUnLocked
+	return accountapi.Wallet.UnLockFor(password, unlockDuration)
+}
+
+/*
+ name: walletStatus
+ usage: 查询钱包是否打开、是否锁定及解锁到期时间
+ params:
+ return: {open, external, locked, unlockExpire}
+ example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_walletStatus","params":[], "id": 3}' -H "Content-Type:application/json"
+ response:
+	 {"jsonrpc":"2.0","id":3,"result":{"open":true,"external":false,"locked":false,"unlockExpire":1571385600}}
+*/
+func (accountapi *AccountApi) WalletStatus() *WalletStatus {
+	return accountapi.Wallet.Status()
+}
+
+/*
+ name: listAccounts
+ usage: 列出所有地址的余额、是否只读、是否解锁及解锁到期时间，开启trace时只读地址附带最早发送和接收的交易哈希
+ params:
+ return: 账号数组
+ example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_listAccounts","params":[], "id": 3}' -H "Content-Type:application/json"
+ response:
+	 {"jsonrpc":"2.0","id":3,"result":[{"address":"0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","watchOnly":false,"unlocked":true,"unlockExpire":1571385600,"balance":"0x1158e460913d00000"},{"address":"0x3296d3336895b5baaa0eca3df911741bd0681c3f","watchOnly":true,"unlocked":false,"balance":"0x0","history":{"sent":[],"received":["0x3d3e7da272a5128bec6fd7ad10d8557b08e0fb9de4af6753641e29740eb7054e"]}}]}
+*/
+func (accountapi *AccountApi) ListAccounts() ([]*RpcAccountStatus, error) {
+	if !accountapi.Wallet.IsOpen() {
+		return nil, ErrClosedWallet
+	}
+	statuses, err := accountapi.Wallet.AccountStatus()
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*RpcAccountStatus, len(statuses))
+	for i, status := range statuses {
+		accounts[i] = &RpcAccountStatus{
+			AccountStatus: status,
+			Balance:       (*common.Big)(accountapi.databaseService.GetBalance(&status.Address)),
+		}
+		if status.WatchOnly {
+			accounts[i].History = accountapi.accountService.watchHistory(&status.Address)
+		}
+	}
+	return accounts, nil
+}
+
+/*
+ name: watchAddress
+ usage: 添加只读地址，只读地址没有私钥，可查询余额和交易记录
+ params:
+	1. 地址
+ return: 无
+ example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_watchAddress","params":["0x3296d3336895b5baaa0eca3df911741bd0681c3f"], "id": 3}' -H "Content-Type:application/json"
+ response:
+	 {"jsonrpc":"2.0","id":3,"result":null}
+*/
+func (accountapi *AccountApi) WatchAddress(address crypto.CommonAddress) error {
+	return accountapi.Wallet.WatchAddress(&address)
+}
+
+/*
+ name: unWatchAddress
+ usage: 删除只读地址
+ params:
+	1. 地址
+ return: 无
+ example:   curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_unWatchAddress","params":["0x3296d3336895b5baaa0eca3df911741bd0681c3f"], "id": 3}' -H "Content-Type:application/json"
+ response:
+	 {"jsonrpc":"2.0","id":3,"result":null}
+*/
+func (accountapi *AccountApi) UnWatchAddress(address crypto.CommonAddress) error {
+	return accountapi.Wallet.UnWatchAddress(&address)
 }
 
 /*
@@ -165,7 +337,7 @@ func (accountapi *AccountApi) CloseWallet() {
 func (accountapi *AccountApi) Transfer(from crypto.CommonAddress, to crypto.CommonAddress, amount, gasprice, gaslimit *common.Big, data common.Bytes) (string, error) {
 	nonce := accountapi.poolQuery.GetTransactionCount(&from)
 	tx := types.NewTransaction(to, (*big.Int)(amount), (*big.Int)(gasprice), (*big.Int)(gaslimit), nonce)
-	sig, err := accountapi.Wallet.Sign(&from, tx.TxHash().Bytes())
+	sig, err := accountapi.Wallet.SignTx(&from, tx)
 	if err != nil {
 		return "", err
 	}
@@ -199,7 +371,7 @@ func (accountapi *AccountApi) ReplaceTx(from crypto.CommonAddress, to crypto.Com
 	}
 
 	tx := types.NewTransaction(to, (*big.Int)(amount), (*big.Int)(gasprice), (*big.Int)(gaslimit), *nonce)
-	sig, err := accountapi.Wallet.Sign(&from, tx.TxHash().Bytes())
+	sig, err := accountapi.Wallet.SignTx(&from, tx)
 	if err != nil {
 		return "", err
 	}
@@ -265,7 +437,7 @@ response:
 func (accountapi *AccountApi) SetAlias(srcAddr crypto.CommonAddress, alias string, gasprice, gaslimit *common.Big) (string, error) {
 	nonce := accountapi.poolQuery.GetTransactionCount(&srcAddr)
 	t := types.NewAliasTransaction(alias, (*big.Int)(gasprice), (*big.Int)(gaslimit), nonce)
-	sig, err := accountapi.Wallet.Sign(&srcAddr, t.TxHash().Bytes())
+	sig, err := accountapi.Wallet.SignTx(&srcAddr, t)
 	if err != nil {
 		return "", err
 	}
@@ -298,7 +470,7 @@ func (accountapi *AccountApi) SetAlias(srcAddr crypto.CommonAddress, alias strin
 func (accountapi *AccountApi) Call(from crypto.CommonAddress, to crypto.CommonAddress, input common.Bytes, amount, gasprice, gaslimit *common.Big) (string, error) {
 	nonce := accountapi.poolQuery.GetTransactionCount(&from)
 	t := types.NewCallContractTransaction(to, input, (*big.Int)(amount), (*big.Int)(gasprice), (*big.Int)(gaslimit), nonce)
-	sig, err := accountapi.Wallet.Sign(&from, t.TxHash().Bytes())
+	sig, err := accountapi.Wallet.SignTx(&from, t)
 	if err != nil {
 		return "", err
 	}
@@ -309,28 +481,65 @@ func (accountapi *AccountApi) Call(from crypto.CommonAddress, to crypto.CommonAd
 
 /*
  name: createCode
- usage: 部署合约
+ usage: 部署合约，可附带合约abi及构造函数参数，参数按abi编码后追加在合约内容之后，交易上链后abi和合约的元数据hash按收据中的合约地址保存在本节点数据库中，等待上链的部署记录也保存在数据库中，节点重启后继续等待
  params:
 	1. 部署合约的地址
 	2. 合约内容
 	3. 金额
 	4. gas价格
 	5. gas上线
- return: 合约地址
+	6. json格式的abi(可选)
+	7. 构造函数参数数组(可选)，整数为数字或字符串，地址和字节为16进制字符串
+ return: 交易hash
  example:
- 	curl -H "Content-Type: application/json" -X post --data '{"jsonrpc":"2.0","method":"account_createCode","params":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","0x608060405234801561001057600080fd5b5061018c806100206000396000f3fe608060405260043610610051576000357c0100000000000000000000000000000000000000000000000000000000900480634f2be91f146100565780636d4ce63c1461006d578063db7208e31461009e575b600080fd5b34801561006257600080fd5b5061006b6100dc565b005b34801561007957600080fd5b5061008261011c565b604051808260070b60070b815260200191505060405180910390f35b3480156100aa57600080fd5b506100da600480360360208110156100c157600080fd5b81019080803560070b9060200190929190505050610132565b005b60016000808282829054906101000a900460070b0192506101000a81548167ffffffffffffffff021916908360070b67ffffffffffffffff160217905550565b60008060009054906101000a900460070b905090565b806000806101000a81548167ffffffffffffffff021916908360070b67ffffffffffffffff1602179055505056fea165627a7a723058204b651e4313ab6bc4eda61084cac1f805699cefbb979ddfd3a2d7f970903307cd0029","0x111","0x110","0x30000"],"id":1}' http://127.0.0.1:15645
+ 	curl -H "Content-Type: application/json" -X post --data '{"jsonrpc":"2.0","method":"account_createCode","params":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","0x608060405234801561001057600080fd5b5060405160208061012083398101604052516000555060f5806100276000396000f3fe6080604052348015600f57600080fd5b506004361060285760003560e01c80636d4ce63c14602d575b600080fd5b60336045565b60408051918252519081900360200190f35b6000549056fea165627a7a72305820a0b7d7ec4b0b42e58bd66d1ba3ba69b7ed4c04bb36b6d90b2dba8e8a2b0a6d5c0029","0x0","0x110","0x30000","[{\"inputs\":[{\"name\":\"value\",\"type\":\"uint256\"}],\"type\":\"constructor\"}]",["100"]],"id":1}' http://127.0.0.1:15645
  response:
 	 {"jsonrpc":"2.0","id":1,"result":"0x9a8d8d5d7d00bbe0eb1b9431a13a7219008e352241b751b177bfb29e4e75b0d1"}
 */
-func (accountapi *AccountApi) CreateCode(from crypto.CommonAddress, byteCode common.Bytes, amount, gasprice, gaslimit *common.Big) (string, error) {
+func (accountapi *AccountApi) CreateCode(from crypto.CommonAddress, byteCode common.Bytes, amount, gasprice, gaslimit *common.Big, abiJson *string, args *[]json.RawMessage) (string, error) {
+	code := byteCode
+	var contractAbi *abi.ABI
+	if abiJson != nil {
+		parsed, err := abi.JSON(strings.NewReader(*abiJson))
+		if err != nil {
+			return "", errors.Wrap(ErrInvalidAbi, err.Error())
+		}
+		contractAbi = &parsed
+	}
+	if args != nil {
+		if contractAbi == nil {
+			return "", ErrConstructorWithoutAbi
+		}
+		packed, err := contractAbi.PackConstructor(*args)
+		if err != nil {
+			return "", errors.Wrap(ErrConstructorArgs, err.Error())
+		}
+		code = append(append([]byte{}, byteCode...), packed...)
+	}
+
 	nonce := accountapi.poolQuery.GetTransactionCount(&from)
-	t := types.NewContractTransaction(byteCode, (*big.Int)(gasprice), (*big.Int)(gaslimit), nonce)
-	sig, err := accountapi.Wallet.Sign(&from, t.TxHash().Bytes())
+	t := types.NewContractTransaction(code, (*big.Int)(gasprice), (*big.Int)(gaslimit), nonce)
+	sig, err := accountapi.Wallet.SignTx(&from, t)
 	if err != nil {
 		return "", err
 	}
 	t.Sig = sig
-	accountapi.messageBroadCastor.SendTransaction(t, true)
+
+	// the abi and the metadata hash of the bytecode compiled by solc are stored at the contract
+	// address of the receipt once the transaction is mined
+	deploy := &pendingDeploy{MetadataHash: evm.MetadataHash(byteCode)}
+	if abiJson != nil {
+		deploy.AbiJson = []byte(*abiJson)
+	}
+	if deploy.AbiJson != nil || deploy.MetadataHash != nil {
+		if err := accountapi.accountService.addDeploy(*t.TxHash(), deploy); err != nil {
+			return "", err
+		}
+	}
+	if err := accountapi.messageBroadCastor.SendTransaction(t, true); err != nil {
+		accountapi.accountService.removeDeploy(*t.TxHash())
+		return "", err
+	}
 	return t.TxHash().String(), nil
 }
 
@@ -348,15 +557,7 @@ func (accountapi *AccountApi) DumpPrivkey(address *crypto.CommonAddress) (*secp2
 	if !accountapi.Wallet.IsOpen() {
 		return nil, ErrClosedWallet
 	}
-	if accountapi.Wallet.IsLock() {
-		return nil, ErrLockedWallet
-	}
-
-	node, err := accountapi.Wallet.GetAccountByAddress(address)
-	if err != nil {
-		return nil, err
-	}
-	return node.PrivateKey, nil
+	return accountapi.Wallet.DumpPrivateKey(address)
 }
 
 /*
@@ -415,7 +616,7 @@ func (accountapi *AccountApi) GenerateAddresses(address crypto.CommonAddress) (*
 
 /*
 	 name: importKeyStore
-	 usage: 导入keystore
+	 usage: 导入keystore目录或单个密钥文件，支持本项目格式及以太坊V3 keystore格式（scrypt及pbkdf2）
 	 params:
 		1.path
 		2.password
@@ -429,6 +630,74 @@ func (accountapi *AccountApi) ImportKeyStore(path, password string) ([]*crypto.C
 	return accountapi.Wallet.ImportKeyStore(path, password)
 }
 
+/*
+	 name: importEthKeyStore
+	 usage: 导入以太坊V3 keystore（如geth、MetaMask导出的密钥文件）
+	 params:
+		1.keystore内容
+		2.keystore密码
+	 return: address
+	 example:
+		 curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_importEthKeyStore","params":[{"address":"008aeeda4d805471df9b2a5b0f38a0c3bcba786b","crypto":{...},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3},"123"], "id": 3}' -H "Content-Type:application/json"
+	response:
+		 {"jsonrpc":"2.0","id":3,"result":"0x008aeeda4d805471df9b2a5b0f38a0c3bcba786b"}
+*/
+func (accountapi *AccountApi) ImportEthKeyStore(keyJson json.RawMessage, password string) (*crypto.CommonAddress, error) {
+	node, err := accountapi.Wallet.ImportEthKey(keyJson, password)
+	if err != nil {
+		return nil, err
+	}
+	return node.Address, nil
+}
+
+/*
+	 name: exportEthKeyStore
+	 usage: 将私钥导出为以太坊V3 keystore，可导入geth、MetaMask，需解锁钱包或该地址
+	 params:
+		1.地址
+		2.keystore密码，可与钱包密码不同
+	 return: keystore内容
+	 example:
+		 curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_exportEthKeyStore","params":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","123"], "id": 3}' -H "Content-Type:application/json"
+	response:
+		 {"jsonrpc":"2.0","id":3,"result":{"address":"3ebcbe7cb440dd8c52940a2963472380afbb56c5","crypto":{"cipher":"aes-128-ctr",...},"id":"6b1aabd3-6c66-40a0-acff-5edbf0a71e2c","version":3}}
+*/
+func (accountapi *AccountApi) ExportEthKeyStore(address crypto.CommonAddress, password string) (json.RawMessage, error) {
+	return accountapi.Wallet.ExportEthKey(&address, password)
+}
+
+/*
+	 name: backupWallet
+	 usage: 将钱包中所有私钥及其链id、派生路径加密备份到一个新文件，需解锁钱包。备份可恢复到任意类型的keystore
+	 params:
+		1.备份文件路径，文件已存在时失败
+		2.备份密码，可与钱包密码不同
+	 return: 备份的私钥数量
+	 example:
+		 curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_backupWallet","params":["/backup/drep-wallet.json","123"], "id": 3}' -H "Content-Type:application/json"
+	response:
+		 {"jsonrpc":"2.0","id":3,"result":3}
+*/
+func (accountapi *AccountApi) BackupWallet(path, passphrase string) (int, error) {
+	return accountapi.Wallet.Backup(path, passphrase)
+}
+
+/*
+	 name: restoreWallet
+	 usage: 从备份文件恢复私钥到当前钱包，需打开并解锁钱包。校验备份完整性，已存在的私钥跳过
+	 params:
+		1.备份文件路径
+		2.备份密码
+	 return: {restored: 恢复的地址, duplicates: 已存在而跳过的地址}
+	 example:
+		 curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_restoreWallet","params":["/backup/drep-wallet.json","123"], "id": 3}' -H "Content-Type:application/json"
+	response:
+		 {"jsonrpc":"2.0","id":3,"result":{"restored":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5"],"duplicates":["0x3296d3336895b5baaa0eca3df911741bd0681c3f"]}}
+*/
+func (accountapi *AccountApi) RestoreWallet(path, passphrase string) (*RestoreResult, error) {
+	return accountapi.Wallet.Restore(path, passphrase)
+}
+
 /*
 	 name: importPrivkey
 	 usage: 导入私钥
@@ -449,6 +718,82 @@ func (accountapi *AccountApi) ImportPrivkey(privBytes common.Bytes) (*crypto.Com
 	return node.Address, nil
 }
 
+/*
+	 name: multiSigAddress
+	 usage: 计算多签账户的地址
+	 params:
+		1.多签账户 {threshold: 签名门限, pubkeys: 公钥列表}
+	 return: 多签地址
+	 example:
+		curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_multiSigAddress","params":[{"threshold":2,"pubkeys":["0x03177b...","0x02e9bb...","0x0390e0..."]}], "id": 3}' -H "Content-Type:application/json"
+	response:
+		 {"jsonrpc":"2.0","id":3,"result":"0x9f6b1e2ec0c1b4a8e1e3d2c1f0a7b6c5d4e3f2a1"}
+*/
+func (accountapi *AccountApi) MultiSigAddress(account types.MultiSigAccount) (crypto.CommonAddress, error) {
+	if err := account.Validate(); err != nil {
+		return crypto.CommonAddress{}, err
+	}
+	return account.Address(), nil
+}
+
+/*
+	 name: startMultiSig
+	 usage: 用本钱包中多签账户的一个私钥开始签名，返回的nonce承诺需发送给其他签名者。待签名交易由blockmgr_buildTransaction以多签地址构建
+	 params:
+		1.待签名交易
+		2.多签账户
+		3.本钱包中签名私钥的地址
+	 return: {session: 本地签名会话, index: 公钥序号, nonce: nonce承诺}
+	 example:
+		curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_startMultiSig","params":[{"from":"0x9f6b1e2ec0c1b4a8e1e3d2c1f0a7b6c5d4e3f2a1","data":{...},"hash":"0x..."},{"threshold":2,"pubkeys":[...]},"0x3ebcbe7cb440dd8c52940a2963472380afbb56c5"], "id": 3}' -H "Content-Type:application/json"
+	response:
+		 {"jsonrpc":"2.0","id":3,"result":{"session":"0x5c1f7a3e9d2b4c6a8e0f1d3b5a7c9e2f","index":0,"nonce":"0x02c5e8..."}}
+*/
+func (accountapi *AccountApi) StartMultiSig(utx types.UnsignedTransaction, account types.MultiSigAccount, signer crypto.CommonAddress) (*MultiSigCommitment, error) {
+	return accountapi.Wallet.StartMultiSig(&account, &utx, &signer)
+}
+
+/*
+	 name: multiSigPartialSign
+	 usage: 收齐所有签名者的nonce承诺后生成部分签名，所有签名者需使用相同的承诺列表。每个会话只能签名一次
+	 params:
+		1.签名会话
+		2.所有签名者的nonce承诺
+	 return: {index: 公钥序号, sig: 部分签名}
+	 example:
+		curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_multiSigPartialSign","params":["0x5c1f7a3e9d2b4c6a8e0f1d3b5a7c9e2f",[{"index":0,"nonce":"0x02c5e8..."},{"index":2,"nonce":"0x03a1d4..."}]], "id": 3}' -H "Content-Type:application/json"
+	response:
+		 {"jsonrpc":"2.0","id":3,"result":{"index":0,"sig":"0x3045..."}}
+*/
+func (accountapi *AccountApi) MultiSigPartialSign(session string, commitments []*MultiSigCommitment) (*MultiSigPartial, error) {
+	return accountapi.Wallet.MultiSigPartialSign(session, commitments)
+}
+
+/*
+	 name: combineMultiSig
+	 usage: 合并部分签名并发送多签交易，不需要解锁钱包
+	 params:
+		1.待签名交易
+		2.多签账户
+		3.所有签名者的部分签名
+	 return: 交易hash
+	 example:
+		curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"account_combineMultiSig","params":[{"from":"0x9f6b1e2ec0c1b4a8e1e3d2c1f0a7b6c5d4e3f2a1","data":{...},"hash":"0x..."},{"threshold":2,"pubkeys":[...]},[{"index":0,"sig":"0x3045..."},{"index":2,"sig":"0x3044..."}]], "id": 3}' -H "Content-Type:application/json"
+	response:
+		 {"jsonrpc":"2.0","id":3,"result":"0x3a3b59f90a21c2fd1b690aa3a2bc06dc2d40eb5bdc26fdd7ecb7e1105af2638e"}
+*/
+func (accountapi *AccountApi) CombineMultiSig(utx types.UnsignedTransaction, account types.MultiSigAccount, partials []*MultiSigPartial) (string, error) {
+	tx, err := CombineMultiSig(&account, &utx, partials)
+	if err != nil {
+		return "", err
+	}
+	err = accountapi.messageBroadCastor.SendTransaction(tx, true)
+	if err != nil {
+		return "", err
+	}
+	return tx.TxHash().String(), nil
+}
+
 type RpcAddresses struct {
 	BtcAddress      string
 	EthAddress      string
@@ -461,6 +806,14 @@ type RpcAddresses struct {
 	TronAddress     string
 }
 
+// RpcAccountStatus is the lock state and balance of an address of the wallet
+type RpcAccountStatus struct {
+	*AccountStatus
+	Balance *common.Big `json:"balance"`
+	// History of a watch-only address, only if the trace service is enabled
+	History *RpcTxHistory `json:"history,omitempty"`
+}
+
 type RpcAccount struct {
 	Addr   *crypto.CommonAddress
 	Pubkey string
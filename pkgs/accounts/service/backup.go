@@ -0,0 +1,106 @@
+package service
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	accountsComponent "github.com/drep-project/DREP-Chain/pkgs/accounts/component"
+	"github.com/pkg/errors"
+)
+
+// RestoreResult lists the keys restored from a backup and the keys skipped as already in the
+// wallet
+type RestoreResult struct {
+	Restored   []*crypto.CommonAddress `json:"restored"`
+	Duplicates []*crypto.CommonAddress `json:"duplicates"`
+}
+
+// Backup write every key of the wallet to a new archive at path encrypted with passphrase, the
+// wallet must be unlocked to read the keys
+func (wallet *Wallet) Backup(path, passphrase string) (int, error) {
+	if err := wallet.checkWallet(WPERMISSION); err != nil {
+		return 0, err
+	}
+	nodes, err := wallet.cacheStore.ExportKey(wallet.password)
+	if err != nil {
+		return 0, err
+	}
+	backup, err := accountsComponent.NewBackup(nodes, passphrase)
+	if err != nil {
+		return 0, err
+	}
+	content, err := json.Marshal(backup)
+	if err != nil {
+		return 0, err
+	}
+
+	// never overwrite an older backup
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return 0, errors.Wrap(ErrExistBackup, path)
+		}
+		return 0, err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return 0, err
+	}
+	return len(nodes), file.Close()
+}
+
+// Restore import the keys of the backup at path into the keystore of the wallet, keys already
+// in the wallet are skipped. A wallet created from a mnemonic only takes the master key of the
+// same mnemonic.
+func (wallet *Wallet) Restore(path, passphrase string) (*RestoreResult, error) {
+	if err := wallet.checkWallet(WPERMISSION); err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	backup := &accountsComponent.Backup{}
+	if err := json.Unmarshal(content, backup); err != nil {
+		return nil, accountsComponent.ErrBackupCorrupt
+	}
+	nodes, err := backup.Decrypt(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := wallet.masterNode()
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		if isMasterNode(node) && master != nil && *master.Address != *node.Address {
+			return nil, ErrMasterKeyConflict
+		}
+	}
+
+	result := &RestoreResult{
+		Restored:   []*crypto.CommonAddress{},
+		Duplicates: []*crypto.CommonAddress{},
+	}
+	for _, node := range nodes {
+		if _, err := wallet.cacheStore.GetKey(node.Address, wallet.password); err == nil {
+			result.Duplicates = append(result.Duplicates, node.Address)
+			continue
+		}
+		if err := wallet.cacheStore.StoreKey(node, wallet.password); err != nil {
+			return result, err
+		}
+		if err := wallet.unwatchImported(node.Address); err != nil {
+			return result, err
+		}
+		result.Restored = append(result.Restored, node.Address)
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	accountComponent "github.com/drep-project/DREP-Chain/pkgs/accounts/component"
+	accountTypes "github.com/drep-project/DREP-Chain/pkgs/accounts/types"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+func newBackupWallet(t *testing.T, password string) *Wallet {
+	wallet, err := NewWallet(&accountTypes.Config{Enable: true, Type: "memorystore"}, types.ChainIdType(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.Open(password); err != nil {
+		t.Fatal(err)
+	}
+	return wallet
+}
+
+func TestBackupRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "wallet.backup")
+
+	wallet := newBackupWallet(t, "password")
+	if _, err := wallet.NewAccount(); err != nil {
+		t.Fatal(err)
+	}
+	key, _ := secp256k1.GeneratePrivateKey(nil)
+	if _, err := wallet.ImportPrivKey(key); err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := wallet.ListAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := wallet.Backup(path, "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != len(addrs) || count != 3 {
+		t.Fatalf("backup of %d keys, want %d", count, len(addrs))
+	}
+	if _, err := wallet.Backup(path, "backup"); errors.Cause(err) != ErrExistBackup {
+		t.Fatalf("backup over an existing archive: %v, want %v", err, ErrExistBackup)
+	}
+
+	// every key is restored into another keystore
+	restoring := newBackupWallet(t, "other password")
+	result, err := restoring.Restore(path, "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Restored) != len(addrs) || len(result.Duplicates) != 0 {
+		t.Fatalf("restored %d keys, %d duplicates, want %d keys", len(result.Restored), len(result.Duplicates), len(addrs))
+	}
+	restored := make(map[crypto.CommonAddress]bool)
+	for _, addr := range result.Restored {
+		restored[*addr] = true
+	}
+	for _, addr := range addrs {
+		if !restored[*addr] {
+			t.Errorf("%s not reported as restored", addr.String())
+		}
+		want, err := wallet.DumpPrivateKey(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := restoring.DumpPrivateKey(addr)
+		if err != nil {
+			t.Fatalf("%s not restored: %v", addr.String(), err)
+		}
+		if !bytes.Equal(key.Serialize(), want.Serialize()) {
+			t.Errorf("key of %s restored as another key", addr.String())
+		}
+	}
+
+	// keys already in the keystore are reported and not imported again
+	result, err = restoring.Restore(path, "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Restored) != 0 || len(result.Duplicates) != len(addrs) {
+		t.Fatalf("restored %d keys, %d duplicates, want %d duplicates", len(result.Restored), len(result.Duplicates), len(addrs))
+	}
+	restoredAddrs, err := restoring.ListAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restoredAddrs) != len(addrs)+1 {
+		t.Errorf("%d keys after restoring twice, want %d", len(restoredAddrs), len(addrs)+1)
+	}
+}
+
+func TestRestoreRejected(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "wallet.backup")
+
+	wallet := newBackupWallet(t, "password")
+	if _, err := wallet.Backup(path, "backup"); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup := &accountComponent.Backup{}
+	if err := json.Unmarshal(content, backup); err != nil {
+		t.Fatal(err)
+	}
+	backup.CipherText[0] ^= 1
+	tampered, _ := json.Marshal(backup)
+
+	restoring := newBackupWallet(t, "password")
+	before, _ := restoring.ListAddress()
+	write := func(name string, content []byte) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, content, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tests := []struct {
+		name       string
+		path       string
+		passphrase string
+		err        error
+	}{
+		{"wrong passphrase", path, "wrong", accountComponent.ErrDecrypt},
+		{"tampered", write("tampered", tampered), "backup", accountComponent.ErrDecrypt},
+		{"truncated", write("truncated", content[:len(content)/2]), "backup", accountComponent.ErrBackupCorrupt},
+		{"not a backup", write("garbage", []byte("garbage")), "backup", accountComponent.ErrBackupCorrupt},
+	}
+	for _, test := range tests {
+		if result, err := restoring.Restore(test.path, test.passphrase); err != test.err || result != nil {
+			t.Errorf("%s: restored %v with %v, want %v", test.name, result, err, test.err)
+		}
+	}
+	after, _ := restoring.ListAddress()
+	if len(after) != len(before) {
+		t.Errorf("%d keys after rejected restores, want %d", len(after), len(before))
+	}
+
+	// a locked wallet does not restore
+	restoring.Lock()
+	if _, err := restoring.Restore(path, "backup"); err == nil {
+		t.Error("locked wallet restored a backup")
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+const (
+	// deployments not mined in time are dropped, their transaction is assumed lost
+	pendingDeployTimeout = 24 * time.Hour
+)
+
+// pendingDeploy is the abi and the metadata hash of a contract deployed by this node, they are
+// stored once the receipt of the transaction gives the address of the contract. The pending
+// deployments are kept in the database so that they outlive a restart of the node.
+type pendingDeploy struct {
+	AbiJson      []byte    `json:"abi,omitempty"`
+	MetadataHash []byte    `json:"metadataHash,omitempty"`
+	Created      time.Time `json:"created"`
+}
+
+// addDeploy wait for the deploy transaction txHash to be mined
+func (accountService *AccountService) addDeploy(txHash crypto.Hash, deploy *pendingDeploy) error {
+	accountService.deployLock.Lock()
+	defer accountService.deployLock.Unlock()
+	if accountService.deploys == nil {
+		accountService.deploys = make(map[crypto.Hash]*pendingDeploy)
+	}
+	for hash, pending := range accountService.deploys {
+		if time.Since(pending.Created) > pendingDeployTimeout {
+			accountService.dropDeploy(hash)
+		}
+	}
+	deploy.Created = time.Now()
+	record, err := json.Marshal(deploy)
+	if err != nil {
+		return err
+	}
+	if err := accountService.DatabaseService.PutPendingDeploy(&txHash, record); err != nil {
+		return err
+	}
+	accountService.deploys[txHash] = deploy
+	return nil
+}
+
+// removeDeploy stop waiting for the deploy transaction txHash
+func (accountService *AccountService) removeDeploy(txHash crypto.Hash) {
+	accountService.deployLock.Lock()
+	defer accountService.deployLock.Unlock()
+	accountService.dropDeploy(txHash)
+}
+
+// dropDeploy remove the pending deployment of txHash, the lock is held by the caller
+func (accountService *AccountService) dropDeploy(txHash crypto.Hash) {
+	delete(accountService.deploys, txHash)
+	if err := accountService.DatabaseService.DeletePendingDeploy(&txHash); err != nil {
+		log.WithField("tx", txHash.String()).WithField("err", err).Error("delete pending deploy")
+	}
+}
+
+// loadDeploys read the deployments left pending by the last run, the ones mined in the meantime
+// are recorded at once
+func (accountService *AccountService) loadDeploys() {
+	accountService.deployLock.Lock()
+	defer accountService.deployLock.Unlock()
+	accountService.deploys = make(map[crypto.Hash]*pendingDeploy)
+	for txHash, record := range accountService.DatabaseService.PendingDeploys() {
+		deploy := &pendingDeploy{}
+		if err := json.Unmarshal(record, deploy); err != nil {
+			log.WithField("tx", txHash.String()).WithField("err", err).Error("invalid pending deploy")
+			accountService.dropDeploy(txHash)
+			continue
+		}
+		accountService.deploys[txHash] = deploy
+		if accountService.DatabaseService.GetReceipt(txHash) != nil {
+			accountService.recordDeploy(txHash, deploy)
+		}
+	}
+}
+
+// recordDeploys store the abi and metadata hash of the deployments mined in block at the
+// contract address of their receipt, failed deployments are dropped
+func (accountService *AccountService) recordDeploys(block *types.Block) {
+	accountService.deployLock.Lock()
+	defer accountService.deployLock.Unlock()
+	if len(accountService.deploys) == 0 {
+		return
+	}
+	for _, tx := range block.Data.TxList {
+		txHash := tx.TxHash()
+		if deploy, ok := accountService.deploys[*txHash]; ok {
+			accountService.recordDeploy(*txHash, deploy)
+		}
+	}
+}
+
+// recordDeploy store the pending deployment of the mined transaction txHash and drop it, the
+// lock is held by the caller
+func (accountService *AccountService) recordDeploy(txHash crypto.Hash, deploy *pendingDeploy) {
+	accountService.dropDeploy(txHash)
+	receipt := accountService.DatabaseService.GetReceipt(txHash)
+	if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful || receipt.ContractAddress.IsEmpty() {
+		return
+	}
+	if deploy.AbiJson != nil {
+		if err := accountService.DatabaseService.PutContractAbi(&receipt.ContractAddress, deploy.AbiJson); err != nil {
+			log.WithField("tx", txHash.String()).WithField("err", err).Error("store contract abi")
+		}
+	}
+	if deploy.MetadataHash != nil {
+		if err := accountService.DatabaseService.PutMetadataHash(&receipt.ContractAddress, deploy.MetadataHash); err != nil {
+			log.WithField("tx", txHash.String()).WithField("err", err).Error("store contract metadata hash")
+		}
+	}
+}
+
+// deployLoop record the deployments of this node as their blocks are added to the chain
+func (accountService *AccountService) deployLoop(events chan *types.ChainEvent) {
+	for {
+		select {
+		case event := <-events:
+			accountService.recordDeploys(event.Block)
+		case <-accountService.deploySub.Err():
+			return
+		}
+	}
+}
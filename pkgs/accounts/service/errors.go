@@ -10,4 +10,21 @@ var (
 	ErrAlreadyUnLocked = errors.New("wallet is already unlocked")
 	ErrExistKey        = errors.New("privkey is exist")
 	ErrMissingKeystore = errors.New("not found keystore")
+
+	ErrOpenedWallet          = errors.New("wallet is already open")
+	ErrInvalidMnemonic       = errors.New("invalid mnemonic")
+	ErrNotHDWallet           = errors.New("wallet is not created from mnemonic")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+	ErrExternalSigner        = errors.New("keys are held by the external signer")
+	ErrNotMultiSigKey        = errors.New("key is not in the multisig account")
+	ErrMultiSigSession       = errors.New("multisig session not found or expired")
+	ErrInvalidCommitment     = errors.New("invalid multisig commitment")
+	ErrWatchOnly             = errors.New("address is watch-only")
+	ErrExistWatchOnly        = errors.New("address is already watched")
+	ErrNotWatchOnly          = errors.New("address is not watched")
+	ErrExistBackup           = errors.New("backup file exists")
+	ErrMasterKeyConflict     = errors.New("wallet is created from another mnemonic")
+	ErrInvalidAbi            = errors.New("invalid contract abi")
+	ErrConstructorWithoutAbi = errors.New("constructor arguments given without abi")
+	ErrConstructorArgs       = errors.New("invalid constructor arguments")
 )
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/hdkey"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+const (
+	// entropy of new mnemonics, 128 bits make 12 words
+	mnemonicEntropyBits = 128
+
+	// restoring a mnemonic wallet stops after this many consecutive unused accounts, the gap
+	// limit of BIP44 account discovery
+	accountGapLimit = 20
+)
+
+// NewMnemonic generate a random BIP39 mnemonic
+func NewMnemonic() (string, error) {
+	entropy, err := hdkey.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return hdkey.NewMnemonic(entropy)
+}
+
+// NewMasterNode derive the BIP32 master key of a BIP39 mnemonic, the mnemonic has no passphrase
+// so that it can be restored by other wallets
+func NewMasterNode(mnemonic string, chainId types.ChainIdType) (*types.Node, error) {
+	if !hdkey.IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+	master, err := hdkey.NewMaster(hdkey.NewSeed(mnemonic, ""))
+	if err != nil {
+		return nil, err
+	}
+	return extendedKeyNode(master, hdkey.RootPath, chainId), nil
+}
+
+// DeriveNode derive the key at path from the master node
+func DeriveNode(master *types.Node, path hdkey.DerivationPath) (*types.Node, error) {
+	masterKey := &hdkey.ExtendedKey{PrivateKey: master.PrivateKey, ChainCode: master.ChainCode}
+	key, err := masterKey.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	return extendedKeyNode(key, path, master.ChainId), nil
+}
+
+// DiscoverAccounts derive the accounts of master on the default BIP44 path until accountGapLimit
+// consecutive accounts are not used, the first account is always returned with the used ones
+func DiscoverAccounts(master *types.Node, used func(addr *crypto.CommonAddress) bool) ([]*types.Node, error) {
+	var (
+		nodes []*types.Node
+		gap   int
+	)
+	for index := uint32(0); index < hdkey.HardenedKeyStart && gap < accountGapLimit; index++ {
+		node, err := DeriveNode(master, hdkey.DefaultBaseDerivationPath.Child(index))
+		if err == hdkey.ErrInvalidChild {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if used(node.Address) {
+			gap = 0
+		} else {
+			gap++
+			if len(nodes) > 0 {
+				continue
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func extendedKeyNode(key *hdkey.ExtendedKey, path hdkey.DerivationPath, chainId types.ChainIdType) *types.Node {
+	addr := crypto.PubkeyToAddress(key.PrivateKey.PubKey())
+	return &types.Node{
+		Address:    &addr,
+		PrivateKey: key.PrivateKey,
+		ChainId:    chainId,
+		ChainCode:  key.ChainCode,
+		Path:       path.String(),
+	}
+}
+
+// isMasterNode check whether node is the master key of a wallet created from mnemonic, it is
+// kept to derive accounts and not used as an account
+func isMasterNode(node *types.Node) bool {
+	return node.Path == hdkey.RootPath.String()
+}
+
+// masterNode return the master key of the wallet, nil if the wallet is not created from mnemonic
+func (wallet *Wallet) masterNode() (*types.Node, error) {
+	nodes, err := wallet.cacheStore.ExportKey(wallet.password)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		if isMasterNode(node) {
+			return node, nil
+		}
+	}
+	return nil, nil
+}
+
+// IsHD check whether the wallet is created from mnemonic
+func (wallet *Wallet) IsHD() (bool, error) {
+	if err := wallet.checkWallet(RPERMISSION); err != nil {
+		return false, err
+	}
+	master, err := wallet.masterNode()
+	return master != nil, err
+}
+
+// DeriveAccount derive the account at path from the master key and store it, the stored account
+// is returned if it is already derived
+func (wallet *Wallet) DeriveAccount(path hdkey.DerivationPath) (*types.Node, error) {
+	if err := wallet.checkWallet(WPERMISSION); err != nil {
+		return nil, err
+	}
+	master, err := wallet.masterNode()
+	if err != nil {
+		return nil, err
+	}
+	if master == nil {
+		return nil, ErrNotHDWallet
+	}
+	if len(path) == 0 {
+		return nil, ErrInvalidDerivationPath
+	}
+	node, err := DeriveNode(master, path)
+	if err != nil {
+		return nil, err
+	}
+	if existNode, err := wallet.cacheStore.GetKey(node.Address, wallet.password); err == nil {
+		return existNode, nil
+	}
+	if err := wallet.cacheStore.StoreKey(node, wallet.password); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// deriveNextAccount derive the account following the last one derived on the default BIP44
+// path, indexes of invalid child keys are skipped as BIP32 requires
+func (wallet *Wallet) deriveNextAccount() (*types.Node, error) {
+	nodes, err := wallet.cacheStore.ExportKey(wallet.password)
+	if err != nil {
+		return nil, err
+	}
+	next := uint32(0)
+	for _, node := range nodes {
+		path, err := hdkey.ParseDerivationPath(node.Path)
+		if err != nil || !path.IsChildOf(hdkey.DefaultBaseDerivationPath) {
+			continue
+		}
+		if index := path[len(path)-1]; index < hdkey.HardenedKeyStart && index >= next {
+			next = index + 1
+		}
+	}
+	for ; next < hdkey.HardenedKeyStart; next++ {
+		node, err := wallet.DeriveAccount(hdkey.DefaultBaseDerivationPath.Child(next))
+		if err != hdkey.ErrInvalidChild {
+			return node, err
+		}
+	}
+	return nil, hdkey.ErrInvalidChild
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"crypto/rand"
+	"sort"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+const (
+	// sessions not finished in time are dropped with their nonces
+	multiSigSessionTimeout = 30 * time.Minute
+)
+
+// MultiSigCommitment is the public nonce a key of a multisig account commits to for a
+// transaction, it is sent to the other signers
+type MultiSigCommitment struct {
+	// Session is the local id of the signing session, only meaningful to the wallet which made it
+	Session string               `json:"session,omitempty"`
+	Index   int                  `json:"index"`
+	Nonce   *secp256k1.PublicKey `json:"nonce"`
+}
+
+// MultiSigPartial is the partial signature of a key of a multisig account
+type MultiSigPartial struct {
+	Index int          `json:"index"`
+	Sig   common.Bytes `json:"sig"`
+}
+
+// multiSigSession is the state of a key between its commitment and its partial signature, the
+// nonce is random and used only once as signing a hash twice with one nonce reveals the key
+type multiSigSession struct {
+	account *types.MultiSigAccount
+	hash    []byte
+	index   int
+	addr    *crypto.CommonAddress
+	nonce   *secp256k1.PrivateKey
+	created time.Time
+}
+
+// StartMultiSig commit the key of addr to sign utx for the multisig account, the returned
+// commitment is sent to the other signers
+func (wallet *Wallet) StartMultiSig(account *types.MultiSigAccount, utx *types.UnsignedTransaction, addr *crypto.CommonAddress) (*MultiSigCommitment, error) {
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
+	if utx.From != account.Address() {
+		return nil, types.ErrTxSenderMismatch
+	}
+	tx := &types.Transaction{Data: utx.Data}
+	if *tx.TxHash() != utx.Hash {
+		return nil, types.ErrTxHashMismatch
+	}
+	node, err := wallet.unlockedKey(addr)
+	if err != nil {
+		return nil, err
+	}
+	index := account.IndexOf(node.PrivateKey.PubKey())
+	if index < 0 {
+		return nil, ErrNotMultiSigKey
+	}
+
+	extra := make([]byte, 32)
+	if _, err := rand.Read(extra); err != nil {
+		return nil, err
+	}
+	hash := tx.TxHash().Bytes()
+	nonce, nonceKey, err := schnorr.GenerateNoncePair(secp256k1.S256(), hash, node.PrivateKey, extra, schnorr.Sha256VersionStringRFC6979)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	wallet.sessionLock.Lock()
+	defer wallet.sessionLock.Unlock()
+	for id, session := range wallet.sessions {
+		if time.Since(session.created) > multiSigSessionTimeout {
+			delete(wallet.sessions, id)
+		}
+	}
+	session := common.Encode(id)
+	wallet.sessions[session] = &multiSigSession{
+		account: account,
+		hash:    hash,
+		index:   index,
+		addr:    addr,
+		nonce:   nonce,
+		created: time.Now(),
+	}
+	return &MultiSigCommitment{Session: session, Index: index, Nonce: nonceKey}, nil
+}
+
+// MultiSigPartialSign sign the transaction of session with the commitments of all signers,
+// every signer must use the same commitments. The key is weighted by its coefficient in the
+// account. The session ends whether signing succeeds or not.
+func (wallet *Wallet) MultiSigPartialSign(session string, commitments []*MultiSigCommitment) (*MultiSigPartial, error) {
+	if err := wallet.checkWallet(RPERMISSION); err != nil {
+		return nil, err
+	}
+	wallet.sessionLock.Lock()
+	state, ok := wallet.sessions[session]
+	delete(wallet.sessions, session)
+	wallet.sessionLock.Unlock()
+	if !ok || time.Since(state.created) > multiSigSessionTimeout {
+		return nil, ErrMultiSigSession
+	}
+
+	if len(commitments) < int(state.account.Threshold) {
+		return nil, types.ErrMultiSigThreshold
+	}
+	seen := make(map[int]bool)
+	others := []*secp256k1.PublicKey{}
+	for _, commitment := range commitments {
+		if commitment.Index < 0 || commitment.Index >= len(state.account.PubKeys) || seen[commitment.Index] || commitment.Nonce == nil {
+			return nil, ErrInvalidCommitment
+		}
+		seen[commitment.Index] = true
+		if commitment.Index == state.index {
+			if !commitment.Nonce.IsEqual(state.nonce.PubKey()) {
+				return nil, ErrInvalidCommitment
+			}
+			continue
+		}
+		others = append(others, commitment.Nonce)
+	}
+	if !seen[state.index] {
+		return nil, ErrInvalidCommitment
+	}
+
+	node, err := wallet.unlockedKey(state.addr)
+	if err != nil {
+		return nil, err
+	}
+	key := state.account.SigningKey(state.index, node.PrivateKey)
+	var sig *schnorr.Signature
+	if len(others) == 0 {
+		// a lone signer makes a whole signature
+		r, s, err := schnorr.Sign(key, state.hash)
+		if err != nil {
+			return nil, err
+		}
+		sig = schnorr.NewSignature(r, s)
+	} else {
+		pubSum := schnorr.CombinePubkeys(others)
+		if pubSum == nil {
+			return nil, ErrInvalidCommitment
+		}
+		if sig, err = schnorr.PartialSign(secp256k1.S256(), state.hash, key, state.nonce, pubSum); err != nil {
+			return nil, err
+		}
+	}
+	return &MultiSigPartial{Index: state.index, Sig: sig.Serialize()}, nil
+}
+
+// CombineMultiSig combine the partial signatures of the signers into the signature of utx
+func CombineMultiSig(account *types.MultiSigAccount, utx *types.UnsignedTransaction, partials []*MultiSigPartial) (*types.Transaction, error) {
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
+	sort.Slice(partials, func(i, j int) bool { return partials[i].Index < partials[j].Index })
+	bitmap := make([]byte, len(account.PubKeys))
+	sigs := []*schnorr.Signature{}
+	for _, partial := range partials {
+		if partial.Index < 0 || partial.Index >= len(bitmap) || bitmap[partial.Index] == 1 {
+			return nil, ErrInvalidCommitment
+		}
+		bitmap[partial.Index] = 1
+		sig, err := schnorr.ParseSignature(partial.Sig)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+	if len(sigs) < int(account.Threshold) {
+		return nil, types.ErrMultiSigThreshold
+	}
+	combined, err := schnorr.CombineSigs(secp256k1.S256(), sigs)
+	if err != nil {
+		return nil, err
+	}
+	multiSig, err := types.NewMultiSig(account, bitmap, combined)
+	if err != nil {
+		return nil, err
+	}
+	tx := &types.Transaction{Data: utx.Data, Sig: multiSig}
+	from, err := tx.From()
+	if err != nil {
+		return nil, err
+	}
+	if *from != account.Address() {
+		return nil, types.ErrTxSenderMismatch
+	}
+	return tx, nil
+}
+
+// clearMultiSigSessions drop all sessions with their nonces
+func (wallet *Wallet) clearMultiSigSessions() {
+	wallet.sessionLock.Lock()
+	wallet.sessions = make(map[string]*multiSigSession)
+	wallet.sessionLock.Unlock()
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/app"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+func Test_MultiSig(t *testing.T) {
+	wallets := []*Wallet{}
+	account := &types.MultiSigAccount{Threshold: 2}
+	addrs := []*crypto.CommonAddress{}
+	for i := 0; i < 3; i++ {
+		wallet, err := NewWallet(testConfig, app.ChainIdType{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := wallet.Open("password"); err != nil {
+			t.Fatal(err)
+		}
+		node, err := wallet.NewAccount()
+		if err != nil {
+			t.Fatal(err)
+		}
+		wallets = append(wallets, wallet)
+		addrs = append(addrs, node.Address)
+		account.PubKeys = append(account.PubKeys, node.PrivateKey.PubKey())
+	}
+	tx := types.NewTransaction(crypto.CommonAddress{1}, big.NewInt(10), big.NewInt(1), big.NewInt(21000), 0)
+	utx := types.NewUnsignedTransaction(account.Address(), tx)
+
+	signers := []int{0, 2}
+	sessions := []string{}
+	commitments := []*MultiSigCommitment{}
+	for _, i := range signers {
+		commitment, err := wallets[i].StartMultiSig(account, utx, addrs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions = append(sessions, commitment.Session)
+		commitments = append(commitments, &MultiSigCommitment{Index: commitment.Index, Nonce: commitment.Nonce})
+	}
+	partials := []*MultiSigPartial{}
+	for j, i := range signers {
+		partial, err := wallets[i].MultiSigPartialSign(sessions[j], commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partials = append(partials, partial)
+	}
+	// a session signs only once
+	if _, err := wallets[0].MultiSigPartialSign(sessions[0], commitments); err != ErrMultiSigSession {
+		t.Fatalf("expect %v, got %v", ErrMultiSigSession, err)
+	}
+
+	signed, err := CombineMultiSig(account, utx, partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := signed.From()
+	if err != nil || *from != account.Address() {
+		t.Fatalf("expect sender %s, got %v %v", account.Address().String(), from, err)
+	}
+	if _, err := CombineMultiSig(account, utx, partials[:1]); err != types.ErrMultiSigThreshold {
+		t.Fatalf("expect %v, got %v", types.ErrMultiSigThreshold, err)
+	}
+
+	outsider, _ := secp256k1.GeneratePrivateKey(nil)
+	node, err := wallets[0].ImportPrivKey(outsider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallets[0].StartMultiSig(account, utx, node.Address); err != ErrNotMultiSigKey {
+		t.Fatalf("expect %v, got %v", ErrNotMultiSigKey, err)
+	}
+}
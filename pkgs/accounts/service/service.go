@@ -5,7 +5,9 @@ import (
 	"github.com/drep-project/DREP-Chain/blockmgr"
 	"github.com/drep-project/DREP-Chain/chain"
 	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/common/event"
 	"github.com/drep-project/DREP-Chain/common/fileutil"
+	"github.com/drep-project/DREP-Chain/crypto"
 	"github.com/drep-project/DREP-Chain/crypto/sha3"
 	"github.com/drep-project/DREP-Chain/database"
 	accountComponent "github.com/drep-project/DREP-Chain/pkgs/accounts/component"
@@ -13,6 +15,7 @@ import (
 	chainTypes "github.com/drep-project/DREP-Chain/types"
 	"gopkg.in/urfave/cli.v1"
 	"path/filepath"
+	"sync"
 )
 
 var (
@@ -47,6 +50,14 @@ type AccountService struct {
 	Config             *accountTypes.Config
 	Wallet             *Wallet
 	apis               []app.API
+
+	historyLock sync.RWMutex
+	txHistory   TxHistory
+
+	// deploys are the contract deployments of this node waiting for their receipt
+	deployLock sync.Mutex
+	deploys    map[crypto.Hash]*pendingDeploy
+	deploySub  event.Subscription
 }
 
 // Name service name
@@ -67,7 +78,7 @@ func (accountService *AccountService) Api() []app.API {
 				accountService:     accountService,
 				databaseService:    accountService.DatabaseService,
 			},
-			Public: true,
+			Public: false,
 		},
 	}
 }
@@ -81,8 +92,8 @@ func (accountService *AccountService) P2pMessages() map[int]interface{} {
 	return map[int]interface{}{}
 }
 
-// Init  set console Config
-func (accountService *AccountService) Init(executeContext *app.ExecuteContext) error {
+// ApplyFlags set keystore dir, password and enable wallet from command line flags
+func (accountService *AccountService) ApplyFlags(executeContext *app.ExecuteContext) {
 	if len(accountService.Config.KeyStoreDir) == 0 {
 		accountService.Config.KeyStoreDir = filepath.Join(executeContext.CommonConfig.HomeDir, "keystore")
 	} else {
@@ -103,6 +114,41 @@ func (accountService *AccountService) Init(executeContext *app.ExecuteContext) e
 		accountService.Config.KeyStoreDir = executeContext.Cli.GlobalString(KeyStoreDirFlag.Name)
 	}
 
+	if accountService.Config.Signer != "" && !filepath.IsAbs(accountService.Config.Signer) {
+		accountService.Config.Signer = filepath.Join(executeContext.CommonConfig.HomeDir, accountService.Config.Signer)
+	}
+
+	// every keystore has its own watch list beside it, the keystore directory only holds keys
+	if accountService.Config.WatchFile == "" {
+		accountService.Config.WatchFile = filepath.Clean(accountService.Config.KeyStoreDir) + ".watchonly.json"
+	} else if !filepath.IsAbs(accountService.Config.WatchFile) {
+		accountService.Config.WatchFile = filepath.Join(executeContext.CommonConfig.HomeDir, accountService.Config.WatchFile)
+	}
+}
+
+// SetTxHistory set the transaction history listed with watch-only addresses
+func (accountService *AccountService) SetTxHistory(history TxHistory) {
+	accountService.historyLock.Lock()
+	defer accountService.historyLock.Unlock()
+	accountService.txHistory = history
+}
+
+// watchHistory return the first transactions of the watch-only address addr, nil without history
+func (accountService *AccountService) watchHistory(addr *crypto.CommonAddress) *RpcTxHistory {
+	accountService.historyLock.RLock()
+	history := accountService.txHistory
+	accountService.historyLock.RUnlock()
+	if history == nil {
+		return nil
+	}
+	return &RpcTxHistory{
+		Sent:     history.SentTransactions(addr, 1, watchHistorySize),
+		Received: history.ReceivedTransactions(addr, 1, watchHistorySize),
+	}
+}
+
+// Init  set console Config
+func (accountService *AccountService) Init(executeContext *app.ExecuteContext) error {
 	if !accountService.Config.Enable {
 		return nil
 	}
@@ -112,7 +158,8 @@ func (accountService *AccountService) Init(executeContext *app.ExecuteContext) e
 	if err != nil {
 		return err
 	}
-	if accountService.Config.Password != "" {
+	// an external signer needs no password, its keys are unlocked by the signer daemon
+	if accountService.Config.Type == "external" || accountService.Config.Password != "" {
 		err = accountService.Wallet.Open(accountService.Config.Password)
 		if err != nil {
 			return err
@@ -121,34 +168,86 @@ func (accountService *AccountService) Init(executeContext *app.ExecuteContext) e
 	return nil
 }
 
+// ValidateConfig check that an external keystore has the socket of its signer
+func (accountService *AccountService) ValidateConfig(executeContext *app.ExecuteContext) app.ConfigErrors {
+	if accountService.Config.Type == "external" && accountService.Config.Signer == "" {
+		return app.ConfigErrors{app.NewConfigError("signer socket is required for external keystore", MODULENAME, "signer")}
+	}
+	return nil
+}
+
 func (accountService *AccountService) Start(executeContext *app.ExecuteContext) error {
-	if accountService.Config.Enable {
+	if !accountService.Config.Enable {
 		return nil
 	}
+	accountService.loadDeploys()
+	events := make(chan *chainTypes.ChainEvent, 10)
+	accountService.deploySub = accountService.Chain.NewBlockFeed().Subscribe(events)
+	go accountService.deployLoop(events)
 	return nil
 }
 
 func (accountService *AccountService) Stop(executeContext *app.ExecuteContext) error {
-	if accountService.Config.Enable {
-		return nil
+	if accountService.deploySub != nil {
+		accountService.deploySub.Unsubscribe()
 	}
 	return nil
 }
 
 func (accountService *AccountService) CreateWallet(password string) error {
+	store, err := accountService.newKeyStore()
+	if err != nil {
+		return err
+	}
+	password = string(sha3.Keccak256([]byte(password)))
+	newNode := chainTypes.NewNode(nil, accountService.Chain.GetConfig().ChainId)
+	store.StoreKey(newNode, password)
+	return nil
+}
+
+// CreateHDWallet create a wallet from BIP39 mnemonic, the master key is stored to derive accounts.
+// The first account on the default BIP44 path is created with the following accounts used on
+// chain, they are discovered up to the gap limit of BIP44.
+func (accountService *AccountService) CreateHDWallet(password, mnemonic string) error {
+	master, err := NewMasterNode(mnemonic, accountService.Chain.GetConfig().ChainId)
+	if err != nil {
+		return err
+	}
+	accounts, err := DiscoverAccounts(master, accountService.accountUsed)
+	if err != nil {
+		return err
+	}
+	store, err := accountService.newKeyStore()
+	if err != nil {
+		return err
+	}
+	password = string(sha3.Keccak256([]byte(password)))
+	if err := store.StoreKey(master, password); err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if err := store.StoreKey(account, password); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accountUsed check whether an address has sent transactions or holds balance
+func (accountService *AccountService) accountUsed(addr *crypto.CommonAddress) bool {
+	return accountService.DatabaseService.GetNonce(addr) > 0 || accountService.DatabaseService.GetBalance(addr).Sign() > 0
+}
+
+// newKeyStore open the keystore of a new wallet, it must be empty
+func (accountService *AccountService) newKeyStore() (accountComponent.KeyStore, error) {
 	if fileutil.IsDirExists(accountService.Config.KeyStoreDir) {
 		if !fileutil.IsEmptyDir(accountService.Config.KeyStoreDir) {
-			return ErrExistKeystore
+			return nil, ErrExistKeystore
 		}
 	} else {
 		fileutil.EnsureDir(accountService.Config.KeyStoreDir)
 	}
-
-	store := accountComponent.NewFileStore(accountService.Config.KeyStoreDir)
-	password = string(sha3.Keccak256([]byte(password)))
-	newNode := chainTypes.NewNode(nil, accountService.Chain.GetConfig().ChainId)
-	store.StoreKey(newNode, password)
-	return nil
+	return accountComponent.NewFileStore(accountService.Config.KeyStoreDir), nil
 }
 
 func (accountService *AccountService) DefaultConfig() *accountTypes.Config {
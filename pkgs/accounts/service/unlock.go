@@ -0,0 +1,211 @@
+package service
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+// unlock of the wallet or of one account, a zero expire lasts until the wallet is locked
+type unlock struct {
+	expire time.Time
+	timer  *time.Timer
+}
+
+func (u *unlock) stop() {
+	if u != nil && u.timer != nil {
+		u.timer.Stop()
+	}
+}
+
+// WalletStatus is the lock state of the wallet
+type WalletStatus struct {
+	Open     bool `json:"open"`
+	External bool `json:"external"`
+	Locked   bool `json:"locked"`
+	// UnlockExpire is the unix time the wallet locks again, zero if it stays unlocked
+	UnlockExpire int64 `json:"unlockExpire,omitempty"`
+}
+
+// AccountStatus is the lock state of an address of the wallet
+type AccountStatus struct {
+	Address   crypto.CommonAddress `json:"address"`
+	WatchOnly bool                 `json:"watchOnly"`
+	Unlocked  bool                 `json:"unlocked"`
+	// UnlockExpire is the unix time the account locks again, zero if it stays unlocked
+	UnlockExpire int64 `json:"unlockExpire,omitempty"`
+}
+
+// newUnlock start an unlock lasting duration, onExpire is called with unlockLock held and must
+// check that u is still the current unlock
+func (wallet *Wallet) newUnlock(duration time.Duration, onExpire func(u *unlock)) *unlock {
+	u := &unlock{}
+	if duration > 0 {
+		u.expire = time.Now().Add(duration)
+		u.timer = time.AfterFunc(duration, func() {
+			wallet.unlockLock.Lock()
+			defer wallet.unlockLock.Unlock()
+			onExpire(u)
+		})
+	}
+	return u
+}
+
+// UnLockFor unlock all keys of the wallet for duration, a zero duration unlocks until the wallet
+// is locked
+func (wallet *Wallet) UnLockFor(password string, duration time.Duration) error {
+	if wallet.signer != nil {
+		return ErrExternalSigner
+	}
+	var err error
+	if wallet.cacheStore == nil {
+		err = wallet.Open(password)
+	} else {
+		err = wallet.unLock(password)
+	}
+	if err != nil {
+		return err
+	}
+
+	wallet.unlockLock.Lock()
+	defer wallet.unlockLock.Unlock()
+	wallet.walletUnlock.stop()
+	wallet.walletUnlock = wallet.newUnlock(duration, func(u *unlock) {
+		if wallet.walletUnlock == u {
+			wallet.lock()
+		}
+	})
+	return nil
+}
+
+// UnLockAccount unlock only the key of addr for duration while the rest of the wallet stays
+// locked, a zero duration unlocks until the wallet is locked
+func (wallet *Wallet) UnLockAccount(addr *crypto.CommonAddress, password string, duration time.Duration) error {
+	if err := wallet.checkWallet(RPERMISSION); err != nil {
+		return err
+	}
+	if wallet.watchList.contains(addr) {
+		return ErrWatchOnly
+	}
+	if err := wallet.cacheStore.ReloadKey(addr, wallet.cryptoPassword(password)); err != nil {
+		return err
+	}
+
+	account := *addr
+	wallet.unlockLock.Lock()
+	defer wallet.unlockLock.Unlock()
+	wallet.unlocks[account].stop()
+	wallet.unlocks[account] = wallet.newUnlock(duration, func(u *unlock) {
+		if wallet.unlocks[account] != u {
+			return
+		}
+		delete(wallet.unlocks, account)
+		if wallet.IsLock() {
+			wallet.cacheStore.ClearKey(&account)
+		}
+	})
+	return nil
+}
+
+// lock clear all keys and unlocks, unlockLock must be held
+func (wallet *Wallet) lock() {
+	wallet.walletUnlock.stop()
+	wallet.walletUnlock = nil
+	for addr, u := range wallet.unlocks {
+		u.stop()
+		delete(wallet.unlocks, addr)
+	}
+	atomic.StoreInt32(&wallet.isLock, LOCKED)
+	if wallet.cacheStore != nil {
+		wallet.cacheStore.ClearKeys()
+	}
+	wallet.clearMultiSigSessions()
+}
+
+// checkKey check that the key of addr may sign, either the wallet or the account is unlocked
+func (wallet *Wallet) checkKey(addr *crypto.CommonAddress) error {
+	if err := wallet.checkWallet(RPERMISSION); err != nil {
+		return err
+	}
+	if wallet.watchList.contains(addr) {
+		return ErrWatchOnly
+	}
+	if !wallet.IsLock() {
+		return nil
+	}
+	wallet.unlockLock.Lock()
+	defer wallet.unlockLock.Unlock()
+	if _, ok := wallet.unlocks[*addr]; ok {
+		return nil
+	}
+	return ErrLockedWallet
+}
+
+// unlockedKey return the key of addr if it may sign
+func (wallet *Wallet) unlockedKey(addr *crypto.CommonAddress) (*types.Node, error) {
+	if err := wallet.checkKey(addr); err != nil {
+		return nil, err
+	}
+	node, err := wallet.cacheStore.GetKey(addr, wallet.password)
+	if err != nil {
+		return nil, err
+	}
+	// the unlock may have expired since the check
+	if node.PrivateKey == nil {
+		return nil, ErrLockedWallet
+	}
+	return node, nil
+}
+
+// Status return the lock state of the wallet
+func (wallet *Wallet) Status() *WalletStatus {
+	status := &WalletStatus{
+		Open:     wallet.IsOpen(),
+		External: wallet.signer != nil,
+	}
+	if !status.Open || status.External {
+		status.Locked = !status.Open
+		return status
+	}
+	status.Locked = wallet.IsLock()
+	wallet.unlockLock.Lock()
+	defer wallet.unlockLock.Unlock()
+	if !status.Locked && wallet.walletUnlock != nil && !wallet.walletUnlock.expire.IsZero() {
+		status.UnlockExpire = wallet.walletUnlock.expire.Unix()
+	}
+	return status
+}
+
+// AccountStatus return the lock state of every address of the wallet, watch-only addresses
+// are never unlocked
+func (wallet *Wallet) AccountStatus() ([]*AccountStatus, error) {
+	addrs, err := wallet.ListAddress()
+	if err != nil {
+		return nil, err
+	}
+	walletStatus := wallet.Status()
+
+	wallet.unlockLock.Lock()
+	defer wallet.unlockLock.Unlock()
+	statuses := make([]*AccountStatus, len(addrs))
+	for i, addr := range addrs {
+		status := &AccountStatus{Address: *addr}
+		statuses[i] = status
+		if wallet.watchList.contains(addr) {
+			status.WatchOnly = true
+			continue
+		}
+		if !walletStatus.Locked {
+			status.Unlocked = true
+			status.UnlockExpire = walletStatus.UnlockExpire
+		} else if u, ok := wallet.unlocks[*addr]; ok {
+			status.Unlocked = true
+			if !u.expire.IsZero() {
+				status.UnlockExpire = u.expire.Unix()
+			}
+		}
+	}
+	return statuses, nil
+}
@@ -6,9 +6,12 @@ import (
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
 	"github.com/drep-project/DREP-Chain/crypto/sha3"
 	accountsComponent "github.com/drep-project/DREP-Chain/pkgs/accounts/component"
+	"github.com/drep-project/DREP-Chain/pkgs/accounts/signer"
 	accountTypes "github.com/drep-project/DREP-Chain/pkgs/accounts/types"
 	"github.com/drep-project/DREP-Chain/types"
 	"github.com/pkg/errors"
+	"io/ioutil"
+	"sync"
 	"sync/atomic"
 )
 
@@ -25,28 +28,56 @@ const (
 //Wallet is used to manage private keys, build simple transactions and other functions.
 type Wallet struct {
 	cacheStore *accountsComponent.CacheStore
+	// signer holds the keys instead of cacheStore when the wallet type is external
+	signer *signer.Client
 
 	chainId types.ChainIdType
 	config  *accountTypes.Config
 
 	isLock   int32
 	password string
+
+	// unlockLock guards the expiry of the wallet unlock and the accounts unlocked alone
+	unlockLock   sync.Mutex
+	walletUnlock *unlock
+	unlocks      map[crypto.CommonAddress]*unlock
+
+	watchList *watchList
+
+	// sessions are the multisig signing sessions waiting for the commitments of other signers
+	sessionLock sync.Mutex
+	sessions    map[string]*multiSigSession
 }
 
 // NewWallet based in config
 func NewWallet(config *accountTypes.Config, chainId types.ChainIdType) (*Wallet, error) {
+	watchList, err := loadWatchList(config.WatchFile)
+	if err != nil {
+		return nil, err
+	}
 	wallet := &Wallet{
-		config:  config,
-		chainId: chainId,
+		config:    config,
+		chainId:   chainId,
+		sessions:  make(map[string]*multiSigSession),
+		unlocks:   make(map[crypto.CommonAddress]*unlock),
+		watchList: watchList,
 	}
 	return wallet, nil
 }
 
 // Open wallet to use wallet
 func (wallet *Wallet) Open(password string) error {
-	if wallet.cacheStore != nil {
+	if wallet.IsOpen() {
 		return ErrClosedWallet
 	}
+	if wallet.config.Type == "external" {
+		client, err := signer.Dial(wallet.config.Signer)
+		if err != nil {
+			return err
+		}
+		wallet.signer = client
+		return nil
+	}
 	cryptedPassword := wallet.cryptoPassword(password)
 
 	var store accountsComponent.KeyStore
@@ -58,6 +89,16 @@ func (wallet *Wallet) Open(password string) error {
 		store = accountsComponent.NewFileStore(wallet.config.KeyStoreDir)
 	}
 
+	if migrator, ok := store.(accountsComponent.Migrator); ok {
+		migrated, err := migrator.Migrate()
+		if err != nil {
+			return err
+		}
+		if migrated > 0 {
+			log.WithField("keys", migrated).WithField("version", accountsComponent.KeyStoreVersion).Info("keystore migrated")
+		}
+	}
+
 	accountCacheStore, err := accountsComponent.NewCacheStore(store, cryptedPassword)
 	if err != nil {
 		return err
@@ -76,16 +117,29 @@ func (wallet *Wallet) Open(password string) error {
 
 // Close wallet to disable wallet
 func (wallet *Wallet) Close() {
+	if wallet.signer != nil {
+		wallet.signer.Close()
+		wallet.signer = nil
+		return
+	}
 	wallet.Lock()
 	wallet.cacheStore = nil
 	wallet.password = ""
 }
 
-// NewAccount create new address
+// NewAccount create new address, wallets created from mnemonic derive the next account on the
+// default BIP44 path
 func (wallet *Wallet) NewAccount() (*types.Node, error) {
 	if err := wallet.checkWallet(WPERMISSION); err != nil {
 		return nil, err
 	}
+	master, err := wallet.masterNode()
+	if err != nil {
+		return nil, err
+	}
+	if master != nil {
+		return wallet.deriveNextAccount()
+	}
 
 	newNode := types.NewNode(nil, wallet.chainId)
 	wallet.cacheStore.StoreKey(newNode, wallet.password)
@@ -95,7 +149,7 @@ func (wallet *Wallet) NewAccount() (*types.Node, error) {
 // GetAccountByAddress query account according to address
 func (wallet *Wallet) GetAccountByAddress(addr *crypto.CommonAddress) (*types.Node, error) {
 	if err := wallet.checkWallet(RPERMISSION); err != nil {
-		return nil, ErrClosedWallet
+		return nil, err
 	}
 	return wallet.cacheStore.GetKey(addr, wallet.password)
 }
@@ -103,16 +157,27 @@ func (wallet *Wallet) GetAccountByAddress(addr *crypto.CommonAddress) (*types.No
 // GetAccountByAddress query account according to public key
 func (wallet *Wallet) GetAccountByPubkey(pubkey *secp256k1.PublicKey) (*types.Node, error) {
 	if err := wallet.checkWallet(RPERMISSION); err != nil {
-		return nil, ErrClosedWallet
+		return nil, err
 	}
 	addr := crypto.PubkeyToAddress(pubkey)
 	return wallet.GetAccountByAddress(&addr)
 }
 
-// ListAddress get all address in wallet
+// ListAddress get all address in wallet, watch-only addresses come after the keys
 func (wallet *Wallet) ListAddress() ([]*crypto.CommonAddress, error) {
+	if wallet.signer != nil {
+		addrs, err := wallet.signer.Accounts()
+		if err != nil {
+			return nil, err
+		}
+		addreses := make([]*crypto.CommonAddress, len(addrs))
+		for i := range addrs {
+			addreses[i] = &addrs[i]
+		}
+		return append(addreses, wallet.watchList.list()...), nil
+	}
 	if err := wallet.checkWallet(RPERMISSION); err != nil {
-		return nil, ErrClosedWallet
+		return nil, err
 	}
 	nodes, err := wallet.cacheStore.ExportKey(wallet.password)
 	if err != nil {
@@ -120,18 +185,17 @@ func (wallet *Wallet) ListAddress() ([]*crypto.CommonAddress, error) {
 	}
 	addreses := []*crypto.CommonAddress{}
 	for _, node := range nodes {
+		if isMasterNode(node) {
+			continue
+		}
 		addreses = append(addreses, node.Address)
 	}
-	return addreses, nil
+	return append(addreses, wallet.watchList.list()...), nil
 }
 
 // DumpPrivateKey query private key by address
 func (wallet *Wallet) DumpPrivateKey(addr *crypto.CommonAddress) (*secp256k1.PrivateKey, error) {
-	if err := wallet.checkWallet(WPERMISSION); err != nil {
-		return nil, err
-	}
-
-	node, err := wallet.cacheStore.GetKey(addr, wallet.password)
+	node, err := wallet.unlockedKey(addr)
 	if err != nil {
 		return nil, err
 	}
@@ -143,11 +207,10 @@ func (wallet *Wallet) Sign(addr *crypto.CommonAddress, msg []byte) ([]byte, erro
 	if len(msg) != 32 {
 		return nil, ErrNotAHash
 	}
-	if err := wallet.checkWallet(WPERMISSION); err != nil {
-		return nil, err
+	if wallet.signer != nil {
+		return wallet.signer.SignHash(addr, msg)
 	}
-
-	node, err := wallet.cacheStore.GetKey(addr, wallet.password)
+	node, err := wallet.unlockedKey(addr)
 	if err != nil {
 		return nil, err
 	}
@@ -158,39 +221,78 @@ func (wallet *Wallet) Sign(addr *crypto.CommonAddress, msg []byte) ([]byte, erro
 	return sig, nil
 }
 
-// IsLock query current lock state  0 is locked  1 is unlock
+// SignTx sign tx with the key of addr, an external signer gets the whole transaction so that it
+// can check the transaction against its rules
+func (wallet *Wallet) SignTx(addr *crypto.CommonAddress, tx *types.Transaction) ([]byte, error) {
+	if wallet.signer != nil {
+		return wallet.signer.SignTransaction(addr, tx)
+	}
+	return wallet.Sign(addr, tx.TxHash().Bytes())
+}
+
+// KeySigner return the signer of the producer key pubkey
+func (wallet *Wallet) KeySigner(pubkey *secp256k1.PublicKey) (signer.KeySigner, error) {
+	if wallet.signer != nil {
+		return wallet.signer.KeySigner(pubkey)
+	}
+	node, err := wallet.GetAccountByPubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+	return signer.NewLocalKeySigner(node.PrivateKey), nil
+}
+
+// IsLock query current lock state  0 is locked  1 is unlock, an external signer is never locked
+// by the node
 func (wallet *Wallet) IsLock() bool {
+	if wallet.signer != nil {
+		return false
+	}
 	return atomic.LoadInt32(&wallet.isLock) == LOCKED
 }
 
 // IsOpen query current wallet open state
 func (wallet *Wallet) IsOpen() bool {
-	return wallet.cacheStore != nil
+	return wallet.cacheStore != nil || wallet.signer != nil
 }
 
-// Lock wallet to disable private key
+// Lock wallet to disable private key, accounts unlocked alone are locked too
 func (wallet *Wallet) Lock() error {
-	atomic.StoreInt32(&wallet.isLock, LOCKED)
-	wallet.cacheStore.ClearKeys()
+	if wallet.signer != nil {
+		return ErrExternalSigner
+	}
+	wallet.unlockLock.Lock()
+	defer wallet.unlockLock.Unlock()
+	wallet.lock()
 	return nil
 }
 
-// UnLock wallet to enable private key
+// UnLock wallet to enable private key until it is locked
 func (wallet *Wallet) UnLock(password string) error {
-	if wallet.cacheStore == nil {
-		return wallet.Open(password)
-	} else {
-		return wallet.unLock(password)
-	}
+	return wallet.UnLockFor(password, 0)
 }
 
 func (wallet *Wallet) unLock(password string) error {
+	cryptedPassword := wallet.cryptoPassword(password)
+	if err := wallet.cacheStore.ReloadKeys(cryptedPassword); err != nil {
+		return err
+	}
+	wallet.password = cryptedPassword
 	atomic.StoreInt32(&wallet.isLock, UNLOCKED)
-	wallet.password = wallet.cryptoPassword(password)
-	return wallet.cacheStore.ReloadKeys(wallet.password)
+	return nil
+}
+
+// HasUnLockedAccount query whether some account is unlocked alone
+func (wallet *Wallet) HasUnLockedAccount() bool {
+	wallet.unlockLock.Lock()
+	defer wallet.unlockLock.Unlock()
+	return len(wallet.unlocks) > 0
 }
 
 func (wallet *Wallet) checkWallet(op int) error {
+	if wallet.signer != nil {
+		return ErrExternalSigner
+	}
 	if wallet.cacheStore == nil {
 		return ErrClosedWallet
 	}
@@ -224,34 +326,72 @@ func (wallet *Wallet) ImportPrivKey(key *secp256k1.PrivateKey) (*types.Node, err
 	if err != nil {
 		return nil, err
 	}
+	if err := wallet.unwatchImported(&addr); err != nil {
+		return nil, err
+	}
 	return node, nil
 }
 
+// WatchAddress add a watch-only address, it is listed and tracked without a key
+func (wallet *Wallet) WatchAddress(addr *crypto.CommonAddress) error {
+	if wallet.cacheStore != nil {
+		if _, err := wallet.cacheStore.GetKey(addr, wallet.password); err == nil {
+			return errors.Wrap(ErrExistKey, addr.String())
+		}
+	}
+	return wallet.watchList.add(addr)
+}
+
+// UnWatchAddress remove a watch-only address
+func (wallet *Wallet) UnWatchAddress(addr *crypto.CommonAddress) error {
+	return wallet.watchList.remove(addr)
+}
+
+// IsWatchOnly query whether addr is a watch-only address
+func (wallet *Wallet) IsWatchOnly(addr *crypto.CommonAddress) bool {
+	return wallet.watchList.contains(addr)
+}
+
+// unwatchImported stop watching addr once its key is in the wallet
+func (wallet *Wallet) unwatchImported(addr *crypto.CommonAddress) error {
+	if !wallet.watchList.contains(addr) {
+		return nil
+	}
+	return wallet.watchList.remove(addr)
+}
+
+// ImportKeyStore import the keys of a keystore directory or of a single key file, both keys of
+// this project and Ethereum V3 keystore files are read
 func (wallet *Wallet) ImportKeyStore(path, password string) ([]*crypto.CommonAddress, error) {
 	if err := wallet.checkWallet(WPERMISSION); err != nil {
 		return nil, err
 	}
-	if !fileutil.IsDirExists(path) {
+	files := []string{}
+	if fileutil.IsDirExists(path) {
+		err := fileutil.EachChildFile(path, func(path string) (bool, error) {
+			files = append(files, path)
+			return true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if fileutil.IsFileExists(path) {
+		files = append(files, path)
+	} else {
 		return nil, errors.Wrap(ErrMissingKeystore, path)
 	}
 
-	newWallet, err := NewWallet(&accountTypes.Config{
-		Enable:      true,
-		Type:        "keystore",
-		KeyStoreDir: path,
-	}, wallet.chainId)
-	if err != nil {
-		return nil, err
-	}
-	err = newWallet.Open(password)
-	if err != nil {
-		return nil, err
-	}
-	nodes, err := newWallet.cacheStore.ExportKey(password)
 	addrs := []*crypto.CommonAddress{}
-	for _, node := range nodes {
-		_, err := wallet.cacheStore.GetKey(node.Address, wallet.password)
-		if err == nil {
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return addrs, err
+		}
+		node, err := wallet.decodeKeyFile(content, password)
+		if err != nil {
+			return addrs, errors.Wrap(err, file)
+		}
+		if _, err := wallet.cacheStore.GetKey(node.Address, wallet.password); err == nil {
 			log.WithField("addr", node.Address.String()).Info("privkey exist")
 			continue
 		}
@@ -259,7 +399,47 @@ func (wallet *Wallet) ImportKeyStore(path, password string) ([]*crypto.CommonAdd
 		if err != nil {
 			return addrs, err
 		}
+		if err := wallet.unwatchImported(node.Address); err != nil {
+			return addrs, err
+		}
 		addrs = append(addrs, node.Address)
 	}
 	return addrs, nil
 }
+
+// ImportEthKey import an Ethereum V3 keystore file encrypted with password
+func (wallet *Wallet) ImportEthKey(content []byte, password string) (*types.Node, error) {
+	key, err := accountsComponent.DecryptEthKey(content, password)
+	if err != nil {
+		return nil, err
+	}
+	return wallet.ImportPrivKey(key)
+}
+
+// ExportEthKey export the key of addr as an Ethereum V3 keystore file encrypted with password,
+// which may differ from the password of the wallet
+func (wallet *Wallet) ExportEthKey(addr *crypto.CommonAddress, password string) ([]byte, error) {
+	key, err := wallet.DumpPrivateKey(addr)
+	if err != nil {
+		return nil, err
+	}
+	return accountsComponent.EncryptEthKey(key, password, accountsComponent.StandardScryptN, accountsComponent.StandardScryptP)
+}
+
+// decodeKeyFile decrypt a key file of this project, encrypted with the hash of password, or an
+// Ethereum V3 keystore file, encrypted with password itself
+func (wallet *Wallet) decodeKeyFile(content []byte, password string) (*types.Node, error) {
+	if accountsComponent.IsEthKeyJSON(content) {
+		key, err := accountsComponent.DecryptEthKey(content, password)
+		if err != nil {
+			return nil, err
+		}
+		addr := crypto.PubkeyToAddress(key.PubKey())
+		return &types.Node{
+			Address:    &addr,
+			PrivateKey: key,
+			ChainId:    wallet.chainId,
+		}, nil
+	}
+	return accountsComponent.BytesToCryptoNode(content, wallet.cryptoPassword(password))
+}
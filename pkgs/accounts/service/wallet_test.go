@@ -3,14 +3,22 @@ package service
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"github.com/drep-project/DREP-Chain/app"
 	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/hdkey"
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
 	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	accountComponent "github.com/drep-project/DREP-Chain/pkgs/accounts/component"
 	accountTypes "github.com/drep-project/DREP-Chain/pkgs/accounts/types"
 	"github.com/drep-project/DREP-Chain/types"
+	"github.com/pkg/errors"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 var (
@@ -279,3 +287,335 @@ func getWallet(path, password string) (*Wallet, error) {
 	wallet.Open(password)
 	return wallet, nil
 }
+
+func Test_WalletHDAccounts(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	wallet, err := NewWallet(&accountTypes.Config{Enable: true, Type: "memorystore"}, app.ChainIdType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.Open("password"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.DeriveAccount(hdkey.DefaultBaseDerivationPath.Child(0)); err != ErrNotHDWallet {
+		t.Fatalf("expect derive fail without master key, got %v", err)
+	}
+	master, err := NewMasterNode(mnemonic, app.ChainIdType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.cacheStore.StoreKey(master, wallet.password); err != nil {
+		t.Fatal(err)
+	}
+
+	expects := []string{"0x9858effd232b4033e47d90003d41ec34ecaeda94", "0x6fac4d18c912343bf86fa7049364dd4e424ab9c0"}
+	for i, expect := range expects {
+		node, err := wallet.NewAccount()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *node.Address != crypto.HexToAddress(expect) || node.Path != hdkey.DefaultBaseDerivationPath.Child(uint32(i)).String() {
+			t.Errorf("expect account %d at %s, got %s at %s", i, expect, node.Address.String(), node.Path)
+		}
+	}
+	addrs, err := wallet.ListAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range addrs {
+		if *addr == *master.Address {
+			t.Error("expect master key not listed")
+		}
+	}
+	if _, err := NewMasterNode("abandon abandon", app.ChainIdType{}); err != ErrInvalidMnemonic {
+		t.Errorf("expect invalid mnemonic, got %v", err)
+	}
+}
+
+func Test_DiscoverAccounts(t *testing.T) {
+	master, err := NewMasterNode("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", app.ChainIdType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// accounts 1 and 21 are used, 42 follows a gap of 20 unused accounts
+	used := map[string]bool{}
+	for _, index := range []uint32{1, 21, 42} {
+		node, err := DeriveNode(master, hdkey.DefaultBaseDerivationPath.Child(index))
+		if err != nil {
+			t.Fatal(err)
+		}
+		used[node.Address.String()] = true
+	}
+	nodes, err := DiscoverAccounts(master, func(addr *crypto.CommonAddress) bool { return used[addr.String()] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{}
+	for _, node := range nodes {
+		paths = append(paths, node.Path)
+	}
+	expects := []string{"m/44'/60'/0'/0/0", "m/44'/60'/0'/0/1", "m/44'/60'/0'/0/21"}
+	if strings.Join(paths, ",") != strings.Join(expects, ",") {
+		t.Errorf("expect accounts %v, got %v", expects, paths)
+	}
+}
+
+func Test_WalletUnLockAccount(t *testing.T) {
+	password := "password"
+	wallet, err := NewWallet(testConfig, app.ChainIdType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.Open(password)
+	unlocked, err := wallet.NewAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked, err := wallet.NewAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.Lock()
+
+	if err := wallet.UnLockAccount(unlocked.Address, password, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	msg := sha3.Keccak256([]byte("helloworld"))
+	if _, err := wallet.Sign(unlocked.Address, msg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.Sign(locked.Address, msg); err != ErrLockedWallet {
+		t.Fatalf("expect %v, got %v", ErrLockedWallet, err)
+	}
+	if !wallet.IsLock() {
+		t.Fatal("expected wallet stays locked")
+	}
+	statuses, err := wallet.AccountStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if status.Unlocked != (status.Address == *unlocked.Address) {
+			t.Fatalf("unexpected unlock state of %s", status.Address.String())
+		}
+		if status.Unlocked && status.UnlockExpire == 0 {
+			t.Fatal("expected unlock expire time")
+		}
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if _, err := wallet.Sign(unlocked.Address, msg); err != ErrLockedWallet {
+		t.Fatalf("expect %v after expire, got %v", ErrLockedWallet, err)
+	}
+	if wallet.HasUnLockedAccount() {
+		t.Fatal("expected no unlocked account after expire")
+	}
+
+	if err := wallet.UnLockFor(password, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if wallet.IsLock() || wallet.Status().UnlockExpire == 0 {
+		t.Fatal("expected wallet unlocked with expire time")
+	}
+	time.Sleep(200 * time.Millisecond)
+	if !wallet.IsLock() {
+		t.Fatal("expected wallet locked after expire")
+	}
+}
+
+func Test_WatchAddress(t *testing.T) {
+	path, err := ioutil.TempDir("", "watchonly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+	config := &accountTypes.Config{
+		Enable:    true,
+		Type:      "memorystore",
+		WatchFile: filepath.Join(path, "watchonly.json"),
+	}
+	wallet, err := NewWallet(config, app.ChainIdType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.Open("password")
+
+	key, _ := secp256k1.GeneratePrivateKey(nil)
+	watched := crypto.PubkeyToAddress(key.PubKey())
+	if err := wallet.WatchAddress(&watched); err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.WatchAddress(&watched); err != ErrExistWatchOnly {
+		t.Fatalf("expect %v, got %v", ErrExistWatchOnly, err)
+	}
+	if _, err := wallet.Sign(&watched, sha3.Keccak256([]byte("helloworld"))); err != ErrWatchOnly {
+		t.Fatalf("expect %v, got %v", ErrWatchOnly, err)
+	}
+
+	// the list is loaded again by a new wallet
+	reopened, err := NewWallet(config, app.ChainIdType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened.Open("password")
+	addrs, err := reopened.ListAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *addrs[len(addrs)-1] != watched {
+		t.Fatalf("expect watched address %s listed, got %v", watched.String(), addrs)
+	}
+
+	// importing the key ends watching
+	if _, err := reopened.ImportPrivKey(key); err != nil {
+		t.Fatal(err)
+	}
+	if reopened.IsWatchOnly(&watched) {
+		t.Fatal("expected imported address not watched")
+	}
+}
+
+type testTxHistory map[crypto.CommonAddress][]crypto.Hash
+
+func (history testTxHistory) SentTransactions(addr *crypto.CommonAddress, pageIndex, pageSize int) []crypto.Hash {
+	return history[*addr]
+}
+
+func (history testTxHistory) ReceivedTransactions(addr *crypto.CommonAddress, pageIndex, pageSize int) []crypto.Hash {
+	return nil
+}
+
+func Test_WatchHistory(t *testing.T) {
+	watched := crypto.CommonAddress{1}
+	accountService := &AccountService{}
+	if history := accountService.watchHistory(&watched); history != nil {
+		t.Fatalf("expect no history without trace, got %v", history)
+	}
+	sent := []crypto.Hash{{1}, {2}}
+	accountService.SetTxHistory(testTxHistory{watched: sent})
+	history := accountService.watchHistory(&watched)
+	if history == nil || len(history.Sent) != 2 || history.Sent[1] != sent[1] || len(history.Received) != 0 {
+		t.Fatalf("expect sent %v, got %v", sent, history)
+	}
+}
+
+func Test_ImportExportEthKey(t *testing.T) {
+	path, err := ioutil.TempDir("", "ethkeystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	wallet, err := NewWallet(testConfig, app.ChainIdType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.Open("password")
+	node, err := wallet.NewAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := wallet.ExportEthKey(node.Address, "ethpassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(path, "UTC--"+node.Address.Hex()), content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	newWallet, err := NewWallet(testConfig, app.ChainIdType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newWallet.Open("password")
+	addrs, err := newWallet.ImportKeyStore(path, "ethpassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 1 || *addrs[0] != *node.Address {
+		t.Fatalf("expect imported %s, got %v", node.Address.String(), addrs)
+	}
+	privkey, err := newWallet.DumpPrivateKey(node.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(privkey.Serialize(), node.PrivateKey.Serialize()) {
+		t.Fatal("imported key differs from exported key")
+	}
+	if _, err := newWallet.ImportEthKey(content, "ethpassword"); errors.Cause(err) != ErrExistKey {
+		t.Fatalf("expect %v, got %v", ErrExistKey, err)
+	}
+}
+
+func Test_BackupAndRestore(t *testing.T) {
+	path, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+	backupPath := filepath.Join(path, "backup.json")
+
+	wallet, err := NewWallet(testConfig, app.ChainIdType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet.Open("password")
+	if _, err := wallet.NewAccount(); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := wallet.Backup(backupPath, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys != 2 {
+		t.Fatalf("expect 2 keys in backup, got %d", keys)
+	}
+	if _, err := wallet.Backup(backupPath, "passphrase"); errors.Cause(err) != ErrExistBackup {
+		t.Fatalf("expect %v, got %v", ErrExistBackup, err)
+	}
+
+	// restore into a keystore of another backend
+	restored, err := getWallet(filepath.Join(path, "keystore"), "newpassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := restored.Restore(backupPath, "wrongpassphrase"); err != accountComponent.ErrDecrypt {
+		t.Fatalf("expect %v, got %v", accountComponent.ErrDecrypt, err)
+	}
+	result, err := restored.Restore(backupPath, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Restored) != 2 || len(result.Duplicates) != 0 {
+		t.Fatalf("expect 2 keys restored, got %v", result)
+	}
+	for _, addr := range result.Restored {
+		origin, _ := wallet.DumpPrivateKey(addr)
+		privkey, err := restored.DumpPrivateKey(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(privkey.Serialize(), origin.Serialize()) {
+			t.Fatalf("restored key of %s differs", addr.String())
+		}
+	}
+	result, err = restored.Restore(backupPath, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Restored) != 0 || len(result.Duplicates) != 2 {
+		t.Fatalf("expect 2 duplicate keys, got %v", result)
+	}
+
+	// a modified archive fails the mac check
+	content, _ := ioutil.ReadFile(backupPath)
+	backup := &accountComponent.Backup{}
+	json.Unmarshal(content, backup)
+	backup.CipherText[0] ^= 0xff
+	content, _ = json.Marshal(backup)
+	tampered := filepath.Join(path, "tampered.json")
+	ioutil.WriteFile(tampered, content, 0600)
+	if _, err := restored.Restore(tampered, "passphrase"); err != accountComponent.ErrDecrypt {
+		t.Fatalf("expect %v, got %v", accountComponent.ErrDecrypt, err)
+	}
+}
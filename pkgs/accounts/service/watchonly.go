@@ -0,0 +1,121 @@
+package service
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/drep-project/DREP-Chain/common/fileutil"
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+const (
+	// number of sent and of received transactions listed with a watch-only address
+	watchHistorySize = 20
+)
+
+// TxHistory look up the transactions sent and received by an address, the trace service
+// provides it when it is enabled
+type TxHistory interface {
+	SentTransactions(addr *crypto.CommonAddress, pageIndex, pageSize int) []crypto.Hash
+	ReceivedTransactions(addr *crypto.CommonAddress, pageIndex, pageSize int) []crypto.Hash
+}
+
+// RpcTxHistory is the first transactions sent and received by a watch-only address, they are
+// queried by hash with chain_getTransactionByHash
+type RpcTxHistory struct {
+	Sent     []crypto.Hash `json:"sent"`
+	Received []crypto.Hash `json:"received"`
+}
+
+// watchList is the set of watch-only addresses, they are listed with the keys of the wallet
+// but have no key to sign with
+type watchList struct {
+	lock sync.RWMutex
+	// path of the json file holding the list, the list is only kept in memory if empty
+	path  string
+	addrs []crypto.CommonAddress
+}
+
+func loadWatchList(path string) (*watchList, error) {
+	list := &watchList{path: path}
+	if path == "" || !fileutil.IsFileExists(path) {
+		return list, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &list.addrs); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (list *watchList) contains(addr *crypto.CommonAddress) bool {
+	list.lock.RLock()
+	defer list.lock.RUnlock()
+
+	return list.indexOf(addr) >= 0
+}
+
+func (list *watchList) indexOf(addr *crypto.CommonAddress) int {
+	for i := range list.addrs {
+		if list.addrs[i] == *addr {
+			return i
+		}
+	}
+	return -1
+}
+
+func (list *watchList) add(addr *crypto.CommonAddress) error {
+	list.lock.Lock()
+	defer list.lock.Unlock()
+
+	if list.indexOf(addr) >= 0 {
+		return ErrExistWatchOnly
+	}
+	list.addrs = append(list.addrs, *addr)
+	return list.save()
+}
+
+func (list *watchList) remove(addr *crypto.CommonAddress) error {
+	list.lock.Lock()
+	defer list.lock.Unlock()
+
+	index := list.indexOf(addr)
+	if index < 0 {
+		return ErrNotWatchOnly
+	}
+	list.addrs = append(list.addrs[:index], list.addrs[index+1:]...)
+	return list.save()
+}
+
+func (list *watchList) list() []*crypto.CommonAddress {
+	list.lock.RLock()
+	defer list.lock.RUnlock()
+
+	addrs := make([]*crypto.CommonAddress, len(list.addrs))
+	for i := range list.addrs {
+		addr := list.addrs[i]
+		addrs[i] = &addr
+	}
+	return addrs
+}
+
+// save write the list to a temporary file first so a crash never leaves half a list
+func (list *watchList) save() error {
+	if list.path == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(list.addrs, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := list.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, list.path)
+}
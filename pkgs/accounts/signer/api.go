@@ -0,0 +1,352 @@
+package signer
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/binary"
+	"github.com/drep-project/rpc"
+)
+
+const (
+	// Namespace of the signer methods
+	Namespace = "signer"
+
+	// number of nonces committed to multi signatures and waiting for the partial signature
+	maxPendingNonces = 1024
+)
+
+// SignerApi is served by the signer daemon, every signing request is checked against the rules,
+// approved by the operator if required and recorded in the audit log
+type SignerApi struct {
+	keys     map[crypto.CommonAddress]*secp256k1.PrivateKey
+	rules    *Rules
+	approver Approver
+	audit    *AuditLog
+	nonces   *nonceStore
+}
+
+// NewSignerApi return the api signing with keys, approver is only used if the rules require
+// manual approval
+func NewSignerApi(keys []*types.Node, rules *Rules, approver Approver, audit *AuditLog) *SignerApi {
+	api := &SignerApi{
+		keys:     make(map[crypto.CommonAddress]*secp256k1.PrivateKey),
+		rules:    rules,
+		approver: approver,
+		audit:    audit,
+		nonces:   newNonceStore(),
+	}
+	for _, node := range keys {
+		api.keys[*node.Address] = node.PrivateKey
+	}
+	return api
+}
+
+/*
+	 name: accounts
+	 usage: 列出签名服务持有私钥的地址
+	 params:
+	 return: 地址列表
+	 example: echo '{"jsonrpc":"2.0","method":"signer_accounts","params":[],"id":1}' | nc -U signer.ipc
+	 response:
+		 {"jsonrpc":"2.0","id":1,"result":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5"]}
+*/
+func (api *SignerApi) Accounts() []crypto.CommonAddress {
+	addrs := make([]crypto.CommonAddress, 0, len(api.keys))
+	for addr := range api.keys {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+/*
+	 name: pubKey
+	 usage: 查询地址的公钥
+	 params:
+		1. 地址
+	 return: 公钥
+	 example: echo '{"jsonrpc":"2.0","method":"signer_pubKey","params":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5"],"id":1}' | nc -U signer.ipc
+	 response:
+		 {"jsonrpc":"2.0","id":1,"result":"0x03177b8e4ef31f4f801ce00260db1b04cc501287e828692a404fdbc46c7ad6ff26"}
+*/
+func (api *SignerApi) PubKey(addr crypto.CommonAddress) (*secp256k1.PublicKey, error) {
+	key, ok := api.keys[addr]
+	if !ok {
+		return nil, ErrUnknownAccount
+	}
+	return key.PubKey(), nil
+}
+
+/*
+	 name: signTransaction
+	 usage: 按规则检查交易的金额和接收者后签名
+	 params:
+		1. 发送者地址
+		2. 交易数据
+	 return: 交易签名
+	 example: echo '{"jsonrpc":"2.0","method":"signer_signTransaction","params":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5",{"Version":0,"Nonce":1,"Type":0,"To":"0x...","ChainId":0,"Amount":"0x111","GasPrice":"0x110","GasLimit":"0x30000","Timestamp":1559322808,"Data":null}],"id":1}' | nc -U signer.ipc
+	 response:
+		 {"jsonrpc":"2.0","id":1,"result":"0x1f1d16412468dd9b67b568d31839ac608bdfddf2580666db4d364eefbe285fdaed569a3c8fa1decfebbfa0ed18b636059dbbf4c2106c45fc8846909833ef2cb1de"}
+*/
+func (api *SignerApi) SignTransaction(addr crypto.CommonAddress, data types.TransactionData) (common.Bytes, error) {
+	tx := &types.Transaction{Data: data}
+	hash := tx.TxHash().Bytes()
+	amount := data.Amount
+	entry := &AuditEntry{Method: "signTransaction", Address: addr, Hash: hash, Amount: &amount}
+	if data.Type == types.TransferType || data.Type == types.CallContractType {
+		entry.To = &data.To
+	}
+	key, err := api.check(entry, func() error { return api.rules.CheckTransaction(&data) })
+	if err != nil {
+		return nil, err
+	}
+	return api.sign(entry, func() ([]byte, error) { return secp256k1.SignCompact(key, hash, true) })
+}
+
+/*
+	 name: signHash
+	 usage: 对任意哈希签名，需要规则允许
+	 params:
+		1. 地址
+		2. 哈希
+	 return: 签名
+	 example: echo '{"jsonrpc":"2.0","method":"signer_signHash","params":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","0x00001c9b8c8fdb1f53faf02321f76253704123e2b56cce065852bab93e526ae2"],"id":1}' | nc -U signer.ipc
+	 response:
+		 {"jsonrpc":"2.0","id":1,"result":"0x1f1d16412468dd9b67b568d31839ac608bdfddf2580666db4d364eefbe285fdaed569a3c8fa1decfebbfa0ed18b636059dbbf4c2106c45fc8846909833ef2cb1de"}
+*/
+func (api *SignerApi) SignHash(addr crypto.CommonAddress, hash common.Bytes) (common.Bytes, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("hash must be 32 bytes")
+	}
+	entry := &AuditEntry{Method: "signHash", Address: addr, Hash: hash}
+	key, err := api.check(entry, api.rules.CheckHash)
+	if err != nil {
+		return nil, err
+	}
+	return api.sign(entry, func() ([]byte, error) { return secp256k1.SignCompact(key, hash, true) })
+}
+
+/*
+	 name: signBlock
+	 usage: 出块节点对区块签名消息的哈希签名，地址需要在规则的producers中，消息必须是区块头或共识消息，规则允许时也可以是32字节的哈希
+	 params:
+		1. 出块地址
+		2. 区块签名消息
+	 return: DER编码的签名
+	 example: echo '{"jsonrpc":"2.0","method":"signer_signBlock","params":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","0x0000000001000000..."],"id":1}' | nc -U signer.ipc
+	 response:
+		 {"jsonrpc":"2.0","id":1,"result":"0x3045..."}
+*/
+func (api *SignerApi) SignBlock(addr crypto.CommonAddress, msg common.Bytes) (common.Bytes, error) {
+	entry := &AuditEntry{Method: "signBlock", Address: addr}
+	key, hash, err := api.checkProducer(entry, msg)
+	if err != nil {
+		return nil, err
+	}
+	return api.sign(entry, func() ([]byte, error) {
+		sig, err := key.Sign(hash)
+		if err != nil {
+			return nil, err
+		}
+		return sig.Serialize(), nil
+	})
+}
+
+/*
+	 name: schnorrNonce
+	 usage: bft共识中返回对区块多签的公共随机数，消息的要求同signBlock
+	 params:
+		1. 出块地址
+		2. 区块或共识消息的签名消息
+	 return: 随机数公钥
+	 example: echo '{"jsonrpc":"2.0","method":"signer_schnorrNonce","params":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","0x0000000001000000..."],"id":1}' | nc -U signer.ipc
+	 response:
+		 {"jsonrpc":"2.0","id":1,"result":"0x03177b8e4ef31f4f801ce00260db1b04cc501287e828692a404fdbc46c7ad6ff26"}
+*/
+func (api *SignerApi) SchnorrNonce(addr crypto.CommonAddress, msg common.Bytes) (*secp256k1.PublicKey, error) {
+	entry := &AuditEntry{Method: "schnorrNonce", Address: addr}
+	key, hash, err := api.checkProducer(entry, msg)
+	if err != nil {
+		return nil, err
+	}
+	var nonce *secp256k1.PublicKey
+	if _, err := api.sign(entry, func() ([]byte, error) {
+		nonce, err = api.nonces.generate(addr.String()+common.Encode(hash), key, hash)
+		if err != nil {
+			return nil, err
+		}
+		return nonce.SerializeCompressed(), nil
+	}); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+/*
+	 name: schnorrPartialSign
+	 usage: bft共识中对区块多签的部分签名，同一区块只能用同一随机数和签名一次，消息的要求同signBlock
+	 params:
+		1. 出块地址
+		2. 区块或共识消息的签名消息
+		3. 其他签名者随机数公钥之和
+	 return: 部分签名
+	 example: echo '{"jsonrpc":"2.0","method":"signer_schnorrPartialSign","params":["0x3ebcbe7cb440dd8c52940a2963472380afbb56c5","0x0000000001000000...","0x03..."],"id":1}' | nc -U signer.ipc
+	 response:
+		 {"jsonrpc":"2.0","id":1,"result":"0x..."}
+*/
+func (api *SignerApi) SchnorrPartialSign(addr crypto.CommonAddress, msg common.Bytes, pubSum *secp256k1.PublicKey) (common.Bytes, error) {
+	if pubSum == nil {
+		return nil, fmt.Errorf("nonce sum is required")
+	}
+	entry := &AuditEntry{Method: "schnorrPartialSign", Address: addr}
+	key, hash, err := api.checkProducer(entry, msg)
+	if err != nil {
+		return nil, err
+	}
+	nonce := api.nonces.take(addr.String() + common.Encode(hash))
+	if nonce == nil {
+		return nil, api.reject(entry, ErrNoNonce)
+	}
+	return api.sign(entry, func() ([]byte, error) {
+		sig, err := schnorr.PartialSign(secp256k1.S256(), hash, key, nonce, pubSum)
+		if err != nil {
+			return nil, err
+		}
+		return sig.Serialize(), nil
+	})
+}
+
+// check find the key of the entry and apply rule, then ask the operator if required
+func (api *SignerApi) check(entry *AuditEntry, rule func() error) (*secp256k1.PrivateKey, error) {
+	key, ok := api.keys[entry.Address]
+	err := ErrUnknownAccount
+	if ok {
+		err = rule()
+	}
+	if err == nil && api.rules.ManualApproval && !api.approver.Approve(describe(entry)) {
+		err = ErrNotApproved
+	}
+	if err != nil {
+		return nil, api.reject(entry, err)
+	}
+	return key, nil
+}
+
+// checkProducer find the key of the entry and check that it may sign msg, the hash to sign is
+// set in the entry and returned. Only rejections are recorded here
+func (api *SignerApi) checkProducer(entry *AuditEntry, msg []byte) (*secp256k1.PrivateKey, []byte, error) {
+	key, ok := api.keys[entry.Address]
+	err := ErrUnknownAccount
+	if ok {
+		err = api.rules.CheckProducer(entry.Address)
+	}
+	if err == nil {
+		entry.Hash, err = api.consensusHash(msg)
+	}
+	if err != nil {
+		if entry.Hash == nil {
+			entry.Hash = msg
+		}
+		return nil, nil, api.reject(entry, err)
+	}
+	return key, entry.Hash, nil
+}
+
+// consensusHash return the hash producers sign for msg, msg must be the sign message of a block
+// header or of a completed block message. Raw hashes are only signed if the rules allow them
+func (api *SignerApi) consensusHash(msg []byte) (common.Bytes, error) {
+	if isBlockSignMessage(msg) || isCompletedBlockSignMessage(msg) {
+		return sha3.Keccak256(msg), nil
+	}
+	if err := api.rules.CheckHash(); err != nil {
+		return nil, err
+	}
+	if len(msg) != 32 {
+		return nil, fmt.Errorf("hash must be 32 bytes")
+	}
+	return msg, nil
+}
+
+// completedBlockSignMessage mirror the completed block message of bft, its multi signature is
+// signed again with the state root in the second round
+type completedBlockSignMessage struct {
+	MultiSignature struct {
+		Sig    secp256k1.Signature
+		Leader int
+		Bitmap []byte
+	}
+	StateRoot []byte
+}
+
+// isBlockSignMessage check that msg is the sign message of a block, which holds only the header
+func isBlockSignMessage(msg []byte) bool {
+	block := &types.Block{}
+	if err := binary.Unmarshal(msg, block); err != nil || block.Header == nil {
+		return false
+	}
+	return bytes.Equal(block.AsSignMessage(), msg)
+}
+
+func isCompletedBlockSignMessage(msg []byte) bool {
+	completed := &completedBlockSignMessage{}
+	if err := binary.Unmarshal(msg, completed); err != nil || len(completed.StateRoot) != len(crypto.Hash{}) {
+		return false
+	}
+	encoded, err := binary.Marshal(completed)
+	return err == nil && bytes.Equal(encoded, msg)
+}
+
+// reject record the rejection of entry and return err
+func (api *SignerApi) reject(entry *AuditEntry, err error) error {
+	entry.Time, entry.Result, entry.Reason = time.Now(), AuditRejected, err.Error()
+	if auditErr := api.audit.Record(entry); auditErr != nil {
+		log.WithField("err", auditErr).Error("write audit log")
+	}
+	return err
+}
+
+// sign make the signature and return it once the entry is recorded
+func (api *SignerApi) sign(entry *AuditEntry, sign func() ([]byte, error)) (common.Bytes, error) {
+	sig, err := sign()
+	if err != nil {
+		return nil, err
+	}
+	entry.Time, entry.Result = time.Now(), AuditSigned
+	if err := api.audit.Record(entry); err != nil {
+		return nil, fmt.Errorf("write audit log: %v", err)
+	}
+	return sig, nil
+}
+
+func describe(entry *AuditEntry) string {
+	request := fmt.Sprintf("%s by %s hash %s", entry.Method, entry.Address.String(), entry.Hash.String())
+	if entry.To != nil {
+		request += " to " + entry.To.String()
+	}
+	if entry.Amount != nil {
+		request += " amount " + entry.Amount.String()
+	}
+	return request
+}
+
+// Listen serve api on the Unix socket at path
+func Listen(path string, api *SignerApi) (net.Listener, *rpc.Server, error) {
+	handler := rpc.NewServer()
+	if err := handler.RegisterName(Namespace, api); err != nil {
+		return nil, nil, err
+	}
+	listener, err := rpc.IpcListen(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	go handler.ServeListener(listener)
+	return listener, handler, nil
+}
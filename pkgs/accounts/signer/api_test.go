@@ -0,0 +1,178 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/binary"
+	"github.com/pkg/errors"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (buffer *bufferCloser) Close() error { return nil }
+
+type answerApprover bool
+
+func (answer answerApprover) Approve(request string) bool { return bool(answer) }
+
+func newTestApi(t *testing.T, rules *Rules, approver Approver) (*SignerApi, crypto.CommonAddress, *bufferCloser) {
+	key, err := secp256k1.GeneratePrivateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := crypto.PubkeyToAddress(key.PubKey())
+	out := &bufferCloser{}
+	api := NewSignerApi([]*types.Node{{Address: &addr, PrivateKey: key}}, rules, approver, &AuditLog{out: out})
+	return api, addr, out
+}
+
+func auditResults(t *testing.T, out *bufferCloser) []string {
+	results := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		entry := &AuditEntry{}
+		if err := json.Unmarshal([]byte(line), entry); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, entry.Method+" "+entry.Result)
+	}
+	return results
+}
+
+func TestSignTransactionRules(t *testing.T) {
+	allowed := crypto.CommonAddress{1}
+	maxAmount := (*common.Big)(big.NewInt(100))
+	api, addr, out := newTestApi(t, &Rules{MaxAmount: maxAmount, AllowedRecipients: []crypto.CommonAddress{allowed}}, nil)
+
+	tx := types.NewTransaction(allowed, big.NewInt(100), big.NewInt(1), big.NewInt(21000), 0)
+	sig, err := api.SignTransaction(addr, tx.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSigner(sig, tx.TxHash().Bytes(), &addr); err != nil {
+		t.Fatal(err)
+	}
+
+	tx = types.NewTransaction(allowed, big.NewInt(101), big.NewInt(1), big.NewInt(21000), 0)
+	if _, err := api.SignTransaction(addr, tx.Data); errors.Cause(err) != ErrRuleRejected {
+		t.Fatalf("expect %v, got %v", ErrRuleRejected, err)
+	}
+	tx = types.NewTransaction(crypto.CommonAddress{2}, big.NewInt(1), big.NewInt(1), big.NewInt(21000), 0)
+	if _, err := api.SignTransaction(addr, tx.Data); errors.Cause(err) != ErrRuleRejected {
+		t.Fatalf("expect %v, got %v", ErrRuleRejected, err)
+	}
+	if _, err := api.SignTransaction(crypto.CommonAddress{3}, tx.Data); err != ErrUnknownAccount {
+		t.Fatalf("expect %v, got %v", ErrUnknownAccount, err)
+	}
+	if _, err := api.SignHash(addr, sha3.Keccak256([]byte("hash"))); errors.Cause(err) != ErrRuleRejected {
+		t.Fatalf("expect %v, got %v", ErrRuleRejected, err)
+	}
+
+	expect := []string{"signTransaction signed", "signTransaction rejected", "signTransaction rejected", "signTransaction rejected", "signHash rejected"}
+	if got := auditResults(t, out); strings.Join(got, ",") != strings.Join(expect, ",") {
+		t.Fatalf("expect audit %v, got %v", expect, got)
+	}
+}
+
+func TestManualApproval(t *testing.T) {
+	hash := sha3.Keccak256([]byte("hash"))
+	api, addr, _ := newTestApi(t, &Rules{AllowRawHash: true, ManualApproval: true}, answerApprover(false))
+	if _, err := api.SignHash(addr, hash); err != ErrNotApproved {
+		t.Fatalf("expect %v, got %v", ErrNotApproved, err)
+	}
+	api.approver = answerApprover(true)
+	if _, err := api.SignHash(addr, hash); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignBlock(t *testing.T) {
+	block := &types.Block{Header: &types.BlockHeader{Height: 1, Timestamp: 1559322808}}
+	msg := block.AsSignMessage()
+	hash := sha3.Keccak256(msg)
+	api, addr, out := newTestApi(t, &Rules{}, nil)
+	if _, err := api.SignBlock(addr, msg); errors.Cause(err) != ErrRuleRejected {
+		t.Fatalf("expect %v, got %v", ErrRuleRejected, err)
+	}
+
+	api.rules.Producers = []crypto.CommonAddress{addr}
+	sigBytes, err := api.SignBlock(addr, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := secp256k1.ParseSignature(sigBytes)
+	if err != nil || !sig.Verify(hash, api.keys[addr].PubKey()) {
+		t.Fatalf("invalid block signature %v", err)
+	}
+
+	// a nonce is random and signs a message once
+	other, _ := secp256k1.GeneratePrivateKey(nil)
+	another, _ := secp256k1.GeneratePrivateKey(nil)
+	if _, err := api.SchnorrPartialSign(addr, msg, other.PubKey()); err != ErrNoNonce {
+		t.Fatalf("expect %v, got %v", ErrNoNonce, err)
+	}
+	nonce, err := api.SchnorrNonce(addr, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := api.SchnorrNonce(addr, msg); err != nil || again.IsEqual(nonce) {
+		t.Fatalf("nonce of a message repeated, err %v", err)
+	}
+	if _, err := api.SchnorrPartialSign(addr, msg, other.PubKey()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.SchnorrPartialSign(addr, msg, another.PubKey()); err != ErrNoNonce {
+		t.Fatalf("expect %v, got %v", ErrNoNonce, err)
+	}
+
+	expect := []string{"signBlock rejected", "signBlock signed", "schnorrPartialSign rejected", "schnorrNonce signed", "schnorrNonce signed", "schnorrPartialSign signed", "schnorrPartialSign rejected"}
+	if got := auditResults(t, out); strings.Join(got, ",") != strings.Join(expect, ",") {
+		t.Fatalf("expect audit %v, got %v", expect, got)
+	}
+}
+
+func TestSignConsensusMessage(t *testing.T) {
+	api, addr, _ := newTestApi(t, &Rules{}, nil)
+	api.rules.Producers = []crypto.CommonAddress{addr}
+
+	completed := &completedBlockSignMessage{StateRoot: sha3.Keccak256([]byte("state"))}
+	completed.MultiSignature.Leader = 1
+	completed.MultiSignature.Bitmap = []byte{1, 0, 1}
+	msg, err := binary.Marshal(completed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.SchnorrNonce(addr, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	// raw hashes and unknown messages are only signed if the rules allow raw hashes
+	hash := sha3.Keccak256([]byte("hash"))
+	if _, err := api.SignBlock(addr, hash); errors.Cause(err) != ErrRuleRejected {
+		t.Fatalf("expect %v, got %v", ErrRuleRejected, err)
+	}
+	if _, err := api.SchnorrNonce(addr, append(msg, 0)); errors.Cause(err) != ErrRuleRejected {
+		t.Fatalf("expect %v, got %v", ErrRuleRejected, err)
+	}
+	api.rules.AllowRawHash = true
+	sigBytes, err := api.SignBlock(addr, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig, err := secp256k1.ParseSignature(sigBytes); err != nil || !sig.Verify(hash, api.keys[addr].PubKey()) {
+		t.Fatalf("invalid hash signature %v", err)
+	}
+	if _, err := api.SignBlock(addr, hash[:31]); err == nil {
+		t.Fatal("expect short hash to be rejected")
+	}
+}
@@ -0,0 +1,98 @@
+package signer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+const (
+	AuditSigned   = "signed"
+	AuditRejected = "rejected"
+)
+
+// AuditEntry is a line of the audit log, one is written for every signing request
+type AuditEntry struct {
+	Time    time.Time             `json:"time"`
+	Method  string                `json:"method"`
+	Address crypto.CommonAddress  `json:"address"`
+	Hash    common.Bytes          `json:"hash,omitempty"`
+	To      *crypto.CommonAddress `json:"to,omitempty"`
+	Amount  *common.Big           `json:"amount,omitempty"`
+	Result  string                `json:"result"`
+	Reason  string                `json:"reason,omitempty"`
+}
+
+// AuditLog append entries as json lines to a file, a signature is only returned once its entry
+// is written
+type AuditLog struct {
+	lock sync.Mutex
+	out  io.WriteCloser
+}
+
+// OpenAuditLog open the audit log at path for appending
+func OpenAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{out: file}, nil
+}
+
+// Record write entry, the entry is synced to disk when the output is a file
+func (audit *AuditLog) Record(entry *AuditEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	audit.lock.Lock()
+	defer audit.lock.Unlock()
+	if _, err := audit.out.Write(append(content, '\n')); err != nil {
+		return err
+	}
+	if file, ok := audit.out.(*os.File); ok {
+		return file.Sync()
+	}
+	return nil
+}
+
+func (audit *AuditLog) Close() error {
+	return audit.out.Close()
+}
+
+// Approver ask the operator whether a request may be signed
+type Approver interface {
+	Approve(request string) bool
+}
+
+// NewTerminalApprover return an Approver prompting on out and reading the answer from in,
+// requests are prompted one at a time
+func NewTerminalApprover(in io.Reader, out io.Writer) Approver {
+	return &terminalApprover{in: bufio.NewReader(in), out: out}
+}
+
+type terminalApprover struct {
+	lock sync.Mutex
+	in   *bufio.Reader
+	out  io.Writer
+}
+
+func (approver *terminalApprover) Approve(request string) bool {
+	approver.lock.Lock()
+	defer approver.lock.Unlock()
+	fmt.Fprintf(approver.out, "%s\nApprove? [y/N] ", request)
+	answer, err := approver.in.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,141 @@
+package signer
+
+import (
+	"context"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/rpc"
+)
+
+const (
+	// blocks must be signed within a consensus round, transactions and hashes wait for the
+	// operator as long as it takes
+	blockSignTimeout = 3 * time.Second
+)
+
+// Client forward signing requests to a signer daemon
+type Client struct {
+	client *rpc.Client
+}
+
+// Dial connect to the signer daemon listening on the Unix socket at path
+func Dial(path string) (*Client, error) {
+	client, err := rpc.Dial(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{client: client}, nil
+}
+
+func (client *Client) Close() {
+	client.client.Close()
+}
+
+// Accounts list the addresses whose keys are held by the signer
+func (client *Client) Accounts() ([]crypto.CommonAddress, error) {
+	var addrs []crypto.CommonAddress
+	err := client.client.Call(&addrs, Namespace+"_accounts")
+	return addrs, err
+}
+
+// SignTransaction sign tx with the key of addr, the signature is checked to be made by addr
+// over the hash of tx
+func (client *Client) SignTransaction(addr *crypto.CommonAddress, tx *types.Transaction) ([]byte, error) {
+	var sig common.Bytes
+	if err := client.client.Call(&sig, Namespace+"_signTransaction", addr, tx.Data); err != nil {
+		return nil, err
+	}
+	if err := checkSigner(sig, tx.TxHash().Bytes(), addr); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignHash sign a raw hash with the key of addr
+func (client *Client) SignHash(addr *crypto.CommonAddress, hash []byte) ([]byte, error) {
+	var sig common.Bytes
+	if err := client.client.Call(&sig, Namespace+"_signHash", addr, common.Bytes(hash)); err != nil {
+		return nil, err
+	}
+	if err := checkSigner(sig, hash, addr); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// KeySigner return the KeySigner of the producer key pubkey held by the signer
+func (client *Client) KeySigner(pubkey *secp256k1.PublicKey) (KeySigner, error) {
+	addr := crypto.PubkeyToAddress(pubkey)
+	remotePubkey := &secp256k1.PublicKey{}
+	if err := client.client.Call(remotePubkey, Namespace+"_pubKey", addr); err != nil {
+		return nil, err
+	}
+	if !remotePubkey.IsEqual(pubkey) {
+		return nil, ErrSignerMismatch
+	}
+	return &remoteKeySigner{client: client.client, addr: addr, pubkey: pubkey}, nil
+}
+
+func checkSigner(sig, hash []byte, addr *crypto.CommonAddress) error {
+	pubkey, _, err := secp256k1.RecoverCompact(sig, hash)
+	if err != nil {
+		return err
+	}
+	if crypto.PubkeyToAddress(pubkey) != *addr {
+		return ErrSignerMismatch
+	}
+	return nil
+}
+
+type remoteKeySigner struct {
+	client *rpc.Client
+	addr   crypto.CommonAddress
+	pubkey *secp256k1.PublicKey
+}
+
+func (signer *remoteKeySigner) PubKey() *secp256k1.PublicKey {
+	return signer.pubkey
+}
+
+func (signer *remoteKeySigner) Sign(msg []byte) (*secp256k1.Signature, error) {
+	var sigBytes common.Bytes
+	if err := signer.call(&sigBytes, "_signBlock", signer.addr, common.Bytes(msg)); err != nil {
+		return nil, err
+	}
+	sig, err := secp256k1.ParseSignature(sigBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !sig.Verify(sha3.Keccak256(msg), signer.pubkey) {
+		return nil, ErrSignerMismatch
+	}
+	return sig, nil
+}
+
+func (signer *remoteKeySigner) SchnorrNonce(msg []byte) (*secp256k1.PublicKey, error) {
+	nonce := &secp256k1.PublicKey{}
+	if err := signer.call(nonce, "_schnorrNonce", signer.addr, common.Bytes(msg)); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+func (signer *remoteKeySigner) SchnorrPartialSign(msg []byte, pubSum *secp256k1.PublicKey) (*schnorr.Signature, error) {
+	var sig common.Bytes
+	if err := signer.call(&sig, "_schnorrPartialSign", signer.addr, common.Bytes(msg), pubSum); err != nil {
+		return nil, err
+	}
+	return schnorr.ParseSignature(sig)
+}
+
+func (signer *remoteKeySigner) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), blockSignTimeout)
+	defer cancel()
+	return signer.client.CallContext(ctx, result, Namespace+method, args...)
+}
@@ -0,0 +1,11 @@
+package signer
+
+import "errors"
+
+var (
+	ErrRuleRejected   = errors.New("rejected by signer rules")
+	ErrNotApproved    = errors.New("rejected by operator")
+	ErrUnknownAccount = errors.New("account is not in signer")
+	ErrNoNonce        = errors.New("no nonce committed to msg, or it is already used")
+	ErrSignerMismatch = errors.New("signature is not made by the requested account")
+)
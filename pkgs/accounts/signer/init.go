@@ -0,0 +1,13 @@
+package signer
+
+import (
+	dlog "github.com/drep-project/DREP-Chain/pkgs/log"
+)
+
+const (
+	MODULENAME = "signer"
+)
+
+var (
+	log = dlog.EnsureLogger(MODULENAME)
+)
@@ -0,0 +1,83 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/pkg/errors"
+)
+
+// Rules decide which requests the signer daemon answers, a request breaking any rule is rejected
+type Rules struct {
+	// MaxAmount is the largest amount of a single transaction, no limit if nil
+	MaxAmount *common.Big `json:"maxAmount,omitempty"`
+	// AllowedRecipients are the only addresses transfers and calls may be sent to, any if empty
+	AllowedRecipients []crypto.CommonAddress `json:"allowedRecipients,omitempty"`
+	// AllowRawHash allow signing hashes whose content the signer can not check, also by producers
+	// instead of block and consensus messages
+	AllowRawHash bool `json:"allowRawHash"`
+	// Producers are the accounts allowed to sign blocks
+	Producers []crypto.CommonAddress `json:"producers,omitempty"`
+	// ManualApproval ask the operator to approve every transaction and hash, blocks are never
+	// prompted as consensus can not wait for the operator
+	ManualApproval bool `json:"manualApproval"`
+}
+
+// LoadRules read rules from a json file
+func LoadRules(path string) (*Rules, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules := &Rules{}
+	if err := json.Unmarshal(content, rules); err != nil {
+		return nil, fmt.Errorf("invalid rules %s: %v", path, err)
+	}
+	return rules, nil
+}
+
+// CheckTransaction check the amount and the recipient of tx
+func (rules *Rules) CheckTransaction(tx *types.TransactionData) error {
+	if rules.MaxAmount != nil {
+		amount := big.Int(tx.Amount)
+		if amount.Cmp(rules.MaxAmount.ToInt()) > 0 {
+			return errors.Wrapf(ErrRuleRejected, "amount %s exceeds %s", amount.String(), rules.MaxAmount.ToInt().String())
+		}
+	}
+	if len(rules.AllowedRecipients) > 0 && (tx.Type == types.TransferType || tx.Type == types.CallContractType) {
+		if !containsAddress(rules.AllowedRecipients, tx.To) {
+			return errors.Wrapf(ErrRuleRejected, "recipient %s is not allowed", tx.To.String())
+		}
+	}
+	return nil
+}
+
+// CheckHash check that raw hashes may be signed
+func (rules *Rules) CheckHash() error {
+	if !rules.AllowRawHash {
+		return errors.Wrap(ErrRuleRejected, "raw hash signing is disabled")
+	}
+	return nil
+}
+
+// CheckProducer check that addr may sign blocks
+func (rules *Rules) CheckProducer(addr crypto.CommonAddress) error {
+	if !containsAddress(rules.Producers, addr) {
+		return errors.Wrapf(ErrRuleRejected, "%s is not a producer", addr.String())
+	}
+	return nil
+}
+
+func containsAddress(addrs []crypto.CommonAddress, addr crypto.CommonAddress) bool {
+	for _, allowed := range addrs {
+		if allowed == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+// Package signer keeps private keys out of the node process. A signer daemon holds the keys of a
+// keystore, checks every request against its rules, records it in an audit log and answers over a
+// Unix socket with the JSON-RPC methods of the "signer" namespace. The node uses Client to forward
+// the signing of transactions and blocks to it.
+package signer
+
+import (
+	"crypto/rand"
+	"sync"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+)
+
+// KeySigner signs blocks with the key of a producer, the key itself may live in a signer daemon.
+// Messages are the sign messages of blocks or consensus messages, the keccak256 hash of a message
+// is signed so that a signer daemon can check what it signs.
+type KeySigner interface {
+	// PubKey return the public key of the signing key
+	PubKey() *secp256k1.PublicKey
+	// Sign sign the hash of msg with ecdsa
+	Sign(msg []byte) (*secp256k1.Signature, error)
+	// SchnorrNonce return the public nonce committed to a multi signature of msg
+	SchnorrNonce(msg []byte) (*secp256k1.PublicKey, error)
+	// SchnorrPartialSign sign msg as part of a multi signature with the nonce committed by
+	// SchnorrNonce, pubSum is the sum of the public nonces of the other signers. The nonce is only
+	// used once.
+	SchnorrPartialSign(msg []byte, pubSum *secp256k1.PublicKey) (*schnorr.Signature, error)
+}
+
+// NewLocalKeySigner return a KeySigner of a key loaded in the process
+func NewLocalKeySigner(key *secp256k1.PrivateKey) KeySigner {
+	return &localKeySigner{key: key, nonces: newNonceStore()}
+}
+
+type localKeySigner struct {
+	key    *secp256k1.PrivateKey
+	nonces *nonceStore
+}
+
+func (signer *localKeySigner) PubKey() *secp256k1.PublicKey {
+	return signer.key.PubKey()
+}
+
+func (signer *localKeySigner) Sign(msg []byte) (*secp256k1.Signature, error) {
+	return signer.key.Sign(sha3.Keccak256(msg))
+}
+
+func (signer *localKeySigner) SchnorrNonce(msg []byte) (*secp256k1.PublicKey, error) {
+	hash := sha3.Keccak256(msg)
+	return signer.nonces.generate(common.Encode(hash), signer.key, hash)
+}
+
+func (signer *localKeySigner) SchnorrPartialSign(msg []byte, pubSum *secp256k1.PublicKey) (*schnorr.Signature, error) {
+	hash := sha3.Keccak256(msg)
+	nonce := signer.nonces.take(common.Encode(hash))
+	if nonce == nil {
+		return nil, ErrNoNonce
+	}
+	return schnorr.PartialSign(secp256k1.S256(), hash, signer.key, nonce, pubSum)
+}
+
+// nonceStore keeps the secret nonces committed to multi signatures until the partial signature.
+// Nonces are random and used once, a second partial signature of a message with the same nonce
+// and another nonce sum would reveal the key.
+type nonceStore struct {
+	lock   sync.Mutex
+	nonces map[string]*secp256k1.PrivateKey
+	order  []string
+}
+
+func newNonceStore() *nonceStore {
+	return &nonceStore{nonces: make(map[string]*secp256k1.PrivateKey)}
+}
+
+// generate make a random nonce for hash signed with key and keep it under id, replacing the
+// nonce of a previous commitment. The oldest nonces are dropped past maxPendingNonces, their
+// rounds can no longer be signed.
+func (store *nonceStore) generate(id string, key *secp256k1.PrivateKey, hash []byte) (*secp256k1.PublicKey, error) {
+	extra := make([]byte, 32)
+	if _, err := rand.Read(extra); err != nil {
+		return nil, err
+	}
+	nonce, pubNonce, err := schnorr.GenerateNoncePair(secp256k1.S256(), hash, key, extra, schnorr.Sha256VersionStringRFC6979)
+	if err != nil {
+		return nil, err
+	}
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if _, ok := store.nonces[id]; !ok {
+		if len(store.order) >= maxPendingNonces {
+			delete(store.nonces, store.order[0])
+			store.order = store.order[1:]
+		}
+		store.order = append(store.order, id)
+	}
+	store.nonces[id] = nonce
+	return pubNonce, nil
+}
+
+// take remove the nonce kept under id and return it, nil if there is none
+func (store *nonceStore) take(id string) *secp256k1.PrivateKey {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	nonce, ok := store.nonces[id]
+	if !ok {
+		return nil
+	}
+	delete(store.nonces, id)
+	for i, pending := range store.order {
+		if pending == id {
+			store.order = append(store.order[:i], store.order[i+1:]...)
+			break
+		}
+	}
+	return nonce
+}
@@ -5,4 +5,9 @@ type Config struct {
 	Type        string `json:"type,omitempty"`
 	KeyStoreDir string `json:"keyStoreDir,omitempty"`
 	Password    string `json:"password,omitempty"`
+	// Signer is the Unix socket of the signer daemon used when Type is "external"
+	Signer string `json:"signer,omitempty"`
+	// WatchFile is the json file of watch-only addresses of the wallet, beside the keystore by
+	// default. They are only kept in memory if empty
+	WatchFile string `json:"watchFile,omitempty"`
 }
@@ -0,0 +1,50 @@
+package chain_indexer
+
+/*
+name: 链索引接口
+usage: 查看和重建按section处理区块的索引
+prefix: indexer
+*/
+type ChainIndexerApi struct {
+	chainIndexer *ChainIndexerService
+}
+
+/*
+ name: getIndexers
+ usage: 查询所有已注册索引的处理进度
+ params:
+	无
+ return: 索引进度列表，Sections为已处理的section数目，Known为链上已确认的section数目，Head为最后处理的section的最后一个区块hash，Stalled为处理失败等待新区块
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"indexer_getIndexers","params":[], "id": 3}' -H "Content-Type:application/json"
+ response:
+{
+  "jsonrpc": "2.0",
+  "id": 3,
+  "result": [
+    {
+      "Name": "bloombits",
+      "Sections": 12,
+      "Known": 12,
+      "Head": "0x8216c5785ac562ff41e2dcfdf5785ac562ff41e2dcfdf829c5a142f1fccd7d",
+      "Stalled": false
+    }
+  ]
+}
+*/
+func (api *ChainIndexerApi) GetIndexers() []*IndexerStatus {
+	return api.chainIndexer.Status()
+}
+
+/*
+ name: rebuild
+ usage: 删除指定索引的数据，从第一个section开始重新处理
+ params:
+	1. 索引名称
+ return: 无
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"indexer_rebuild","params":["bloombits"], "id": 3}' -H "Content-Type:application/json"
+ response:
+   {"jsonrpc":"2.0","id":3,"result":null}
+*/
+func (api *ChainIndexerApi) Rebuild(name string) error {
+	return api.chainIndexer.Rebuild(name)
+}
@@ -0,0 +1,46 @@
+package chain_indexer
+
+import (
+	"context"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+// ChainIndexerBackend processes the chain section by section into an index. Every backend
+// registered to the chain indexer is fed the same confirmed sections and tracks its own
+// progress, so a new or rebuilt backend catches up without touching the others.
+type ChainIndexerBackend interface {
+	// Reset initiates the processing of a new chain section, discarding any partially
+	// completed one.
+	Reset(ctx context.Context, section uint64, prevHead crypto.Hash) error
+
+	// Process crunches through the next header of the section.
+	Process(ctx context.Context, header *types.BlockHeader) error
+
+	// Commit finalizes the section and stores it into the database.
+	Commit() error
+
+	// Rollback removes the index of every section from section on, they have been reorganized
+	// out of the canonical chain or the index is rebuilt.
+	Rollback(section uint64) error
+}
+
+// indexer is a registered backend and its progress
+type indexer struct {
+	name    string
+	backend ChainIndexerBackend
+	prefix  string // key prefix of the progress of the indexer
+
+	storedSections uint64 // Number of sections successfully indexed into the database
+	stalled        bool   // processing failed, not retried until new sections are known
+}
+
+// IndexerStatus is the progress of an indexer
+type IndexerStatus struct {
+	Name     string
+	Sections uint64      // number of sections indexed
+	Known    uint64      // number of confirmed sections of the chain
+	Head     crypto.Hash // last block of the last indexed section
+	Stalled  bool        // processing of the next section failed
+}
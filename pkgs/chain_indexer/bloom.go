@@ -0,0 +1,90 @@
+package chain_indexer
+
+import (
+	"context"
+
+	"github.com/drep-project/DREP-Chain/common/bitutil"
+	"github.com/drep-project/DREP-Chain/common/bloombits"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/binary"
+)
+
+const (
+	// BloomIndexerName is the name of the bloombits indexer used by log filters
+	BloomIndexerName = "bloombits"
+)
+
+var (
+	bloomPrefix = []byte("B")
+)
+
+// bloomIndexer rotates the blooms of the headers of a section into bloom bits
+type bloomIndexer struct {
+	db          *database.DatabaseService
+	sectionSize uint64
+
+	gen     *bloombits.Generator // generator to rotate the bloom bits crating the bloom index
+	section uint64               // Section is the section number being processed currently
+	head    crypto.Hash          // Head is the hash of the last header processed
+}
+
+// 启动新的bloombits索引部分。
+func (b *bloomIndexer) Reset(ctx context.Context, section uint64, lastSectionHead crypto.Hash) error {
+	gen, err := bloombits.NewGenerator(uint(b.sectionSize))
+	b.gen, b.section, b.head = gen, section, crypto.Hash{}
+	return err
+}
+
+// 将新区块头的bloom添加到索引。
+func (b *bloomIndexer) Process(ctx context.Context, header *types.BlockHeader) error {
+	b.gen.AddBloom(uint(header.Height-b.section*b.sectionSize), header.Bloom)
+	b.head = *header.Hash()
+	return nil
+}
+
+// 完成bloom部分和把它写进数据库。
+func (b *bloomIndexer) Commit() error {
+	batch := b.db.NewBatch()
+	for i := 0; i < types.BloomBitLength; i++ {
+		bits, err := b.gen.Bitset(uint(i))
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(bloomBitsKey(uint(i), b.section, b.head), bitutil.CompressBytes(bits)); err != nil {
+			log.WithField("err", err).Fatal("Failed to store bloom bits")
+		}
+	}
+	return batch.Write()
+}
+
+// Rollback keeps the bloom bits of reorganized sections, they are keyed by the head of their
+// section and no longer read once the head is not canonical
+func (b *bloomIndexer) Rollback(section uint64) error {
+	return nil
+}
+
+// bloomBitsKey = bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash
+func bloomBitsKey(bit uint, section uint64, hash crypto.Hash) []byte {
+	key := append(append(bloomPrefix, make([]byte, 10)...), hash.Bytes()...)
+
+	binary.BigEndian.PutUint16(key[1:], uint16(bit))
+	binary.BigEndian.PutUint64(key[3:], section)
+
+	return key
+}
+
+// ReadBloomBits retrieves the compressed bloom bit vector belonging to the given
+// section and bit index from the.
+func (chainIndexer *ChainIndexerService) ReadBloomBits(bit uint, section uint64, head crypto.Hash) ([]byte, error) {
+	return chainIndexer.DatabaseService.Get(bloomBitsKey(bit, section, head))
+}
+
+func (chainIndexer *ChainIndexerService) BloomStatus() (uint64, uint64) {
+	chainIndexer.lock.Lock()
+	defer chainIndexer.lock.Unlock()
+
+	chainIndexer.verifyLastHead(chainIndexer.bloom)
+	return chainIndexer.Config.SectionSize, chainIndexer.bloom.storedSections
+}
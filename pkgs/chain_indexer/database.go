@@ -8,12 +8,20 @@ import (
 
 var (
 	indexerPrefix = "ci_"
-	bloomPrefix   = []byte("B")
 )
 
-func (chainIndexer *ChainIndexerService) getStoredSections() uint64 {
+// indexerKeyPrefix return the key prefix of the progress of an indexer, the bloombits indexer
+// keeps the keys used before other indexers could be registered
+func indexerKeyPrefix(name string) string {
+	if name == BloomIndexerName {
+		return indexerPrefix
+	}
+	return indexerPrefix + name + "_"
+}
+
+func (chainIndexer *ChainIndexerService) getStoredSections(idx *indexer) uint64 {
 	var storedSections uint64
-	value, err := chainIndexer.DatabaseService.Get([]byte(indexerPrefix + "count"))
+	value, err := chainIndexer.DatabaseService.Get([]byte(idx.prefix + "count"))
 	if err != nil {
 		return storedSections
 	}
@@ -22,51 +30,54 @@ func (chainIndexer *ChainIndexerService) getStoredSections() uint64 {
 	return storedSections
 }
 
-func (chainIndexer *ChainIndexerService) setStoredSections(storedSections uint64) error {
+func (chainIndexer *ChainIndexerService) setStoredSections(idx *indexer, storedSections uint64) error {
 	value, err := binary.Marshal(storedSections)
 	if err != nil {
 		return err
 	}
-	return chainIndexer.DatabaseService.Put([]byte(indexerPrefix+"count"), value)
+	return chainIndexer.DatabaseService.Put([]byte(idx.prefix+"count"), value)
 }
 
 // setValidStoredSections writes the number of valid sections to the index database
-func (chainIndexer *ChainIndexerService) setValidStoredSections(sections uint64) {
+func (chainIndexer *ChainIndexerService) setValidStoredSections(idx *indexer, sections uint64) {
 	// Set the current number of valid sections in the database
-	chainIndexer.setStoredSections(sections)
+	chainIndexer.setStoredSections(idx, sections)
 
+	// Remove the index of reorged sections
+	if idx.storedSections > sections {
+		if err := idx.backend.Rollback(sections); err != nil {
+			log.WithField("indexer", idx.name).WithField("section", sections).WithField("err", err).Error("Rollback index failed")
+		}
+	}
 	// Remove any reorged sections, caching the valids in the mean time
-	for chainIndexer.storedSections > sections {
-		chainIndexer.storedSections--
-		chainIndexer.deleteSectionHead(chainIndexer.storedSections)
+	for idx.storedSections > sections {
+		idx.storedSections--
+		chainIndexer.deleteSectionHead(idx, idx.storedSections)
 	}
-	chainIndexer.storedSections = sections // needed if new > old
+	idx.storedSections = sections // needed if new > old
 }
 
-// Sections returns the number of processed sections maintained by the indexer
+// status returns the number of processed sections maintained by the indexer
 // and also the information about the last header indexed for potential canonical
 // verifications.
-func (chainIndexer *ChainIndexerService) Sections() (uint64, uint64, crypto.Hash) {
-	chainIndexer.lock.Lock()
-	defer chainIndexer.lock.Unlock()
-
-	chainIndexer.verifyLastHead()
-	return chainIndexer.storedSections, chainIndexer.storedSections*chainIndexer.Config.SectionSize - 1, chainIndexer.getSectionHead(chainIndexer.storedSections - 1)
-}
-
-func (chainIndexer *ChainIndexerService) BloomStatus() (uint64, uint64) {
-	sections, _, _ := chainIndexer.Sections()
-	return chainIndexer.Config.SectionSize, sections
+func (chainIndexer *ChainIndexerService) status(idx *indexer) *IndexerStatus {
+	chainIndexer.verifyLastHead(idx)
+	status := &IndexerStatus{
+		Name:     idx.name,
+		Sections: idx.storedSections,
+		Known:    chainIndexer.knownSections,
+		Stalled:  idx.stalled,
+	}
+	if idx.storedSections > 0 {
+		status.Head = chainIndexer.getSectionHead(idx, idx.storedSections-1)
+	}
+	return status
 }
 
 // GetSectionHead 从数据库中获取已处理section的最后一个块哈希
-func (chainIndexer *ChainIndexerService) getSectionHead(section uint64) crypto.Hash {
-	var data [8]byte
-	bin.BigEndian.PutUint64(data[:], section)
-	key := append([]byte(indexerPrefix+"shead"), data[:]...)
-
+func (chainIndexer *ChainIndexerService) getSectionHead(idx *indexer, section uint64) crypto.Hash {
 	var sectionHead crypto.Hash
-	value, _ := chainIndexer.DatabaseService.Get(key)
+	value, _ := chainIndexer.DatabaseService.Get(sectionHeadKey(idx, section))
 	if len(value) == 0 {
 		return sectionHead
 	}
@@ -75,11 +86,8 @@ func (chainIndexer *ChainIndexerService) getSectionHead(section uint64) crypto.H
 }
 
 // SetSectionHead 将已处理section的最后一个块哈希写入数据库
-func (chainIndexer *ChainIndexerService) setSectionHead(section uint64, hash crypto.Hash) error {
-	var data [8]byte
-	bin.BigEndian.PutUint64(data[:], section)
-	key := append([]byte(indexerPrefix+"shead"), data[:]...)
-	err := chainIndexer.DatabaseService.Put(key, hash.Bytes())
+func (chainIndexer *ChainIndexerService) setSectionHead(idx *indexer, section uint64, hash crypto.Hash) error {
+	err := chainIndexer.DatabaseService.Put(sectionHeadKey(idx, section), hash.Bytes())
 	if err != nil {
 		return err
 	}
@@ -87,26 +95,12 @@ func (chainIndexer *ChainIndexerService) setSectionHead(section uint64, hash cry
 }
 
 // DeleteSectionHead 将已处理section的最后一个块哈希从数据库中删除
-func (chainIndexer *ChainIndexerService) deleteSectionHead(section uint64) error {
-	var data [8]byte
-	binary.BigEndian.PutUint64(data[:], section)
-	key := append([]byte(indexerPrefix+"shead"), data[:]...)
-
-	return chainIndexer.DatabaseService.Delete(key)
+func (chainIndexer *ChainIndexerService) deleteSectionHead(idx *indexer, section uint64) error {
+	return chainIndexer.DatabaseService.Delete(sectionHeadKey(idx, section))
 }
 
-//bloomBitsKey = bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash
-func bloomBitsKey(bit uint, section uint64, hash crypto.Hash) []byte {
-	key := append(append(bloomPrefix, make([]byte, 10)...), hash.Bytes()...)
-
-	binary.BigEndian.PutUint16(key[1:], uint16(bit))
-	binary.BigEndian.PutUint64(key[3:], section)
-
-	return key
-}
-
-// ReadBloomBits retrieves the compressed bloom bit vector belonging to the given
-// section and bit index from the.
-func (chainIndexer *ChainIndexerService) ReadBloomBits(bit uint, section uint64, head crypto.Hash) ([]byte, error) {
-	return chainIndexer.DatabaseService.Get(bloomBitsKey(bit, section, head))
+func sectionHeadKey(idx *indexer, section uint64) []byte {
+	var data [8]byte
+	bin.BigEndian.PutUint64(data[:], section)
+	return append([]byte(idx.prefix+"shead"), data[:]...)
 }
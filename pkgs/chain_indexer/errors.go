@@ -0,0 +1,11 @@
+package chain_indexer
+
+import (
+	"errors"
+)
+
+var (
+	ErrIndexerDisabled = errors.New("chain indexer not enabled")
+	ErrIndexerExists   = errors.New("indexer already registered")
+	ErrIndexerNotFound = errors.New("indexer not found")
+)
@@ -7,12 +7,11 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/pkg/errors"
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/drep-project/DREP-Chain/app"
 	"github.com/drep-project/DREP-Chain/chain"
-	"github.com/drep-project/DREP-Chain/common/bitutil"
-	"github.com/drep-project/DREP-Chain/common/bloombits"
 	"github.com/drep-project/DREP-Chain/common/event"
 	"github.com/drep-project/DREP-Chain/crypto"
 	"github.com/drep-project/DREP-Chain/database"
@@ -33,6 +32,8 @@ type ChainIndexerServiceInterface interface {
 	BloomStatus() (uint64, uint64)
 	ReadBloomBits(bit uint, section uint64, head crypto.Hash) ([]byte, error)
 	GetConfig() *ChainIndexerConfig
+	RegisterIndexer(name string, backend ChainIndexerBackend) error
+	Status() []*IndexerStatus
 }
 
 var _ ChainIndexerServiceInterface = &ChainIndexerService{}
@@ -42,8 +43,11 @@ type ChainIndexerService struct {
 	ChainService    chain.ChainServiceInterface `service:"chain"`
 	Config          *ChainIndexerConfig
 
-	storedSections uint64 // Number of sections successfully indexed into the database
-	knownSections  uint64 // Number of sections known to be complete (block wise)
+	apis []app.API
+
+	indexers      []*indexer // registered indexers, in the order of registration
+	bloom         *indexer   // bloombits indexer used by log filters
+	knownSections uint64     // Number of sections known to be complete (block wise)
 
 	active    uint32          // Flag whether the event loop was started
 	update    chan struct{}   // Notification channel that headers should be processed
@@ -52,11 +56,6 @@ type ChainIndexerService struct {
 	ctxCancel func()
 
 	lock sync.RWMutex
-
-	// bloomIndexer
-	gen     *bloombits.Generator // generator to rotate the bloom bits crating the bloom index
-	section uint64               // Section is the section number being processed currently
-	head    crypto.Hash          // Head is the hash of the last header processed
 }
 
 func (chainIndexer *ChainIndexerService) Name() string {
@@ -64,18 +63,20 @@ func (chainIndexer *ChainIndexerService) Name() string {
 }
 
 func (chainIndexer *ChainIndexerService) Api() []app.API {
-	return []app.API{}
+	return chainIndexer.apis
 }
 
 func (chainIndexer *ChainIndexerService) CommandFlags() ([]cli.Command, []cli.Flag) {
 	return nil, []cli.Flag{EnableChainIndexerFlag}
 }
 
-func (chainIndexer *ChainIndexerService) Init(executeContext *app.ExecuteContext) error {
-	// initialize module config
+func (chainIndexer *ChainIndexerService) ApplyFlags(executeContext *app.ExecuteContext) {
 	if executeContext.Cli.GlobalIsSet(EnableChainIndexerFlag.Name) {
 		chainIndexer.Config.Enable = executeContext.Cli.GlobalBool(EnableChainIndexerFlag.Name)
 	}
+}
+
+func (chainIndexer *ChainIndexerService) Init(executeContext *app.ExecuteContext) error {
 	if !chainIndexer.Config.Enable {
 		return nil
 	}
@@ -84,14 +85,118 @@ func (chainIndexer *ChainIndexerService) Init(executeContext *app.ExecuteContext
 	chainIndexer.update = make(chan struct{}, 1)
 	chainIndexer.quit = make(chan chan error)
 	chainIndexer.ctx, chainIndexer.ctxCancel = context.WithCancel(context.Background())
-	chainIndexer.storedSections = chainIndexer.getStoredSections()
+
+	bloom := &bloomIndexer{db: chainIndexer.DatabaseService, sectionSize: chainIndexer.Config.SectionSize}
+	if err := chainIndexer.RegisterIndexer(BloomIndexerName, bloom); err != nil {
+		return err
+	}
+	chainIndexer.bloom = chainIndexer.indexers[0]
+
+	chainIndexer.apis = []app.API{
+		app.API{
+			Namespace: "indexer",
+			Version:   "1.0",
+			Service: &ChainIndexerApi{
+				chainIndexer: chainIndexer,
+			},
+			// rebuild drops the stored sections and reprocesses the whole chain
+			Public: false,
+		},
+	}
 
 	go chainIndexer.updateLoop()
 
 	return nil
 }
 
+// RegisterIndexer add a backend fed with every confirmed section of the chain, services register
+// their indexers in Init. The progress of the indexer is kept under its name, it catches up
+// with the chain from the last section it has indexed.
+func (chainIndexer *ChainIndexerService) RegisterIndexer(name string, backend ChainIndexerBackend) error {
+	if !chainIndexer.Config.Enable {
+		return ErrIndexerDisabled
+	}
+	chainIndexer.lock.Lock()
+	defer chainIndexer.lock.Unlock()
+
+	if chainIndexer.lookupIndexer(name) != nil {
+		return errors.Wrapf(ErrIndexerExists, "register %s", name)
+	}
+	idx := &indexer{
+		name:    name,
+		backend: backend,
+		prefix:  indexerKeyPrefix(name),
+	}
+	idx.storedSections = chainIndexer.getStoredSections(idx)
+	chainIndexer.indexers = append(chainIndexer.indexers, idx)
+
+	chainIndexer.scheduleUpdate()
+	return nil
+}
+
+// Status return the progress of every registered indexer
+func (chainIndexer *ChainIndexerService) Status() []*IndexerStatus {
+	chainIndexer.lock.Lock()
+	defer chainIndexer.lock.Unlock()
+
+	status := make([]*IndexerStatus, 0, len(chainIndexer.indexers))
+	for _, idx := range chainIndexer.indexers {
+		status = append(status, chainIndexer.status(idx))
+	}
+	return status
+}
+
+// Rebuild drop the index of an indexer and process the chain again from the first section
+func (chainIndexer *ChainIndexerService) Rebuild(name string) error {
+	chainIndexer.lock.Lock()
+	defer chainIndexer.lock.Unlock()
+
+	idx := chainIndexer.lookupIndexer(name)
+	if idx == nil {
+		return errors.Wrapf(ErrIndexerNotFound, "rebuild %s", name)
+	}
+	log.WithField("indexer", name).Info("Rebuilding chain index")
+	chainIndexer.setValidStoredSections(idx, 0)
+	idx.stalled = false
+	chainIndexer.scheduleUpdate()
+	return nil
+}
+
+func (chainIndexer *ChainIndexerService) lookupIndexer(name string) *indexer {
+	for _, idx := range chainIndexer.indexers {
+		if idx.name == name {
+			return idx
+		}
+	}
+	return nil
+}
+
+// nextIndexer return the indexer furthest behind the known sections, nil if all have caught up
+func (chainIndexer *ChainIndexerService) nextIndexer() *indexer {
+	var next *indexer
+	for _, idx := range chainIndexer.indexers {
+		if idx.stalled || idx.storedSections >= chainIndexer.knownSections {
+			continue
+		}
+		if next == nil || idx.storedSections < next.storedSections {
+			next = idx
+		}
+	}
+	return next
+}
+
+// scheduleUpdate notify the update loop that sections are to be processed
+func (chainIndexer *ChainIndexerService) scheduleUpdate() {
+	select {
+	case chainIndexer.update <- struct{}{}:
+	default:
+	}
+}
+
 func (chainIndexer *ChainIndexerService) Start(executeContext *app.ExecuteContext) error {
+	if !chainIndexer.Config.Enable {
+		return nil
+	}
 	events := make(chan *types.ChainEvent, 10)
 	sub := chainIndexer.ChainService.NewBlockFeed().Subscribe(events)
 
@@ -101,6 +206,9 @@ func (chainIndexer *ChainIndexerService) Start(executeContext *app.ExecuteContex
 }
 
 func (chainIndexer *ChainIndexerService) Stop(executeContext *app.ExecuteContext) error {
+	if chainIndexer.Config == nil || !chainIndexer.Config.Enable {
+		return nil
+	}
 	var errs []error
 	chainIndexer.ctxCancel()
 
@@ -150,25 +258,25 @@ func (chainIndexer *ChainIndexerService) updateLoop() {
 		case <-chainIndexer.update:
 			// Section headers completed (or rolled back), update the index
 			chainIndexer.lock.Lock()
-			if chainIndexer.knownSections > chainIndexer.storedSections {
+			if idx := chainIndexer.nextIndexer(); idx != nil {
 				// Periodically print an upgrade log message to the user
 				if time.Since(updated) > 8*time.Second {
-					if chainIndexer.knownSections > chainIndexer.storedSections+1 {
+					if chainIndexer.knownSections > idx.storedSections+1 {
 						updating = true
-						log.WithField("percentage", chainIndexer.storedSections*100/chainIndexer.knownSections).Info("Upgrading chain index")
+						log.WithField("indexer", idx.name).WithField("percentage", idx.storedSections*100/chainIndexer.knownSections).Info("Upgrading chain index")
 					}
 					updated = time.Now()
 				}
 				// Cache the current section count and head to allow unlocking the mutex
-				chainIndexer.verifyLastHead()
-				section := chainIndexer.storedSections
+				chainIndexer.verifyLastHead(idx)
+				section := idx.storedSections
 				var oldHead crypto.Hash
 				if section > 0 {
-					oldHead = chainIndexer.getSectionHead(section - 1)
+					oldHead = chainIndexer.getSectionHead(idx, section-1)
 				}
 				// Process the newly defined section in the background
 				chainIndexer.lock.Unlock()
-				newHead, err := chainIndexer.processSection(section, oldHead)
+				newHead, err := chainIndexer.processSection(idx, section, oldHead)
 				if err != nil {
 					select {
 					case <-chainIndexer.ctx.Done():
@@ -176,34 +284,31 @@ func (chainIndexer *ChainIndexerService) updateLoop() {
 						return
 					default:
 					}
-					log.WithField("error", err).Error("Section processing failed")
+					log.WithField("indexer", idx.name).WithField("error", err).Error("Section processing failed")
 				}
 				chainIndexer.lock.Lock()
 
 				// If processing succeeded and no reorgs occurred, mark the section completed
-				if err == nil && (section == 0 || oldHead == chainIndexer.getSectionHead(section-1)) {
-					chainIndexer.setSectionHead(section, newHead)
-					chainIndexer.setValidStoredSections(section + 1)
-					if chainIndexer.storedSections == chainIndexer.knownSections && updating {
+				if err == nil && idx.storedSections == section && (section == 0 || oldHead == chainIndexer.getSectionHead(idx, section-1)) {
+					chainIndexer.setSectionHead(idx, section, newHead)
+					chainIndexer.setValidStoredSections(idx, section+1)
+					if chainIndexer.nextIndexer() == nil && updating {
 						updating = false
 						log.Info("Finished upgrading chain index")
 					}
 
 				} else {
 					// If processing failed, don't retry until further notification
-					log.Debug("Chain index processing failed", "section", section, "err", err)
-					chainIndexer.verifyLastHead()
-					chainIndexer.knownSections = chainIndexer.storedSections
+					log.Debug("Chain index processing failed", "indexer", idx.name, "section", section, "err", err)
+					chainIndexer.verifyLastHead(idx)
+					if err != nil {
+						idx.stalled = true
+					}
 				}
 			}
 			// If there are still further sections to process, reschedule
-			if chainIndexer.knownSections > chainIndexer.storedSections {
-				time.AfterFunc(chainIndexer.Config.Throttling, func() {
-					select {
-					case chainIndexer.update <- struct{}{}:
-					default:
-					}
-				})
+			if chainIndexer.nextIndexer() != nil {
+				time.AfterFunc(chainIndexer.Config.Throttling, chainIndexer.scheduleUpdate)
 			}
 			chainIndexer.lock.Unlock()
 		}
@@ -279,8 +384,10 @@ func (chainIndexer *ChainIndexerService) newHead(head uint64, reorg bool) {
 			chainIndexer.knownSections = known
 		}
 		// Revert the stored sections from the database to the reorg point
-		if stored < chainIndexer.storedSections {
-			chainIndexer.setValidStoredSections(stored)
+		for _, idx := range chainIndexer.indexers {
+			if stored < idx.storedSections {
+				chainIndexer.setValidStoredSections(idx, stored)
+			}
 		}
 		// Update the new head number to the finalized section end and notify children
 		head = known * chainIndexer.Config.SectionSize
@@ -296,10 +403,11 @@ func (chainIndexer *ChainIndexerService) newHead(head uint64, reorg bool) {
 
 			chainIndexer.knownSections = sections
 
-			select {
-			case chainIndexer.update <- struct{}{}:
-			default:
+			// retry the indexers which failed with the new sections
+			for _, idx := range chainIndexer.indexers {
+				idx.stalled = false
 			}
+			chainIndexer.scheduleUpdate()
 		}
 	}
 }
@@ -307,19 +415,19 @@ func (chainIndexer *ChainIndexerService) newHead(head uint64, reorg bool) {
 // verifyLastHead compares last stored section head with the corresponding block hash in the
 // actual canonical chain and rolls back reorged sections if necessary to ensure that stored
 // sections are all valid
-func (chainIndexer *ChainIndexerService) verifyLastHead() {
-	for chainIndexer.storedSections > 0 {
+func (chainIndexer *ChainIndexerService) verifyLastHead(idx *indexer) {
+	for idx.storedSections > 0 {
 
 		hash := crypto.Hash{}
-		blockHeader, err := chainIndexer.ChainService.GetBlockHeaderByHeight(chainIndexer.storedSections*chainIndexer.Config.SectionSize - 1)
+		blockHeader, err := chainIndexer.ChainService.GetBlockHeaderByHeight(idx.storedSections*chainIndexer.Config.SectionSize - 1)
 		if err == nil {
 			hash = *blockHeader.Hash()
 		}
 
-		if chainIndexer.getSectionHead(chainIndexer.storedSections-1) == hash {
+		if chainIndexer.getSectionHead(idx, idx.storedSections-1) == hash {
 			return
 		}
-		chainIndexer.setValidStoredSections(chainIndexer.storedSections - 1)
+		chainIndexer.setValidStoredSections(idx, idx.storedSections-1)
 	}
 }
 
@@ -327,12 +435,14 @@ func (chainIndexer *ChainIndexerService) verifyLastHead() {
 // ensuring the continuity of the passed headers. Since the chain mutex is not
 // held while processing, the continuity can be broken by a long reorg, in which
 // case the function returns with an error.
-func (chainIndexer *ChainIndexerService) processSection(section uint64, lastHead crypto.Hash) (crypto.Hash, error) {
-	log.WithField("section", section).Trace("Processing new chain section")
+func (chainIndexer *ChainIndexerService) processSection(idx *indexer, section uint64, lastHead crypto.Hash) (crypto.Hash, error) {
+	log.WithField("indexer", idx.name).WithField("section", section).Trace("Processing new chain section")
 	// Reset and partial processing
 
-	if err := chainIndexer.reset(chainIndexer.ctx, section, lastHead); err != nil {
-		chainIndexer.setValidStoredSections(0)
+	if err := idx.backend.Reset(chainIndexer.ctx, section, lastHead); err != nil {
+		chainIndexer.lock.Lock()
+		chainIndexer.setValidStoredSections(idx, 0)
+		chainIndexer.lock.Unlock()
 		return crypto.Hash{}, err
 	}
 
@@ -352,46 +462,17 @@ func (chainIndexer *ChainIndexerService) processSection(section uint64, lastHead
 		} else if blockHeader.PreviousHash != lastHead {
 			return crypto.Hash{}, fmt.Errorf("chain reorged during section processing")
 		}
-		if err := chainIndexer.process(chainIndexer.ctx, blockHeader); err != nil {
+		if err := idx.backend.Process(chainIndexer.ctx, blockHeader); err != nil {
 			return crypto.Hash{}, err
 		}
 		lastHead = *blockHeader.Hash()
 	}
-	if err := chainIndexer.commit(); err != nil {
+	if err := idx.backend.Commit(); err != nil {
 		return crypto.Hash{}, err
 	}
 	return lastHead, nil
 }
 
-// 启动新的bloombits索引部分。
-func (chainIndexer *ChainIndexerService) reset(ctx context.Context, section uint64, lastSectionHead crypto.Hash) error {
-	gen, err := bloombits.NewGenerator(uint(chainIndexer.Config.SectionSize))
-	chainIndexer.gen, chainIndexer.section, chainIndexer.head = gen, section, crypto.Hash{}
-	return err
-}
-
-// 将新区块头的bloom添加到索引。
-func (chainIndexer *ChainIndexerService) process(ctx context.Context, header *types.BlockHeader) error {
-	chainIndexer.gen.AddBloom(uint(header.Height-chainIndexer.section*chainIndexer.Config.SectionSize), header.Bloom)
-	chainIndexer.head = *header.Hash()
-	return nil
-}
-
-// 完成bloom部分和把它写进数据库。
-func (chainIndexer *ChainIndexerService) commit() error {
-	batch := chainIndexer.DatabaseService.NewBatch()
-	for i := 0; i < types.BloomBitLength; i++ {
-		bits, err := chainIndexer.gen.Bitset(uint(i))
-		if err != nil {
-			return err
-		}
-		if err := batch.Put(bloomBitsKey(uint(i), chainIndexer.section, chainIndexer.head), bitutil.CompressBytes(bits)); err != nil {
-			log.WithField("err", err).Fatal("Failed to store bloom bits")
-		}
-	}
-	return batch.Write()
-}
-
 func (chainIndexer *ChainIndexerService) GetConfig() *ChainIndexerConfig {
 	return chainIndexer.Config
 }
@@ -0,0 +1,309 @@
+package chain_indexer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drep-project/binary"
+	"github.com/pkg/errors"
+
+	"github.com/drep-project/DREP-Chain/chain"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+const testSectionSize = 4
+
+// testChain is a canonical chain of headers, fork replaces its blocks from a height on
+type testChain struct {
+	chain.ChainServiceInterface
+	lock    sync.Mutex
+	headers []*types.BlockHeader
+}
+
+func newTestChain(length int) *testChain {
+	c := &testChain{}
+	c.fork(0, length, 0)
+	return c
+}
+
+func (c *testChain) fork(from, length int, timestamp uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.headers = c.headers[:from]
+	for height := from; height < length; height++ {
+		header := &types.BlockHeader{Height: uint64(height), Timestamp: timestamp}
+		if height > 0 {
+			header.PreviousHash = *c.headers[height-1].Hash()
+		}
+		header.Hash()
+		c.headers = append(c.headers, header)
+	}
+}
+
+func (c *testChain) GetBlockHeaderByHeight(number uint64) (*types.BlockHeader, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if number >= uint64(len(c.headers)) {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return c.headers[number], nil
+}
+
+// testBackend records the calls of the chain indexer
+type testBackend struct {
+	lock      sync.Mutex
+	resets    []uint64
+	processed []uint64
+	rollbacks []uint64
+}
+
+func (b *testBackend) Reset(ctx context.Context, section uint64, prevHead crypto.Hash) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.resets = append(b.resets, section)
+	return nil
+}
+
+func (b *testBackend) Process(ctx context.Context, header *types.BlockHeader) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.processed = append(b.processed, header.Height)
+	return nil
+}
+
+func (b *testBackend) Commit() error {
+	return nil
+}
+
+func (b *testBackend) Rollback(section uint64) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.rollbacks = append(b.rollbacks, section)
+	return nil
+}
+
+func (b *testBackend) calls() (resets, processed, rollbacks []uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]uint64{}, b.resets...), append([]uint64{}, b.processed...), append([]uint64{}, b.rollbacks...)
+}
+
+// newTestIndexer run a chain indexer over c without the bloombits indexer
+func newTestIndexer(db *database.DatabaseService, c *testChain) *ChainIndexerService {
+	chainIndexer := &ChainIndexerService{
+		DatabaseService: db,
+		ChainService:    c,
+		Config:          &ChainIndexerConfig{Enable: true, SectionSize: testSectionSize},
+		update:          make(chan struct{}, 1),
+		quit:            make(chan chan error),
+	}
+	chainIndexer.ctx, chainIndexer.ctxCancel = context.WithCancel(context.Background())
+	go chainIndexer.updateLoop()
+	return chainIndexer
+}
+
+func newTestDatabase(t *testing.T) (*database.DatabaseService, func()) {
+	dir, err := ioutil.TempDir("", "chain_indexer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := database.NewDatabase(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return database.NewDatabaseService(db), func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// waitSections wait until the indexer name has indexed sections
+func waitSections(t *testing.T, chainIndexer *ChainIndexerService, name string, sections uint64) {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		for _, status := range chainIndexer.Status() {
+			if status.Name == name && status.Sections == sections {
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("indexer %s has not indexed %d sections", name, sections)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func storedCount(t *testing.T, db *database.DatabaseService, key string) uint64 {
+	value, err := db.Get([]byte(key))
+	if err != nil {
+		t.Fatalf("%s: %v", key, err)
+	}
+	var count uint64
+	if err := binary.Unmarshal(value, &count); err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func heights(from, to uint64) []uint64 {
+	var hs []uint64
+	for h := from; h < to; h++ {
+		hs = append(hs, h)
+	}
+	return hs
+}
+
+func TestRegisterIndexer(t *testing.T) {
+	db, closeDb := newTestDatabase(t)
+	defer closeDb()
+	c := newTestChain(3*testSectionSize - 1)
+
+	chainIndexer := newTestIndexer(db, c)
+	first, second := &testBackend{}, &testBackend{}
+	if err := chainIndexer.RegisterIndexer("first", first); err != nil {
+		t.Fatal(err)
+	}
+	if err := chainIndexer.RegisterIndexer("second", second); err != nil {
+		t.Fatal(err)
+	}
+	if err := chainIndexer.RegisterIndexer("first", &testBackend{}); errors.Cause(err) != ErrIndexerExists {
+		t.Fatalf("register twice: %v, want %v", err, ErrIndexerExists)
+	}
+
+	// two sections are complete, the third lacks a block
+	chainIndexer.newHead(uint64(3*testSectionSize-2), false)
+	waitSections(t, chainIndexer, "first", 2)
+	waitSections(t, chainIndexer, "second", 2)
+	chainIndexer.Stop(nil)
+
+	for _, backend := range []*testBackend{first, second} {
+		resets, processed, rollbacks := backend.calls()
+		if !reflect.DeepEqual(resets, []uint64{0, 1}) || !reflect.DeepEqual(processed, heights(0, 2*testSectionSize)) || len(rollbacks) != 0 {
+			t.Errorf("backend called with resets %v, processed %v, rollbacks %v", resets, processed, rollbacks)
+		}
+	}
+
+	// every indexer keeps its progress under its own keys
+	if count := storedCount(t, db, "ci_first_count"); count != 2 {
+		t.Errorf("first stored %d sections, want 2", count)
+	}
+	if count := storedCount(t, db, "ci_second_count"); count != 2 {
+		t.Errorf("second stored %d sections, want 2", count)
+	}
+	if _, err := db.Get([]byte(indexerKeyPrefix(BloomIndexerName) + "count")); err == nil {
+		t.Error("progress stored under the bloombits keys")
+	}
+	last, _ := c.GetBlockHeaderByHeight(2*testSectionSize - 1)
+	if head, _ := db.Get(sectionHeadKey(&indexer{prefix: "ci_second_"}, 1)); !reflect.DeepEqual(head, last.Hash().Bytes()) {
+		t.Errorf("section head %x, want %x", head, last.Hash().Bytes())
+	}
+
+	// a restarted indexer resumes from its stored progress
+	restarted := newTestIndexer(db, c)
+	defer restarted.Stop(nil)
+	resumed := &testBackend{}
+	if err := restarted.RegisterIndexer("second", resumed); err != nil {
+		t.Fatal(err)
+	}
+	status := restarted.Status()
+	if len(status) != 1 || status[0].Sections != 2 || status[0].Head != *last.Hash() {
+		t.Fatalf("restarted status %+v, want 2 sections up to %s", status[0], last.Hash().String())
+	}
+	if resets, _, _ := resumed.calls(); len(resets) != 0 {
+		t.Errorf("restarted indexer processed sections %v", resets)
+	}
+}
+
+func TestIndexerRollback(t *testing.T) {
+	db, closeDb := newTestDatabase(t)
+	defer closeDb()
+	c := newTestChain(3*testSectionSize - 1)
+
+	chainIndexer := newTestIndexer(db, c)
+	defer chainIndexer.Stop(nil)
+	backend := &testBackend{}
+	if err := chainIndexer.RegisterIndexer("test", backend); err != nil {
+		t.Fatal(err)
+	}
+	chainIndexer.newHead(uint64(3*testSectionSize-2), false)
+	waitSections(t, chainIndexer, "test", 2)
+
+	// a reorg to a common ancestor in the second section drops it
+	chainIndexer.newHead(testSectionSize+1, true)
+	waitSections(t, chainIndexer, "test", 1)
+	if _, _, rollbacks := backend.calls(); !reflect.DeepEqual(rollbacks, []uint64{1}) {
+		t.Fatalf("rollbacks %v, want [1]", rollbacks)
+	}
+	if count := storedCount(t, db, "ci_test_count"); count != 1 {
+		t.Errorf("stored %d sections, want 1", count)
+	}
+	if _, err := db.Get(sectionHeadKey(&indexer{prefix: "ci_test_"}, 1)); err == nil {
+		t.Error("head of the reorged section kept")
+	}
+
+	// once the new branch is indexed, a stored section head left out of the canonical chain
+	// is rolled back as well
+	c.fork(testSectionSize+2, 3*testSectionSize-1, 1)
+	chainIndexer.newHead(uint64(3*testSectionSize-2), false)
+	waitSections(t, chainIndexer, "test", 2)
+	c.fork(testSectionSize+2, 3*testSectionSize-1, 2)
+	waitSections(t, chainIndexer, "test", 1)
+	if _, _, rollbacks := backend.calls(); !reflect.DeepEqual(rollbacks, []uint64{1, 1}) {
+		t.Fatalf("rollbacks %v, want [1 1]", rollbacks)
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	db, closeDb := newTestDatabase(t)
+	defer closeDb()
+	c := newTestChain(3*testSectionSize - 1)
+
+	chainIndexer := newTestIndexer(db, c)
+	defer chainIndexer.Stop(nil)
+	rebuilt, other := &testBackend{}, &testBackend{}
+	if err := chainIndexer.RegisterIndexer("rebuilt", rebuilt); err != nil {
+		t.Fatal(err)
+	}
+	if err := chainIndexer.RegisterIndexer("other", other); err != nil {
+		t.Fatal(err)
+	}
+	chainIndexer.newHead(uint64(3*testSectionSize-2), false)
+	waitSections(t, chainIndexer, "rebuilt", 2)
+	waitSections(t, chainIndexer, "other", 2)
+
+	if err := chainIndexer.Rebuild("missing"); errors.Cause(err) != ErrIndexerNotFound {
+		t.Fatalf("rebuild unknown indexer: %v, want %v", err, ErrIndexerNotFound)
+	}
+	if err := chainIndexer.Rebuild("rebuilt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, rollbacks := rebuilt.calls(); !reflect.DeepEqual(rollbacks, []uint64{0}) {
+		t.Fatalf("rollbacks %v, want [0]", rollbacks)
+	}
+	waitSections(t, chainIndexer, "rebuilt", 2)
+
+	resets, processed, _ := rebuilt.calls()
+	if !reflect.DeepEqual(resets, []uint64{0, 1, 0, 1}) {
+		t.Errorf("resets %v, want every section processed twice", resets)
+	}
+	if want := append(heights(0, 2*testSectionSize), heights(0, 2*testSectionSize)...); !reflect.DeepEqual(processed, want) {
+		t.Errorf("processed %v, want %v", processed, want)
+	}
+	if count := storedCount(t, db, "ci_rebuilt_count"); count != 2 {
+		t.Errorf("stored %d sections, want 2", count)
+	}
+
+	// the other indexers are left alone
+	if resets, _, rollbacks := other.calls(); len(resets) != 2 || len(rollbacks) != 0 {
+		t.Errorf("other indexer reset %v, rolled back %v", resets, rollbacks)
+	}
+}
@@ -8,10 +8,10 @@ import (
 	"github.com/drep-project/DREP-Chain/chain"
 	"github.com/drep-project/DREP-Chain/common/event"
 	"github.com/drep-project/DREP-Chain/crypto"
-	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
 	"github.com/drep-project/DREP-Chain/database"
 	"github.com/drep-project/DREP-Chain/network/p2p"
 	"github.com/drep-project/DREP-Chain/params"
+	"github.com/drep-project/DREP-Chain/pkgs/accounts/signer"
 	consensusTypes "github.com/drep-project/DREP-Chain/pkgs/consensus/types"
 	"github.com/drep-project/DREP-Chain/types"
 	"io/ioutil"
@@ -28,7 +28,7 @@ const (
 
 type BftConsensus struct {
 	CoinBase  crypto.CommonAddress
-	PrivKey   *secp256k1.PrivateKey
+	Signer    signer.KeySigner
 	curMiner  int
 	minMiners int
 
@@ -78,9 +78,9 @@ func NewBftConsensus(
 	}
 }
 
-func (bftConsensus *BftConsensus) Run(privKey *secp256k1.PrivateKey) (*types.Block, error) {
-	bftConsensus.CoinBase = crypto.PubkeyToAddress(privKey.PubKey())
-	bftConsensus.PrivKey = privKey
+func (bftConsensus *BftConsensus) Run(signer signer.KeySigner) (*types.Block, error) {
+	bftConsensus.CoinBase = crypto.PubkeyToAddress(signer.PubKey())
+	bftConsensus.Signer = signer
 	go bftConsensus.processPeers()
 	miners := bftConsensus.collectMemberStatus()
 	if len(miners) > 1 {
@@ -151,7 +151,7 @@ func (bftConsensus *BftConsensus) collectMemberStatus() []*MemberInfo {
 			pi           consensusTypes.IPeerInfo
 		)
 
-		isMe := bftConsensus.PrivKey.PubKey().IsEqual(produce.Pubkey)
+		isMe := bftConsensus.Signer.PubKey().IsEqual(produce.Pubkey)
 		if isMe {
 			IsOnline = true
 		} else {
@@ -174,7 +174,7 @@ func (bftConsensus *BftConsensus) collectMemberStatus() []*MemberInfo {
 }
 
 func (bftConsensus *BftConsensus) runAsMember(miners []*MemberInfo) (block *types.Block, err error) {
-	member := NewMember(bftConsensus.PrivKey, bftConsensus.sender, bftConsensus.WaitTime, miners, bftConsensus.minMiners, bftConsensus.ChainService.BestChain().Height(), bftConsensus.memberMsgPool)
+	member := NewMember(bftConsensus.Signer, bftConsensus.sender, bftConsensus.WaitTime, miners, bftConsensus.minMiners, bftConsensus.ChainService.BestChain().Height(), bftConsensus.memberMsgPool)
 	log.Trace("node member is going to process consensus for round 1")
 	member.convertor = func(msg []byte) (IConsenMsg, error) {
 		block, err = types.BlockFromMessage(msg)
@@ -243,7 +243,7 @@ func (bftConsensus *BftConsensus) runAsMember(miners []*MemberInfo) (block *type
 //3 leader搜集到所有的签名或者返回的签名个数大于producer个数的三分之二后，开始验证签名
 //4 leader验证签名通过后，广播此块给所有的Peer
 func (bftConsensus *BftConsensus) runAsLeader(miners []*MemberInfo) (block *types.Block, err error) {
-	leader := NewLeader(bftConsensus.PrivKey, bftConsensus.sender, bftConsensus.WaitTime, miners, bftConsensus.minMiners, bftConsensus.ChainService.BestChain().Height(), bftConsensus.leaderMsgPool)
+	leader := NewLeader(bftConsensus.Signer, bftConsensus.sender, bftConsensus.WaitTime, miners, bftConsensus.minMiners, bftConsensus.ChainService.BestChain().Height(), bftConsensus.leaderMsgPool)
 	db := bftConsensus.DbService.BeginTransaction(false)
 	var gasFee *big.Int
 	block, gasFee, err = bftConsensus.BlockGenerator.GenerateTemplate(db, bftConsensus.CoinBase)
@@ -5,6 +5,7 @@ import (
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
 	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/DREP-Chain/pkgs/accounts/signer"
 	consensusTypes "github.com/drep-project/DREP-Chain/pkgs/consensus/types"
 	"math/big"
 	"sync"
@@ -31,9 +32,8 @@ type Leader struct {
 	producers   []*MemberInfo
 	liveMembers []*MemberInfo
 
-	pubkey         *secp256k1.PublicKey
-	privakey       *secp256k1.PrivateKey
-	randomPrivakey *secp256k1.PrivateKey
+	pubkey *secp256k1.PublicKey
+	signer signer.KeySigner
 
 	commitKey *secp256k1.PublicKey
 	sender    Sender
@@ -41,6 +41,7 @@ type Leader struct {
 	commitBitmap      []byte
 	sigmaPubKey       []*secp256k1.PublicKey
 	sigmaCommitPubkey []*secp256k1.PublicKey
+	signMsg           []byte
 	msgHash           []byte
 
 	sigmaS         *schnorr.Signature
@@ -59,10 +60,10 @@ type Leader struct {
 	cancelWaitChallenge chan struct{}
 }
 
-func NewLeader(privkey *secp256k1.PrivateKey, p2pServer Sender, waitTime time.Duration, producers []*MemberInfo, minMember int, curHeight uint64, msgPool chan *MsgWrap) *Leader {
+func NewLeader(signer signer.KeySigner, p2pServer Sender, waitTime time.Duration, producers []*MemberInfo, minMember int, curHeight uint64, msgPool chan *MsgWrap) *Leader {
 	l := &Leader{}
-	l.pubkey = privkey.PubKey()
-	l.privakey = privkey
+	l.pubkey = signer.PubKey()
+	l.signer = signer
 	l.waitTime = waitTime
 	l.sender = p2pServer
 	l.msgPool = msgPool
@@ -84,7 +85,6 @@ func (leader *Leader) Reset() {
 	leader.sigmaPubKey = nil
 	leader.sigmaCommitPubkey = nil
 	leader.sigmaS = nil
-	leader.randomPrivakey = nil
 
 	length := len(leader.producers)
 	leader.commitBitmap = make([]byte, length)
@@ -156,10 +156,9 @@ func (leader *Leader) processP2pMessage() {
 func (leader *Leader) setUp(msg IConsenMsg) {
 	setup := &Setup{Msg: msg.AsMessage()}
 	setup.Height = leader.currentHeight
-	leader.msgHash = sha3.Keccak256(msg.AsSignMessage())
-	var err error
-	var nouncePk *secp256k1.PublicKey
-	leader.randomPrivakey, nouncePk, err = schnorr.GenerateNoncePair(secp256k1.S256(), leader.msgHash, leader.privakey, nil, schnorr.Sha256VersionStringRFC6979)
+	leader.signMsg = msg.AsSignMessage()
+	leader.msgHash = sha3.Keccak256(leader.signMsg)
+	nouncePk, err := leader.signer.SchnorrNonce(leader.signMsg)
 	leader.sigmaPubKey = []*secp256k1.PublicKey{leader.pubkey}
 	leader.sigmaCommitPubkey = []*secp256k1.PublicKey{nouncePk}
 
@@ -295,7 +294,7 @@ func (leader *Leader) challenge(msg IConsenMsg) {
 func (leader *Leader) selfSign(msg IConsenMsg) error {
 	// pk1 | pk2 | pk3 | pk4
 	commitPubkey := schnorr.CombinePubkeys(leader.sigmaCommitPubkey[1:])
-	sig, err := schnorr.PartialSign(secp256k1.S256(), leader.msgHash, leader.privakey, leader.randomPrivakey, commitPubkey)
+	sig, err := leader.signer.SchnorrPartialSign(leader.signMsg, commitPubkey)
 	if err != nil {
 		return err
 	}
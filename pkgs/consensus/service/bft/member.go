@@ -5,8 +5,8 @@ import (
 	"errors"
 	"github.com/drep-project/binary"
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
-	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
 	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/DREP-Chain/pkgs/accounts/signer"
 	consensusTypes "github.com/drep-project/DREP-Chain/pkgs/consensus/types"
 	"math/big"
 	"sync"
@@ -17,14 +17,14 @@ type Member struct {
 	leader      *MemberInfo
 	producers   []*MemberInfo
 	liveMembers []*MemberInfo
-	prvKey      *secp256k1.PrivateKey
+	signer      signer.KeySigner
 	p2pServer   Sender
 
 	msg     IConsenMsg
+	signMsg []byte
 	msgHash []byte
 
-	randomPrivakey *secp256k1.PrivateKey
-	r              *big.Int
+	r *big.Int
 
 	waitTime time.Duration
 
@@ -43,9 +43,9 @@ type Member struct {
 	convertor  func(msg []byte) (IConsenMsg, error)
 }
 
-func NewMember(prvKey *secp256k1.PrivateKey, p2pServer Sender, waitTime time.Duration, producers []*MemberInfo, minMember int, curHeight uint64, msgPool chan *MsgWrap) *Member {
+func NewMember(signer signer.KeySigner, p2pServer Sender, waitTime time.Duration, producers []*MemberInfo, minMember int, curHeight uint64, msgPool chan *MsgWrap) *Member {
 	member := &Member{}
-	member.prvKey = prvKey
+	member.signer = signer
 	member.waitTime = waitTime
 	member.p2pServer = p2pServer
 	member.msgPool = msgPool
@@ -72,8 +72,8 @@ func NewMember(prvKey *secp256k1.PrivateKey, p2pServer Sender, waitTime time.Dur
 
 func (member *Member) Reset() {
 	member.msg = nil
+	member.signMsg = nil
 	member.msgHash = nil
-	member.randomPrivakey = nil
 	member.cancelPool = make(chan struct{}, 1)
 	member.errorChanel = make(chan error, 1)
 	member.completed = make(chan struct{}, 1)
@@ -186,7 +186,8 @@ func (member *Member) OnSetUp(peer consensusTypes.IPeerInfo, setUp *Setup) {
 		if err != nil {
 			return
 		}
-		member.msgHash = sha3.Keccak256(member.msg.AsSignMessage())
+		member.signMsg = member.msg.AsSignMessage()
+		member.msgHash = sha3.Keccak256(member.signMsg)
 		member.commit()
 		log.Debug("sent commit message to leader")
 		member.setState(WAIT_CHALLENGE)
@@ -267,16 +268,13 @@ func (member *Member) commit() {
 		return
 	}
 	//TODO validate block from leader
-	var err error
-	var nouncePk *secp256k1.PublicKey
-
-	member.randomPrivakey, nouncePk, err = schnorr.GenerateNoncePair(secp256k1.S256(), member.msgHash, member.prvKey, nil, schnorr.Sha256VersionStringRFC6979)
+	nouncePk, err := member.signer.SchnorrNonce(member.signMsg)
 	if err != nil {
 		member.pushErrorMsg(ErrGenerateNouncePriv)
 		return
 	}
 	commitment := &Commitment{
-		BpKey: member.prvKey.PubKey(),
+		BpKey: member.signer.PubKey(),
 		Q:     (*secp256k1.PublicKey)(nouncePk),
 	}
 	commitment.Height = member.currentHeight
@@ -285,13 +283,13 @@ func (member *Member) commit() {
 
 func (member *Member) response(challengeMsg *Challenge) {
 	if bytes.Equal(member.msgHash, challengeMsg.R) {
-		sig, err := schnorr.PartialSign(secp256k1.S256(), member.msgHash, member.prvKey, member.randomPrivakey, challengeMsg.SigmaQ)
+		sig, err := member.signer.SchnorrPartialSign(member.signMsg, challengeMsg.SigmaQ)
 		if err != nil {
 			log.WithField("msg", err).Error("sign chanllenge error ")
 			return
 		}
 		response := &Response{S: sig.Serialize()}
-		response.BpKey = member.prvKey.PubKey()
+		response.BpKey = member.signer.PubKey()
 		response.Height = member.currentHeight
 		member.p2pServer.SendAsync(member.leader.Peer.GetMsgRW(), MsgTypeResponse, response)
 	} else {
@@ -3,14 +3,19 @@ package bft
 import (
 	"fmt"
 	"github.com/drep-project/binary"
+	"github.com/drep-project/DREP-Chain/crypto"
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
 	"github.com/drep-project/DREP-Chain/crypto/sha3"
 	"github.com/drep-project/DREP-Chain/network/p2p"
+	"github.com/drep-project/DREP-Chain/pkgs/accounts/signer"
 	consensusTypes "github.com/drep-project/DREP-Chain/pkgs/consensus/types"
+	"github.com/drep-project/DREP-Chain/types"
 	"github.com/sirupsen/logrus"
+	"io/ioutil"
 	"math"
 	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
@@ -205,13 +210,13 @@ type bftResult struct {
 }
 
 func (testbft *testBFT) runAsLeader(miners []*MemberInfo) *bftResult {
-	testbft.leader = NewLeader(testbft.PrivKey, testbft.sender, testbft.WaitTime, miners, testbft.minMiners, testbft.curentHeight, testbft.leaderMsgPool)
+	testbft.leader = NewLeader(signer.NewLocalKeySigner(testbft.PrivKey), testbft.sender, testbft.WaitTime, miners, testbft.minMiners, testbft.curentHeight, testbft.leaderMsgPool)
 	err, sig, bitmap := testbft.leader.ProcessConsensus(&dummyConsensusMsg{})
 	return &bftResult{bitmap, &dummyConsensusMsg{}, sig, err}
 }
 
 func (testbft *testBFT) runAsMember(miners []*MemberInfo) *bftResult {
-	testbft.member = NewMember(testbft.PrivKey, testbft.sender, testbft.WaitTime, miners, testbft.minMiners, testbft.curentHeight, testbft.memberMsgPool)
+	testbft.member = NewMember(signer.NewLocalKeySigner(testbft.PrivKey), testbft.sender, testbft.WaitTime, miners, testbft.minMiners, testbft.curentHeight, testbft.memberMsgPool)
 	testbft.member.convertor = func(msg []byte) (IConsenMsg, error) {
 		return &dummyConsensusMsg{}, nil
 	}
@@ -346,3 +351,28 @@ func TestBFTTimeOut(t *testing.T) {
 
 	group.Wait()
 }
+
+func TestSignerAcceptsConsensusMessages(t *testing.T) {
+	privKey, _ := secp256k1.GeneratePrivateKey(nil)
+	addr := crypto.PubkeyToAddress(privKey.PubKey())
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	audit, err := signer.OpenAuditLog(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer audit.Close()
+	api := signer.NewSignerApi([]*types.Node{{Address: &addr, PrivateKey: privKey}}, &signer.Rules{Producers: []crypto.CommonAddress{addr}}, nil, audit)
+
+	block := &types.Block{Header: &types.BlockHeader{Height: 2, StateRoot: sha3.Keccak256([]byte("state"))}}
+	sig, _ := privKey.Sign(sha3.Keccak256([]byte("sig")))
+	completed := &CompletedBlockMessage{*newMultiSignature(*sig, 0, []byte{1, 1}), block.Header.StateRoot}
+	for _, msg := range []IConsenMsg{block, completed} {
+		if _, err := api.SchnorrNonce(addr, msg.AsSignMessage()); err != nil {
+			t.Fatalf("sign message of %T rejected: %v", msg, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/drep-project/DREP-Chain/app"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+)
+
+// ValidateConfig check the consensus mode, the pubkeys and ips of producers and that the key
+// used to produce blocks belongs to a producer
+func (consensusService *ConsensusService) ValidateConfig(executeContext *app.ExecuteContext) app.ConfigErrors {
+	config := consensusService.Config
+	var errs app.ConfigErrors
+	switch config.ConsensusMode {
+	case "":
+		return nil
+	case "solo", "bft":
+	default:
+		return app.ConfigErrors{app.NewConfigError(`must be one of "solo", "bft"`, MODULENAME, "consensusMode")}
+	}
+
+	// pubkeys are checked on the raw config so that each invalid one can be reported with its path
+	raw := struct {
+		MyPk      json.RawMessage `json:"mypk"`
+		Producers []struct {
+			Pubkey json.RawMessage `json:"pubkey"`
+		} `json:"producers"`
+	}{}
+	if phase := executeContext.GetConfig(MODULENAME); phase != nil && json.Unmarshal(phase, &raw) == nil {
+		for i, producer := range raw.Producers {
+			if err := checkPubkey(producer.Pubkey); err != nil {
+				errs = append(errs, app.NewConfigError(err.Error(), MODULENAME, "producers", i, "pubkey"))
+			}
+		}
+		if len(raw.MyPk) > 0 {
+			if err := checkPubkey(raw.MyPk); err != nil {
+				errs = append(errs, app.NewConfigError(err.Error(), MODULENAME, "mypk"))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if len(config.Producers) == 0 {
+		return app.ConfigErrors{app.NewConfigError("at least one producer is required", MODULENAME, "producers")}
+	}
+	for i, producer := range config.Producers {
+		if producer.Pubkey == nil {
+			errs = append(errs, app.NewConfigError("pubkey is required", MODULENAME, "producers", i, "pubkey"))
+			continue
+		}
+		if net.ParseIP(producer.IP) == nil {
+			errs = append(errs, app.NewConfigError(fmt.Sprintf("invalid ip %q", producer.IP), MODULENAME, "producers", i, "ip"))
+		}
+		for j := 0; j < i; j++ {
+			if config.Producers[j].Pubkey != nil && config.Producers[j].Pubkey.IsEqual(producer.Pubkey) {
+				errs = append(errs, app.NewConfigError(fmt.Sprintf("duplicate pubkey of /%s/producers/%d", MODULENAME, j), MODULENAME, "producers", i, "pubkey"))
+				break
+			}
+		}
+	}
+	if len(errs) > 0 || !config.Enable {
+		return errs
+	}
+
+	if config.MyPk == nil {
+		return app.ConfigErrors{app.NewConfigError("mypk is required when consensus is enabled", MODULENAME, "mypk")}
+	}
+	if config.ConsensusMode == "solo" && !config.Producers[0].Pubkey.IsEqual(config.MyPk) {
+		errs = append(errs, app.NewConfigError("solo consensus must be run by the first producer", MODULENAME, "mypk"))
+	} else if !config.Producers.IsLocalPk(config.MyPk) {
+		errs = append(errs, app.NewConfigError("mypk is not in the producer set", MODULENAME, "mypk"))
+	}
+	return errs
+}
+
+// checkPubkey check that a json value is a valid secp256k1 public key
+func checkPubkey(value json.RawMessage) error {
+	if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+		return fmt.Errorf("pubkey is required")
+	}
+	if err := new(secp256k1.PublicKey).UnmarshalJSON(value); err != nil {
+		return fmt.Errorf("invalid pubkey: %v", err)
+	}
+	return nil
+}
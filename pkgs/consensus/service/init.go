@@ -2,6 +2,7 @@ package service
 
 import (
 	dlog "github.com/drep-project/DREP-Chain/pkgs/log"
+	"github.com/drep-project/DREP-Chain/pkgs/metrics"
 )
 
 const (
@@ -10,4 +11,7 @@ const (
 
 var (
 	log = dlog.EnsureLogger(MODULENAME)
+
+	roundCounter = metrics.NewCounterVec("consensus_rounds_total", "Number of consensus rounds by outcome", "result")
+	roundTimer   = metrics.NewHistogram("consensus_round_seconds", "Time spent in a consensus round", nil)
 )
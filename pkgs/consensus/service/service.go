@@ -2,7 +2,6 @@ package service
 
 import (
 	"github.com/drep-project/DREP-Chain/crypto"
-	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
 	"time"
 
 	"github.com/drep-project/DREP-Chain/pkgs/consensus/service/bft"
@@ -16,6 +15,7 @@ import (
 	"github.com/drep-project/DREP-Chain/network/p2p"
 	p2pService "github.com/drep-project/DREP-Chain/network/service"
 	accountService "github.com/drep-project/DREP-Chain/pkgs/accounts/service"
+	"github.com/drep-project/DREP-Chain/pkgs/accounts/signer"
 	consensusTypes "github.com/drep-project/DREP-Chain/pkgs/consensus/types"
 	chainTypes "github.com/drep-project/DREP-Chain/types"
 	"gopkg.in/urfave/cli.v1"
@@ -47,7 +47,7 @@ type ConsensusService struct {
 	syncBlockEventSub  event.Subscription
 	syncBlockEventChan chan event.SyncBlockEvent
 	ConsensusEngine    consensusTypes.IConsensusEngine
-	Miner              *secp256k1.PrivateKey
+	Miner              signer.KeySigner
 	//During the process of synchronizing blocks, the miner stopped mining
 	pauseForSync bool
 	start        bool
@@ -67,11 +67,13 @@ func (consensusService *ConsensusService) CommandFlags() ([]cli.Command, []cli.F
 	return nil, []cli.Flag{EnableConsensusFlag}
 }
 
-func (consensusService *ConsensusService) Init(executeContext *app.ExecuteContext) error {
+func (consensusService *ConsensusService) ApplyFlags(executeContext *app.ExecuteContext) {
 	if executeContext.Cli.GlobalIsSet(EnableConsensusFlag.Name) {
 		consensusService.Config.Enable = executeContext.Cli.GlobalBool(EnableConsensusFlag.Name)
 	}
+}
 
+func (consensusService *ConsensusService) Init(executeContext *app.ExecuteContext) error {
 	if consensusService.Config.ConsensusMode == "bft" {
 		consensusService.ChainService.AddBlockValidator(&bft.BlockMultiSigValidator{consensusService.Config.Producers})
 	} else if consensusService.Config.ConsensusMode == "solo" {
@@ -109,13 +111,13 @@ func (consensusService *ConsensusService) Init(executeContext *app.ExecuteContex
 	} else {
 		return nil
 	}
-	//consult privkey in wallet
-	accountNode, err := consensusService.WalletService.Wallet.GetAccountByPubkey(consensusService.Config.MyPk)
+	//consult privkey in wallet or external signer
+	miner, err := consensusService.WalletService.Wallet.KeySigner(consensusService.Config.MyPk)
 	if err != nil {
-		log.WithField("init err", err).WithField("addr", crypto.PubkeyToAddress(consensusService.Config.MyPk).String()).Error("privkey of MyPk in Config is not in local wallet")
+		log.WithField("init err", err).WithField("addr", crypto.PubkeyToAddress(consensusService.Config.MyPk).String()).Error("privkey of MyPk in Config is not in local wallet or signer")
 		return err
 	}
-	consensusService.Miner = accountNode.PrivateKey
+	consensusService.Miner = miner
 	consensusService.P2pServer.AddProtocols([]p2p.Protocol{
 		p2p.Protocol{
 			Name:   "consensusService",
@@ -144,7 +146,7 @@ func (consensusService *ConsensusService) Init(executeContext *app.ExecuteContex
 			Service: &ConsensusApi{
 				consensusService: consensusService,
 			},
-			Public: true,
+			Public: false,
 		},
 	}
 
@@ -186,15 +188,20 @@ func (consensusService *ConsensusService) Start(executeContext *app.ExecuteConte
 					continue
 				}
 				log.WithField("Height", consensusService.ChainService.BestChain().Height()).Trace("node start")
+				roundStart := time.Now()
 				block, err := consensusService.ConsensusEngine.Run(consensusService.Miner)
+				roundTimer.ObserveSince(roundStart)
 				if err != nil {
+					roundCounter.WithLabelValues("failed").Inc()
 					log.WithField("Reason", err.Error()).Debug("Producer Block Fail")
 				} else {
 					_, _, err := consensusService.ChainService.ProcessBlock(block)
 					if err == nil {
+						roundCounter.WithLabelValues("success").Inc()
 						consensusService.BroadCastor.BroadcastBlock(chainTypes.MsgTypeBlock, block, true)
 						log.WithField("Height", block.Header.Height).WithField("txs:", block.Data.TxCount).Info("Process block successfully and broad case block message")
 					} else {
+						roundCounter.WithLabelValues("rejected").Inc()
 						log.WithField("Height", block.Header.Height).WithField("txs:", block.Data.TxCount).WithField("err", err).Info("Process Block fail")
 					}
 				}
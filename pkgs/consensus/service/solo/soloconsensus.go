@@ -10,17 +10,17 @@ import (
 	"github.com/drep-project/DREP-Chain/chain"
 	"github.com/drep-project/DREP-Chain/crypto"
 	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
-	"github.com/drep-project/DREP-Chain/crypto/sha3"
 	"github.com/drep-project/DREP-Chain/database"
 	"github.com/drep-project/DREP-Chain/network/p2p"
 	"github.com/drep-project/DREP-Chain/params"
+	"github.com/drep-project/DREP-Chain/pkgs/accounts/signer"
 	consensusTypes "github.com/drep-project/DREP-Chain/pkgs/consensus/types"
 	"github.com/drep-project/DREP-Chain/types"
 )
 
 type SoloConsensus struct {
 	CoinBase       crypto.CommonAddress
-	PrivKey        *secp256k1.PrivateKey
+	Signer         signer.KeySigner
 	Pubkey         *secp256k1.PublicKey
 	blockGenerator blockmgr.IBlockBlockGenerator
 	ChainService   chain.ChainServiceInterface
@@ -40,9 +40,9 @@ func NewSoloConsensus(
 	}
 }
 
-func (soloConsensus *SoloConsensus) Run(privKey *secp256k1.PrivateKey) (*types.Block, error) {
-	soloConsensus.CoinBase = crypto.PubkeyToAddress(privKey.PubKey())
-	soloConsensus.PrivKey = privKey
+func (soloConsensus *SoloConsensus) Run(signer signer.KeySigner) (*types.Block, error) {
+	soloConsensus.CoinBase = crypto.PubkeyToAddress(signer.PubKey())
+	soloConsensus.Signer = signer
 	//区块生成 共识 奖励 验证 完成
 	log.Trace("node leader finishes process consensus")
 
@@ -51,7 +51,7 @@ func (soloConsensus *SoloConsensus) Run(privKey *secp256k1.PrivateKey) (*types.B
 	if err != nil {
 		return nil, err
 	}
-	sig, err := soloConsensus.PrivKey.Sign(sha3.Keccak256(block.AsSignMessage()))
+	sig, err := soloConsensus.Signer.Sign(block.AsSignMessage())
 	if err != nil {
 		log.Error("sign block error")
 		return nil, errors.New("sign block error")
@@ -0,0 +1,86 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+// EventById looks up an event by the hash of its signature, the first topic of its logs
+// returns nil if none found
+func (abi *ABI) EventById(topic crypto.Hash) (*Event, error) {
+	for _, event := range abi.Events {
+		if !event.Anonymous && event.Id() == topic {
+			return &event, nil
+		}
+	}
+	return nil, fmt.Errorf("no event with id: %v", topic)
+}
+
+// DecodeLog decodes a log with the event matching its first topic. Indexed arguments are read
+// from the remaining topics and the others are unpacked from the data, indexed arguments of
+// dynamic types are only logged as the hash of their value which is returned instead.
+func (abi *ABI) DecodeLog(topics []crypto.Hash, data []byte) (*types.DecodedEvent, error) {
+	if len(topics) == 0 {
+		return nil, errors.New("abi: log without topics can not be decoded")
+	}
+	event, err := abi.EventById(topics[0])
+	if err != nil {
+		return nil, err
+	}
+	values, err := event.Inputs.UnpackValues(data)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]types.DecodedArg, len(event.Inputs))
+	topic, value := 1, 0
+	for i, input := range event.Inputs {
+		args[i] = types.DecodedArg{Name: input.Name, Type: input.Type.String()}
+		if !input.Indexed {
+			args[i].Value = values[value]
+			value++
+			continue
+		}
+		if topic >= len(topics) {
+			return nil, fmt.Errorf("abi: missing topic of indexed argument %v of event %v", input.Name, event.Name)
+		}
+		args[i].Value, err = readTopic(input.Type, topics[topic])
+		if err != nil {
+			return nil, err
+		}
+		topic++
+	}
+	return &types.DecodedEvent{Name: event.Name, Signature: event.Sig(), Args: args}, nil
+}
+
+// DecodeInput decodes the input of a contract call with the method matching its 4-byte id
+func (abi *ABI) DecodeInput(data []byte) (*types.DecodedCall, error) {
+	method, err := abi.MethodById(data)
+	if err != nil {
+		return nil, err
+	}
+	values, err := method.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]types.DecodedArg, len(method.Inputs))
+	for i, input := range method.Inputs {
+		args[i] = types.DecodedArg{Name: input.Name, Type: input.Type.String(), Value: values[i]}
+	}
+	return &types.DecodedCall{Method: method.Name, Signature: method.Sig(), Args: args}, nil
+}
+
+// readTopic reads the value of an indexed argument, the topic of a value of dynamic type is the
+// hash of the value
+func readTopic(t Type, topic crypto.Hash) (interface{}, error) {
+	switch t.T {
+	case StringTy, BytesTy, SliceTy, ArrayTy:
+		return topic, nil
+	default:
+		return toGoType(0, t, topic[:])
+	}
+}
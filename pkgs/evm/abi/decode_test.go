@@ -0,0 +1,160 @@
+package abi
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+)
+
+const decodeTestAbi = `[
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Named","inputs":[{"name":"name","type":"string","indexed":true},{"name":"id","type":"uint256","indexed":true}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}]}
+]`
+
+func TestDecodeLog(t *testing.T) {
+	abi, err := JSON(strings.NewReader(decodeTestAbi))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, to := crypto.CommonAddress{1}, crypto.CommonAddress{2}
+	nameHash := crypto.Bytes2Hash(sha3.Keccak256([]byte("drep")))
+	transfer, named := abi.Events["Transfer"].Id(), abi.Events["Named"].Id()
+
+	tests := []struct {
+		name   string
+		topics []crypto.Hash
+		data   []byte
+		values []interface{}
+		err    bool
+	}{
+		{
+			name:   "indexed static args",
+			topics: []crypto.Hash{transfer, crypto.BytesToHash(from[:]), crypto.BytesToHash(to[:])},
+			data:   crypto.BigToHash(big.NewInt(5)).Bytes(),
+			values: []interface{}{from, to, big.NewInt(5)},
+		},
+		{
+			name:   "indexed dynamic args as hashes",
+			topics: []crypto.Hash{named, nameHash, crypto.BigToHash(big.NewInt(7))},
+			values: []interface{}{nameHash, big.NewInt(7)},
+		},
+		{
+			name:   "missing topics",
+			topics: []crypto.Hash{transfer, crypto.BytesToHash(from[:])},
+			data:   crypto.BigToHash(big.NewInt(5)).Bytes(),
+			err:    true,
+		},
+		{
+			name: "no topics",
+			err:  true,
+		},
+		{
+			name:   "unknown event",
+			topics: []crypto.Hash{{1}},
+			err:    true,
+		},
+	}
+	for _, test := range tests {
+		event, err := abi.DecodeLog(test.topics, test.data)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: expect error, got %v", test.name, event)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		values := []interface{}{}
+		for _, arg := range event.Args {
+			values = append(values, arg.Value)
+		}
+		if !reflect.DeepEqual(values, test.values) {
+			t.Errorf("%s: decoded %v, want %v", test.name, values, test.values)
+		}
+	}
+}
+
+func TestDecodeInput(t *testing.T) {
+	abi, err := JSON(strings.NewReader(decodeTestAbi))
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := crypto.CommonAddress{2}
+	input, err := abi.Pack("transfer", to, big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		data   []byte
+		values []interface{}
+		err    bool
+	}{
+		{"call", input, []interface{}{to, big.NewInt(5)}, false},
+		{"input shorter than 4 bytes", input[:3], nil, true},
+		{"no input", nil, nil, true},
+		{"unknown method", []byte{1, 2, 3, 4}, nil, true},
+		{"truncated arguments", input[:36], nil, true},
+	}
+	for _, test := range tests {
+		call, err := abi.DecodeInput(test.data)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: expect error, got %v", test.name, call)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if call.Method != "transfer" {
+			t.Errorf("%s: decoded method %s", test.name, call.Method)
+		}
+		values := []interface{}{}
+		for _, arg := range call.Args {
+			values = append(values, arg.Value)
+		}
+		if !reflect.DeepEqual(values, test.values) {
+			t.Errorf("%s: decoded %v, want %v", test.name, values, test.values)
+		}
+	}
+}
+
+func TestReadTopic(t *testing.T) {
+	topic := crypto.BigToHash(big.NewInt(1))
+	tests := []struct {
+		typ   string
+		value interface{}
+	}{
+		{"uint256", big.NewInt(1)},
+		{"bool", true},
+		{"address", crypto.BytesToAddress(topic[:])},
+		{"string", topic},
+		{"bytes", topic},
+		{"uint256[]", topic},
+		{"uint256[2]", topic},
+	}
+	for _, test := range tests {
+		typ, err := NewType(test.typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, err := readTopic(typ, topic)
+		if err != nil {
+			t.Errorf("%s: %v", test.typ, err)
+			continue
+		}
+		if !reflect.DeepEqual(value, test.value) {
+			t.Errorf("%s: read %v, want %v", test.typ, value, test.value)
+		}
+	}
+}
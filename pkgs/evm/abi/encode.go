@@ -0,0 +1,158 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+// PackConstructor encodes the json values of the constructor arguments, the result is appended to
+// the creation bytecode of the contract
+func (abi ABI) PackConstructor(values []json.RawMessage) ([]byte, error) {
+	args, err := abi.Constructor.Inputs.ParseJSON(values)
+	if err != nil {
+		return nil, err
+	}
+	return abi.Constructor.Inputs.Pack(args...)
+}
+
+// ParseJSON converts json values to the go values packed for the arguments. Integers are json
+// numbers or decimal and 0x prefixed hex strings, addresses and bytes are hex strings and arrays
+// are json arrays.
+func (arguments Arguments) ParseJSON(values []json.RawMessage) ([]interface{}, error) {
+	if len(values) != len(arguments) {
+		return nil, fmt.Errorf("argument count mismatch: %d for %d", len(values), len(arguments))
+	}
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		arg, err := parseJSONValue(arguments[i].Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("abi: argument %d: %v", i, err)
+		}
+		args[i] = arg.Interface()
+	}
+	return args, nil
+}
+
+func parseJSONValue(t Type, value json.RawMessage) (reflect.Value, error) {
+	switch t.T {
+	case IntTy, UintTy:
+		return parseJSONInt(t, value)
+	case BoolTy:
+		var b bool
+		if err := json.Unmarshal(value, &b); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+	case StringTy:
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(s), nil
+	case AddressTy:
+		b, err := parseJSONBytes(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) != crypto.AddressLength {
+			return reflect.Value{}, fmt.Errorf("invalid address length %d", len(b))
+		}
+		return reflect.ValueOf(crypto.BytesToAddress(b)), nil
+	case BytesTy:
+		b, err := parseJSONBytes(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+	case FixedBytesTy:
+		b, err := parseJSONBytes(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) > t.Size {
+			return reflect.Value{}, fmt.Errorf("%d bytes do not fit %v", len(b), t)
+		}
+		array := reflect.New(t.Type).Elem()
+		reflect.Copy(array, reflect.ValueOf(b))
+		return array, nil
+	case SliceTy, ArrayTy:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(value, &elems); err != nil {
+			return reflect.Value{}, err
+		}
+		if t.T == ArrayTy && len(elems) != t.Size {
+			return reflect.Value{}, fmt.Errorf("%d elements for %v", len(elems), t)
+		}
+		var list reflect.Value
+		if t.T == ArrayTy {
+			list = reflect.New(t.Type).Elem()
+		} else {
+			list = reflect.MakeSlice(t.Type, len(elems), len(elems))
+		}
+		for i, elem := range elems {
+			v, err := parseJSONValue(*t.Elem, elem)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			list.Index(i).Set(v)
+		}
+		return list, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported json argument type %v", t)
+	}
+}
+
+func parseJSONInt(t Type, value json.RawMessage) (reflect.Value, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		s = string(value)
+	}
+	var (
+		n  *big.Int
+		ok bool
+	)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		n, ok = new(big.Int).SetString(s[2:], 16)
+	} else {
+		n, ok = new(big.Int).SetString(s, 10)
+	}
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid integer %s", value)
+	}
+
+	if t.T == UintTy && (n.Sign() < 0 || n.BitLen() > t.Size) {
+		return reflect.Value{}, fmt.Errorf("%v overflows %v", n, t)
+	}
+	if t.T == IntTy {
+		// the range of intN is [-2^(N-1), 2^(N-1)-1]
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+		if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return reflect.Value{}, fmt.Errorf("%v overflows %v", n, t)
+		}
+	}
+
+	if t.Type == bigT {
+		return reflect.ValueOf(n), nil
+	}
+	v := reflect.New(t.Type).Elem()
+	if t.T == UintTy {
+		v.SetUint(n.Uint64())
+	} else {
+		v.SetInt(n.Int64())
+	}
+	return v, nil
+}
+
+func parseJSONBytes(value json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return nil, err
+	}
+	return common.Decode(s)
+}
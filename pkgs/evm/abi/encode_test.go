@@ -0,0 +1,151 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+func TestParseJSON(t *testing.T) {
+	maxUint256, _ := new(big.Int).SetString(strings.Repeat("f", 64), 16)
+	minInt256 := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))
+	addr := crypto.CommonAddress{19: 1}
+
+	tests := []struct {
+		typ   string
+		value string
+		want  interface{}
+		err   bool
+	}{
+		// integers are json numbers, decimal strings or hex strings, bounded by their bit width
+		{typ: "uint8", value: `255`, want: uint8(255)},
+		{typ: "uint8", value: `"255"`, want: uint8(255)},
+		{typ: "uint8", value: `"0xff"`, want: uint8(255)},
+		{typ: "uint8", value: `256`, err: true},
+		{typ: "uint8", value: `-1`, err: true},
+		{typ: "int8", value: `127`, want: int8(127)},
+		{typ: "int8", value: `-128`, want: int8(-128)},
+		{typ: "int8", value: `128`, err: true},
+		{typ: "int8", value: `-129`, err: true},
+		{typ: "uint64", value: `"18446744073709551615"`, want: uint64(18446744073709551615)},
+		{typ: "uint64", value: `"18446744073709551616"`, err: true},
+		{typ: "uint256", value: `"16"`, want: big.NewInt(16)},
+		{typ: "uint256", value: `"0x10"`, want: big.NewInt(16)},
+		{typ: "uint256", value: `"0x` + strings.Repeat("f", 64) + `"`, want: maxUint256},
+		{typ: "uint256", value: `"0x1` + strings.Repeat("0", 64) + `"`, err: true},
+		{typ: "int256", value: `"` + minInt256.String() + `"`, want: minInt256},
+		{typ: "int256", value: `"0x8` + strings.Repeat("0", 63) + `"`, err: true},
+		{typ: "uint256", value: `"0xzz"`, err: true},
+		{typ: "uint256", value: `"ten"`, err: true},
+
+		{typ: "bool", value: `true`, want: true},
+		{typ: "bool", value: `"true"`, err: true},
+		{typ: "string", value: `"drep"`, want: "drep"},
+
+		// addresses and bytes are hex strings
+		{typ: "address", value: `"0x` + strings.Repeat("0", 39) + `1"`, want: addr},
+		{typ: "address", value: `"0x` + strings.Repeat("0", 38) + `"`, err: true},
+		{typ: "address", value: `"0x` + strings.Repeat("0", 42) + `"`, err: true},
+		{typ: "address", value: `"drep"`, err: true},
+		{typ: "bytes", value: `"0x0102"`, want: []byte{1, 2}},
+		{typ: "bytes", value: `"0102"`, err: true},
+		{typ: "bytes4", value: `"0x01020304"`, want: [4]byte{1, 2, 3, 4}},
+		{typ: "bytes4", value: `"0x0102"`, want: [4]byte{1, 2}},
+		{typ: "bytes4", value: `"0x0102030405"`, err: true},
+
+		// arrays have their exact size, slices any length
+		{typ: "uint8[2]", value: `[1, "0x02"]`, want: [2]uint8{1, 2}},
+		{typ: "uint8[2]", value: `[1]`, err: true},
+		{typ: "uint8[2]", value: `[1, 256]`, err: true},
+		{typ: "uint8[]", value: `[1, 2, 3]`, want: []uint8{1, 2, 3}},
+		{typ: "uint8[]", value: `[]`, want: []uint8{}},
+		{typ: "uint8[]", value: `1`, err: true},
+		{typ: "address[]", value: `["0x` + strings.Repeat("0", 39) + `1"]`, want: []crypto.CommonAddress{addr}},
+	}
+	for _, test := range tests {
+		typ, err := NewType(test.typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args, err := Arguments{{Type: typ}}.ParseJSON([]json.RawMessage{json.RawMessage(test.value)})
+		if test.err {
+			if err == nil {
+				t.Errorf("%s %s: expect error, got %v", test.typ, test.value, args[0])
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %s: %v", test.typ, test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(args[0], test.want) {
+			t.Errorf("%s %s: parsed %#v, want %#v", test.typ, test.value, args[0], test.want)
+		}
+	}
+}
+
+func TestParseJSONCount(t *testing.T) {
+	typ, _ := NewType("uint256")
+	if _, err := (Arguments{{Type: typ}}).ParseJSON(nil); err == nil {
+		t.Error("missing argument accepted")
+	}
+	if _, err := (Arguments{{Type: typ}}).ParseJSON([]json.RawMessage{[]byte(`1`), []byte(`2`)}); err == nil {
+		t.Error("extra argument accepted")
+	}
+}
+
+func TestPackConstructor(t *testing.T) {
+	word := func(hex string) string {
+		return strings.Repeat("0", 64-len(hex)) + hex
+	}
+	tests := []struct {
+		abi    string
+		values string
+		want   string
+	}{
+		{
+			abi:    `[{"type":"constructor","inputs":[{"name":"value","type":"uint256"},{"name":"owner","type":"address"}]}]`,
+			values: `[100, "0x3ebcbe7cb440dd8c52940a2963472380afbb56c5"]`,
+			want:   word("64") + word("3ebcbe7cb440dd8c52940a2963472380afbb56c5"),
+		},
+		{
+			abi:    `[{"type":"constructor","inputs":[{"name":"value","type":"int16"},{"name":"flag","type":"bool"}]}]`,
+			values: `["-2", true]`,
+			want:   strings.Repeat("f", 60) + "fffe" + word("1"),
+		},
+		{
+			abi:    `[{"type":"constructor","inputs":[{"name":"name","type":"string"},{"name":"pair","type":"uint8[2]"}]}]`,
+			values: `["drep", [1, 2]]`,
+			want:   word("60") + word("1") + word("2") + word("4") + "64726570" + strings.Repeat("0", 56),
+		},
+		{
+			abi:    `[{"type":"constructor","inputs":[{"name":"ids","type":"uint256[]"},{"name":"tag","type":"bytes4"}]}]`,
+			values: `[["0x1", "2"], "0x01020304"]`,
+			want:   word("40") + "01020304" + strings.Repeat("0", 56) + word("2") + word("1") + word("2"),
+		},
+	}
+	for i, test := range tests {
+		abi, err := JSON(strings.NewReader(test.abi))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var values []json.RawMessage
+		if err := json.Unmarshal([]byte(test.values), &values); err != nil {
+			t.Fatal(err)
+		}
+		packed, err := abi.PackConstructor(values)
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if want := common.Hex2Bytes(test.want); !bytes.Equal(packed, want) {
+			t.Errorf("%d: packed %x, want %x", i, packed, want)
+		}
+	}
+}
@@ -27,14 +27,17 @@ func (e Event) String() string {
 	return fmt.Sprintf("e %v(%v)", e.Name, strings.Join(inputs, ", "))
 }
 
-// Id returns the canonical representation of the event's signature used by the
-// abi definition to identify event names and types.
-func (e Event) Id() crypto.Hash {
+// Sig returns the event string signature according to the ABI spec.
+func (e Event) Sig() string {
 	types := make([]string, len(e.Inputs))
-	i := 0
-	for _, input := range e.Inputs {
+	for i, input := range e.Inputs {
 		types[i] = input.Type.String()
-		i++
 	}
-	return crypto.Bytes2Hash(sha3.Keccak256(([]byte(fmt.Sprintf("%v(%v)", e.Name, strings.Join(types, ","))))))
+	return fmt.Sprintf("%v(%v)", e.Name, strings.Join(types, ","))
+}
+
+// Id returns the canonical representation of the event's signature used by the
+// abi definition to identify event names and types.
+func (e Event) Id() crypto.Hash {
+	return crypto.Bytes2Hash(sha3.Keccak256([]byte(e.Sig())))
 }
@@ -0,0 +1,59 @@
+package evm
+
+import (
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+/*
+name: 虚拟机接口
+usage: 管理合约abi，注册后查询的日志、收据和合约调用交易会附带解码结果，并校验部署的合约代码
+prefix: vm
+*/
+type EvmApi struct {
+	evmService *EvmService
+}
+
+/*
+ name: registerAbi
+ usage: 为已部署的合约地址注册json格式的abi，保存在本节点数据库中，重复注册会覆盖之前的abi，abi不超过128KB
+ params:
+	1. 合约地址
+	2. json格式的abi
+ return: 无
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"vm_registerAbi","params":["0x1ad3b7f2b5e08ef43d7c9a8f3a64c74ca3d7c3b1","[{\"type\":\"event\",\"name\":\"Transfer\",\"inputs\":[{\"name\":\"from\",\"type\":\"address\",\"indexed\":true},{\"name\":\"to\",\"type\":\"address\",\"indexed\":true},{\"name\":\"value\",\"type\":\"uint256\",\"indexed\":false}]}]"], "id": 3}' -H "Content-Type:application/json"
+ response:
+   {"jsonrpc":"2.0","id":3,"result":null}
+*/
+func (api *EvmApi) RegisterAbi(addr crypto.CommonAddress, abiJson string) error {
+	return api.evmService.RegisterAbi(addr, abiJson)
+}
+
+/*
+ name: getAbi
+ usage: 查询合约地址注册的abi
+ params:
+	1. 合约地址
+ return: json格式的abi
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"vm_getAbi","params":["0x1ad3b7f2b5e08ef43d7c9a8f3a64c74ca3d7c3b1"], "id": 3}' -H "Content-Type:application/json"
+ response:
+   {"jsonrpc":"2.0","id":3,"result":"[{\"type\":\"event\",\"name\":\"Transfer\",\"inputs\":[{\"name\":\"from\",\"type\":\"address\",\"indexed\":true},{\"name\":\"to\",\"type\":\"address\",\"indexed\":true},{\"name\":\"value\",\"type\":\"uint256\",\"indexed\":false}]}]"}
+*/
+func (api *EvmApi) GetAbi(addr crypto.CommonAddress) (string, error) {
+	return api.evmService.GetAbi(addr)
+}
+
+/*
+ name: verifyCode
+ usage: 校验运行时字节码是否与合约地址上部署的代码一致，忽略solc附加的元数据时一致即为match，包括元数据一致为exactMatch
+ params:
+	1. 合约地址
+	2. 编译得到的运行时字节码
+ return: 校验结果及部署代码的元数据hash
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"vm_verifyCode","params":["0x1ad3b7f2b5e08ef43d7c9a8f3a64c74ca3d7c3b1","0x6080604052600080fdfea165627a7a723058204b651e4313ab6bc4eda61084cac1f805699cefbb979ddfd3a2d7f970903307cd0029"], "id": 3}' -H "Content-Type:application/json"
+ response:
+   {"jsonrpc":"2.0","id":3,"result":{"match":true,"exactMatch":true,"metadataHash":"0x4b651e4313ab6bc4eda61084cac1f805699cefbb979ddfd3a2d7f970903307cd"}}
+*/
+func (api *EvmApi) VerifyCode(addr crypto.CommonAddress, code common.Bytes) (*CodeVerification, error) {
+	return api.evmService.VerifyCode(addr, code)
+}
@@ -0,0 +1,125 @@
+package evm
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/pkgs/evm/abi"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxAbiSize is the size limit of a registered json abi
+	MaxAbiSize = 128 * 1024
+	// maxCachedAbis is the number of parsed abis kept in memory
+	maxCachedAbis = 256
+)
+
+// abiCache keeps the parsed abi of the contracts looked up recently, an arbitrary abi is evicted
+// once it is full
+type abiCache struct {
+	lock sync.RWMutex
+	abis map[crypto.CommonAddress]*abi.ABI
+}
+
+func (cache *abiCache) get(addr crypto.CommonAddress) (*abi.ABI, bool) {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	contractAbi, ok := cache.abis[addr]
+	return contractAbi, ok
+}
+
+func (cache *abiCache) add(addr crypto.CommonAddress, contractAbi *abi.ABI) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if _, ok := cache.abis[addr]; !ok && len(cache.abis) >= maxCachedAbis {
+		for evicted := range cache.abis {
+			delete(cache.abis, evicted)
+			break
+		}
+	}
+	cache.abis[addr] = contractAbi
+}
+
+// RegisterAbi store the json abi of a contract in the node database, it is used to decode the logs
+// emitted by the contract and the input of the calls to it. Only deployed contracts have an abi,
+// registering again replaces it.
+func (evmService *EvmService) RegisterAbi(addr crypto.CommonAddress, abiJson string) error {
+	if len(abiJson) > MaxAbiSize {
+		return errors.Wrapf(ErrAbiTooLarge, "%d bytes", len(abiJson))
+	}
+	if len(evmService.DatabaseService.GetByteCode(&addr)) == 0 {
+		return errors.Wrapf(ErrNoContractCode, "%s", addr.String())
+	}
+	contractAbi, err := abi.JSON(bytes.NewReader([]byte(abiJson)))
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAbi, "%s", err.Error())
+	}
+	if err := evmService.DatabaseService.PutContractAbi(&addr, []byte(abiJson)); err != nil {
+		return err
+	}
+	evmService.abis.add(addr, &contractAbi)
+	return nil
+}
+
+// GetAbi return the json abi registered for a contract
+func (evmService *EvmService) GetAbi(addr crypto.CommonAddress) (string, error) {
+	abiJson, err := evmService.DatabaseService.GetContractAbi(&addr)
+	if err != nil {
+		return "", errors.Wrapf(ErrAbiNotFound, "%s", addr.String())
+	}
+	return string(abiJson), nil
+}
+
+// DecodeLogs fill the decoded event of the logs emitted by contracts with a registered abi, logs
+// which can not be decoded are left untouched
+func (evmService *EvmService) DecodeLogs(logs []*types.Log) {
+	for _, log := range logs {
+		contractAbi := evmService.contractAbi(&log.Address)
+		if contractAbi == nil {
+			continue
+		}
+		event, err := contractAbi.DecodeLog(log.Topics, log.Data)
+		if err != nil {
+			continue
+		}
+		log.Event = event
+	}
+}
+
+// DecodeInput decodes the input of a call to a contract with a registered abi, nil is returned for
+// other transactions or input not matching the abi
+func (evmService *EvmService) DecodeInput(tx *types.Transaction) *types.DecodedCall {
+	if tx.Type() != types.CallContractType || tx.To() == nil {
+		return nil
+	}
+	contractAbi := evmService.contractAbi(tx.To())
+	if contractAbi == nil {
+		return nil
+	}
+	call, err := contractAbi.DecodeInput(tx.Data.Data)
+	if err != nil {
+		return nil
+	}
+	return call
+}
+
+func (evmService *EvmService) contractAbi(addr *crypto.CommonAddress) *abi.ABI {
+	if contractAbi, ok := evmService.abis.get(*addr); ok {
+		return contractAbi
+	}
+
+	abiJson, err := evmService.DatabaseService.GetContractAbi(addr)
+	if err != nil {
+		return nil
+	}
+	parsed, err := abi.JSON(bytes.NewReader(abiJson))
+	if err != nil {
+		return nil
+	}
+
+	evmService.abis.add(*addr, &parsed)
+	return &parsed
+}
@@ -0,0 +1,12 @@
+package evm
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidAbi     = errors.New("invalid contract abi")
+	ErrAbiNotFound    = errors.New("contract abi not registered")
+	ErrNoContractCode = errors.New("no contract code at address")
+	ErrAbiTooLarge    = errors.New("contract abi too large")
+)
@@ -10,4 +10,6 @@ import (
 type Vm interface {
 	app.Service
 	Eval(vm.VMState, *types.Transaction, *types.BlockHeader, ChainContext, uint64, *big.Int) (ret []byte, gasUsed uint64, failed bool, err error)
+	DecodeLogs(logs []*types.Log)
+	DecodeInput(tx *types.Transaction) *types.DecodedCall
 }
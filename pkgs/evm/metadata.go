@@ -0,0 +1,45 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// metadataKeys are the cbor keys of the metadata file hash appended by solc, swarm for the
+// older compilers and ipfs since solc 0.6
+var metadataKeys = [][]byte{[]byte("bzzr0"), []byte("bzzr1"), []byte("ipfs")}
+
+// SplitMetadata splits the cbor metadata appended by solc from the end of the bytecode, the
+// metadata is empty for bytecode not compiled by solc. The last 2 bytes of the bytecode are the
+// big endian length of the cbor map before them.
+func SplitMetadata(code []byte) (body []byte, metadata []byte) {
+	if len(code) < 2 {
+		return code, nil
+	}
+	length := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	start := len(code) - 2 - length
+	// cbor maps with up to 23 entries start with 0xa0 + size
+	if length == 0 || start < 0 || code[start] < 0xa1 || code[start] > 0xb7 {
+		return code, nil
+	}
+	return code[:start], code[start:]
+}
+
+// MetadataHash returns the hash of the metadata file found in the solc metadata of the bytecode,
+// nil if the bytecode has none
+func MetadataHash(code []byte) []byte {
+	_, metadata := SplitMetadata(code)
+	for _, key := range metadataKeys {
+		// keys are cbor text strings of major type 3 and hashes byte strings of major type 2
+		index := bytes.Index(metadata, append([]byte{0x60 + byte(len(key))}, key...))
+		if index < 0 {
+			continue
+		}
+		value := metadata[index+1+len(key):]
+		if len(value) < 2 || value[0] != 0x58 || len(value) < 2+int(value[1]) {
+			return nil
+		}
+		return value[2 : 2+int(value[1])]
+	}
+	return nil
+}
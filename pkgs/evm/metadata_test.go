@@ -0,0 +1,31 @@
+package evm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/common"
+)
+
+func TestSplitMetadata(t *testing.T) {
+	body := common.FromHex("0x6080604052600080fdfe")
+	hash := common.FromHex("0x4b651e4313ab6bc4eda61084cac1f805699cefbb979ddfd3a2d7f970903307cd")
+	metadata := append(append(common.FromHex("0xa165627a7a72305820"), hash...), 0x00, 0x29)
+
+	code := append(append([]byte{}, body...), metadata...)
+	gotBody, gotMetadata := SplitMetadata(code)
+	if !bytes.Equal(gotBody, body) || !bytes.Equal(gotMetadata, metadata) {
+		t.Fatalf("split %x into %x and %x", code, gotBody, gotMetadata)
+	}
+	if got := MetadataHash(code); !bytes.Equal(got, hash) {
+		t.Errorf("metadata hash %x, want %x", got, hash)
+	}
+
+	// bytecode not compiled by solc has no metadata
+	if gotBody, gotMetadata := SplitMetadata(body); !bytes.Equal(gotBody, body) || gotMetadata != nil {
+		t.Errorf("split metadata %x from %x", gotMetadata, body)
+	}
+	if got := MetadataHash(body); got != nil {
+		t.Errorf("metadata hash %x of code without metadata", got)
+	}
+}
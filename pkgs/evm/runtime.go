@@ -4,7 +4,9 @@ import (
 	"github.com/AsynkronIT/protoactor-go/actor"
 	"github.com/drep-project/dlog"
 	"github.com/drep-project/DREP-Chain/app"
+	"github.com/drep-project/DREP-Chain/crypto"
 	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/pkgs/evm/abi"
 	"github.com/drep-project/DREP-Chain/pkgs/evm/vm"
 	"github.com/drep-project/DREP-Chain/types"
 	"gopkg.in/urfave/cli.v1"
@@ -26,6 +28,8 @@ var (
 type EvmService struct {
 	Config          *vm.VMConfig
 	DatabaseService *database.DatabaseService `service:"database"`
+	abis            abiCache
+	apis            []app.API
 }
 
 func (evmService *EvmService) Name() string {
@@ -33,7 +37,7 @@ func (evmService *EvmService) Name() string {
 }
 
 func (evmService *EvmService) Api() []app.API {
-	return []app.API{}
+	return evmService.apis
 }
 
 func (evmService *EvmService) CommandFlags() ([]cli.Command, []cli.Flag) {
@@ -50,6 +54,17 @@ func (evmService *EvmService) Init(executeContext *app.ExecuteContext) error {
 	if err != nil {
 		return err
 	}
+
+	evmService.abis.abis = make(map[crypto.CommonAddress]*abi.ABI)
+	evmService.apis = []app.API{
+		app.API{
+			Namespace: evmService.Name(),
+			Version:   "1.0",
+			Service:   &EvmApi{evmService},
+			// registerAbi writes to the node database
+			Public: false,
+		},
+	}
 	return nil
 }
 
@@ -75,6 +90,11 @@ func (evmService *EvmService) Eval(state vm.VMState, tx *types.Transaction, head
 	// Create a new environment which holds all relevant information
 	// about the transaction and calling mechanisms.
 	vmenv := vm.NewEVM(context, state, evmService.Config)
+	if contractCreation {
+		vmenv.PrepareAccessList(*sender, nil)
+	} else {
+		vmenv.PrepareAccessList(*sender, tx.To())
+	}
 	var (
 		// vm errors do not effect consensus and are therefor
 		// not assigned to err, except for insufficient balance
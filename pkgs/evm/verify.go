@@ -0,0 +1,38 @@
+package evm
+
+import (
+	"bytes"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/pkg/errors"
+)
+
+// CodeVerification is the result of checking a runtime bytecode against the code deployed at an address
+type CodeVerification struct {
+	Match        bool         `json:"match"`        // the code matches the deployed code apart from the solc metadata
+	ExactMatch   bool         `json:"exactMatch"`   // the code matches the deployed code with the solc metadata
+	MetadataHash common.Bytes `json:"metadataHash"` // the metadata hash of the deployed code
+}
+
+// VerifyCode checks that the runtime bytecode compiled from a source is the code deployed at addr.
+// The solc metadata only depends on the source files and compiler settings, the code without it
+// may match while the metadata differs.
+func (evmService *EvmService) VerifyCode(addr crypto.CommonAddress, code []byte) (*CodeVerification, error) {
+	deployed := evmService.DatabaseService.GetByteCode(&addr)
+	if len(deployed) == 0 {
+		return nil, errors.Wrapf(ErrNoContractCode, "%s", addr.String())
+	}
+	body, _ := SplitMetadata(code)
+	deployedBody, _ := SplitMetadata(deployed)
+
+	metadataHash, err := evmService.DatabaseService.GetMetadataHash(&addr)
+	if err != nil {
+		metadataHash = MetadataHash(deployed)
+	}
+	return &CodeVerification{
+		Match:        bytes.Equal(body, deployedBody),
+		ExactMatch:   bytes.Equal(code, deployed),
+		MetadataHash: metadataHash,
+	}, nil
+}
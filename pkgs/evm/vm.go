@@ -18,6 +18,7 @@ package evm
 
 import (
 	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/params"
 	"github.com/drep-project/DREP-Chain/pkgs/evm/vm"
 	"github.com/drep-project/DREP-Chain/types"
 	"math/big"
@@ -28,6 +29,10 @@ import (
 type ChainContext interface {
 	// GetHeader returns the hash corresponding to their hash.
 	GetHeader(crypto.Hash, uint64) *types.BlockHeader
+	// ForkConfig returns the activation heights of the evm rule sets
+	ForkConfig() *params.ForkConfig
+	// Producers returns the addresses of the block producers
+	Producers() []crypto.CommonAddress
 }
 
 // NewEVMContext creates a new context for use in the EVM.
@@ -41,6 +46,9 @@ func NewEVMContext(msg *types.Transaction, header *types.BlockHeader, sender *cr
 		GasLimit:    header.GasLimit.Uint64(),
 		GasPrice:    new(big.Int).Set(msg.GasPrice()),
 		TxHash:      msg.TxHash(),
+		ChainID:     header.ChainId,
+		Rules:       chain.ForkConfig().Rules(header.Height),
+		Producers:   chain.Producers(),
 	}
 }
 
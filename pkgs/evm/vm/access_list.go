@@ -0,0 +1,85 @@
+package vm
+
+import (
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+// storageSlot is one storage slot of a contract
+type storageSlot struct {
+	addr crypto.CommonAddress
+	slot crypto.Hash
+}
+
+// accessEntry is one warmed address or slot in the journal of the access list
+type accessEntry struct {
+	storageSlot
+	isSlot bool
+}
+
+// accessList tracks the addresses and storage slots accessed by a transaction under the eip-2929
+// rules. A nil access list treats everything as warm, it is only created for berlin blocks.
+// Warmed entries are journaled so that the call frames reverting their state cool them down again.
+type accessList struct {
+	addresses map[crypto.CommonAddress]struct{}
+	slots     map[storageSlot]struct{}
+	journal   []accessEntry
+}
+
+func newAccessList() *accessList {
+	return &accessList{
+		addresses: make(map[crypto.CommonAddress]struct{}),
+		slots:     make(map[storageSlot]struct{}),
+	}
+}
+
+// addAddress warms addr, returns whether addr was cold
+func (al *accessList) addAddress(addr crypto.CommonAddress) bool {
+	if al == nil {
+		return false
+	}
+	if _, ok := al.addresses[addr]; ok {
+		return false
+	}
+	al.addresses[addr] = struct{}{}
+	al.journal = append(al.journal, accessEntry{storageSlot: storageSlot{addr: addr}})
+	return true
+}
+
+// addSlot warms the slot of addr together with addr, returns whether the slot was cold
+func (al *accessList) addSlot(addr crypto.CommonAddress, slot crypto.Hash) bool {
+	if al == nil {
+		return false
+	}
+	al.addAddress(addr)
+	key := storageSlot{addr: addr, slot: slot}
+	if _, ok := al.slots[key]; ok {
+		return false
+	}
+	al.slots[key] = struct{}{}
+	al.journal = append(al.journal, accessEntry{storageSlot: key, isSlot: true})
+	return true
+}
+
+// snapshot returns an identifier of the current warm entries
+func (al *accessList) snapshot() int {
+	if al == nil {
+		return 0
+	}
+	return len(al.journal)
+}
+
+// revertToSnapshot cools down the entries warmed after the snapshot id was taken
+func (al *accessList) revertToSnapshot(id int) {
+	if al == nil {
+		return
+	}
+	for i := len(al.journal) - 1; i >= id; i-- {
+		entry := al.journal[i]
+		if entry.isSlot {
+			delete(al.slots, entry.storageSlot)
+		} else {
+			delete(al.addresses, entry.addr)
+		}
+	}
+	al.journal = al.journal[:id]
+}
@@ -0,0 +1,172 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
+	"github.com/drep-project/DREP-Chain/params"
+)
+
+var (
+	errProducerIndex = errors.New("block producer index out of range")
+
+	// maxAliasLength is the longest alias accepted by alias transactions
+	maxAliasLength = 20
+)
+
+// DrepPrecompiledContract is a native contract for the drep chain features, it is given the evm
+// running it to read the state and the block
+type DrepPrecompiledContract interface {
+	RequiredGas(input []byte) uint64            // RequiredGas calculates the contract gas use
+	Run(evm *EVM, input []byte) ([]byte, error) // Run runs the precompiled contract
+}
+
+// DrepPrecompiledContracts are activated by the istanbul rules, their arguments and results are
+// 32 bytes words like the ones of solidity abi
+var DrepPrecompiledContracts = map[crypto.CommonAddress]DrepPrecompiledContract{
+	crypto.BytesToAddress([]byte{1, 1}): &aliasResolve{},
+	crypto.BytesToAddress([]byte{1, 2}): &reputationLookup{},
+	crypto.BytesToAddress([]byte{1, 3}): &schnorrVerify{},
+	crypto.BytesToAddress([]byte{1, 4}): &aggregatedSchnorrVerify{},
+	crypto.BytesToAddress([]byte{1, 5}): &producerLookup{},
+}
+
+// drepPrecompile binds a drep native contract to the evm running it
+type drepPrecompile struct {
+	evm      *EVM
+	contract DrepPrecompiledContract
+}
+
+func (p *drepPrecompile) RequiredGas(input []byte) uint64 {
+	return p.contract.RequiredGas(input)
+}
+
+func (p *drepPrecompile) Run(input []byte) ([]byte, error) {
+	return p.contract.Run(p.evm, input)
+}
+
+// precompile returns the native contract at addr under the rules of the block, nil if there is none
+func (evm *EVM) precompile(addr crypto.CommonAddress) PrecompiledContract {
+	if p := PrecompiledContracts[addr]; p != nil {
+		return p
+	}
+	if !evm.Rules.IsIstanbul {
+		return nil
+	}
+	if p := DrepPrecompiledContracts[addr]; p != nil {
+		return &drepPrecompile{evm: evm, contract: p}
+	}
+	return nil
+}
+
+// aliasResolve returns the address of the alias in input, the zero address for an unknown alias
+type aliasResolve struct{}
+
+func (c *aliasResolve) RequiredGas(input []byte) uint64 {
+	return params.AliasResolveGas
+}
+
+func (c *aliasResolve) Run(evm *EVM, input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input) > maxAliasLength {
+		return make([]byte, 32), nil
+	}
+	addr := evm.State.AliasGet(string(input))
+	if addr == nil {
+		return make([]byte, 32), nil
+	}
+	return common.LeftPadBytes(addr.Bytes(), 32), nil
+}
+
+// reputationLookup returns the reputation of the address word in input
+type reputationLookup struct{}
+
+func (c *reputationLookup) RequiredGas(input []byte) uint64 {
+	return params.ReputationLookupGas
+}
+
+func (c *reputationLookup) Run(evm *EVM, input []byte) ([]byte, error) {
+	addr := crypto.BytesToAddress(common.GetData(input, 12, 20))
+	return common.LeftPadBytes(evm.State.GetReputation(&addr).Bytes(), 32), nil
+}
+
+// schnorrVerify checks the schnorr signature of crypto/secp256k1/schnorr, input is the words
+// (hash, r, s, x, y) with x and y the coordinates of the public key. It returns the word 1 for a
+// valid signature and 0 otherwise.
+type schnorrVerify struct{}
+
+func (c *schnorrVerify) RequiredGas(input []byte) uint64 {
+	return params.SchnorrVerifyGas
+}
+
+func (c *schnorrVerify) Run(evm *EVM, input []byte) ([]byte, error) {
+	input = common.RightPadBytes(input, 160)
+	return verifySchnorr(input[:96], input[96:160]), nil
+}
+
+// aggregatedSchnorrVerify checks a schnorr signature made together by several keys like the
+// multi signatures of the bft producers, input is the words (hash, r, s) followed by the
+// coordinates (x, y) of every key. It returns the word 1 for a valid signature and 0 otherwise.
+type aggregatedSchnorrVerify struct{}
+
+func (c *aggregatedSchnorrVerify) RequiredGas(input []byte) uint64 {
+	keys := uint64(0)
+	if len(input) > 96 {
+		keys = uint64(len(input)-96+63) / 64
+	}
+	if keys <= 1 {
+		return params.SchnorrVerifyGas
+	}
+	return params.SchnorrVerifyGas + (keys-1)*params.SchnorrPerKeyGas
+}
+
+func (c *aggregatedSchnorrVerify) Run(evm *EVM, input []byte) ([]byte, error) {
+	if len(input) <= 96 || (len(input)-96)%64 != 0 {
+		return make([]byte, 32), nil
+	}
+	return verifySchnorr(input[:96], input[96:]), nil
+}
+
+// verifySchnorr checks the signature words (hash, r, s) against the sum of the keys words (x, y)
+func verifySchnorr(sig []byte, keys []byte) []byte {
+	curve := secp256k1.S256()
+	pubkeys := []*secp256k1.PublicKey{}
+	for i := 0; i < len(keys); i += 64 {
+		x := new(big.Int).SetBytes(keys[i : i+32])
+		y := new(big.Int).SetBytes(keys[i+32 : i+64])
+		if x.Cmp(curve.P) >= 0 || y.Cmp(curve.P) >= 0 || !curve.IsOnCurve(x, y) {
+			return make([]byte, 32)
+		}
+		pubkeys = append(pubkeys, secp256k1.NewPublicKey(x, y))
+	}
+	// keys summing to infinity make no valid key
+	pubkey := schnorr.CombinePubkeys(pubkeys)
+	r := new(big.Int).SetBytes(sig[32:64])
+	s := new(big.Int).SetBytes(sig[64:96])
+	if pubkey == nil || !schnorr.Verify(pubkey, sig[:32], r, s) {
+		return make([]byte, 32)
+	}
+	return common.LeftPadBytes([]byte{1}, 32)
+}
+
+// producerLookup reads the block producer set of the chain, an empty input returns the number
+// of producers and an index word returns the address of the producer at that index
+type producerLookup struct{}
+
+func (c *producerLookup) RequiredGas(input []byte) uint64 {
+	return params.ProducerLookupGas
+}
+
+func (c *producerLookup) Run(evm *EVM, input []byte) ([]byte, error) {
+	if len(input) == 0 {
+		return common.LeftPadBytes(big.NewInt(int64(len(evm.Producers))).Bytes(), 32), nil
+	}
+	index := new(big.Int).SetBytes(common.GetData(input, 0, 32))
+	if !index.IsUint64() || index.Uint64() >= uint64(len(evm.Producers)) {
+		return nil, errProducerIndex
+	}
+	return common.LeftPadBytes(evm.Producers[index.Uint64()].Bytes(), 32), nil
+}
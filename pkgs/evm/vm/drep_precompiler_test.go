@@ -0,0 +1,70 @@
+package vm
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/DREP-Chain/params"
+)
+
+func TestDrepPrecompileActivation(t *testing.T) {
+	addr := crypto.BytesToAddress([]byte{1, 3})
+	if p := NewEVM(Context{}, nil, &VMConfig{}).precompile(addr); p != nil {
+		t.Error("drep native contract active before istanbul")
+	}
+	if p := NewEVM(Context{Rules: params.Rules{IsIstanbul: true}}, nil, &VMConfig{}).precompile(addr); p == nil {
+		t.Error("drep native contract inactive under istanbul")
+	}
+}
+
+func TestSchnorrVerifyPrecompile(t *testing.T) {
+	priv, err := secp256k1.GeneratePrivateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha3.Keccak256([]byte("drep"))
+	r, s, err := schnorr.Sign(priv, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey := priv.PubKey()
+	input := append(append(append([]byte{}, hash...), common.LeftPadBytes(r.Bytes(), 32)...), common.LeftPadBytes(s.Bytes(), 32)...)
+	input = append(append(input, common.LeftPadBytes(pubkey.GetX().Bytes(), 32)...), common.LeftPadBytes(pubkey.GetY().Bytes(), 32)...)
+
+	for _, contract := range []DrepPrecompiledContract{&schnorrVerify{}, &aggregatedSchnorrVerify{}} {
+		if res, _ := contract.Run(nil, input); new(big.Int).SetBytes(res).Uint64() != 1 {
+			t.Errorf("%T rejected valid signature", contract)
+		}
+		input[0] ^= 1
+		if res, _ := contract.Run(nil, input); new(big.Int).SetBytes(res).Sign() != 0 {
+			t.Errorf("%T accepted signature of another hash", contract)
+		}
+		input[0] ^= 1
+	}
+	if gas := (&aggregatedSchnorrVerify{}).RequiredGas(append(input, input[96:]...)); gas != params.SchnorrVerifyGas+params.SchnorrPerKeyGas {
+		t.Errorf("aggregated verify of 2 keys gas %d", gas)
+	}
+}
+
+func TestProducerLookupPrecompile(t *testing.T) {
+	producers := []crypto.CommonAddress{{1}, {2}}
+	evm := NewEVM(Context{Producers: producers}, nil, &VMConfig{})
+	contract := &producerLookup{}
+
+	if res, _ := contract.Run(evm, nil); new(big.Int).SetBytes(res).Uint64() != 2 {
+		t.Errorf("producer count %x", res)
+	}
+	res, err := contract.Run(evm, common.LeftPadBytes([]byte{1}, 32))
+	if err != nil || crypto.BytesToAddress(res[12:]) != producers[1] {
+		t.Errorf("producer 1 %x, err %v", res, err)
+	}
+	if _, err := contract.Run(evm, common.LeftPadBytes([]byte{2}, 32)); err != errProducerIndex {
+		t.Error("producer index out of range accepted")
+	}
+}
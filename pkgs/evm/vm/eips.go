@@ -0,0 +1,137 @@
+package vm
+
+import (
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+// opChainID implements CHAINID of eip-1344, it pushes the chain id of the block
+func opChainID(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	stack.push(interpreter.IntPool.get().SetUint64(uint64(interpreter.EVM.ChainID)))
+	return nil, nil
+}
+
+// opSelfBalance implements SELFBALANCE of eip-1884, the cheap balance of the executing contract
+func opSelfBalance(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	balance := interpreter.EVM.State.GetBalance(&contract.ContractAddr)
+	stack.push(interpreter.IntPool.get().Set(balance))
+	return nil, nil
+}
+
+// makeGasSStoreFunc returns the SSTORE net gas metering of eip-2200, readGas is charged for no-op
+// and dirty writes and resetGas for the first write of a clean non-zero slot. Under the berlin
+// rules the access list adds the eip-2929 surcharge of a cold slot.
+func makeGasSStoreFunc(readGas, resetGas uint64) gasFunc {
+	return func(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		// the sentry keeps the calls given only the stipend from writing storage
+		if contract.Gas <= SstoreSentryEIP2200 {
+			return 0, errSStoreSentry
+		}
+		var (
+			y, x     = stack.Back(1), stack.Back(0)
+			slot     = crypto.BigToHash(x)
+			value    = crypto.BigToHash(y)
+			current  = crypto.Bytes2Hash(evm.getState(contract, slot))
+			original = evm.originalState(contract.ContractAddr, slot, current)
+			cost     uint64
+		)
+		if evm.accessList.addSlot(contract.ContractAddr, slot) {
+			cost = ColdSLoadEIP2929
+		}
+
+		if current == value { // no-op
+			return cost + readGas, nil
+		}
+		if original == current {
+			if original == (crypto.Hash{}) { // create slot
+				return cost + SstoreSetEIP2200, nil
+			}
+			if value == (crypto.Hash{}) { // delete slot
+				evm.State.AddRefund(SstoreClearsScheduleRefundEIP2200)
+			}
+			return cost + resetGas, nil // write clean slot
+		}
+		// the slot is dirty, the refunds follow the value back and forth from zero
+		if original != (crypto.Hash{}) {
+			if current == (crypto.Hash{}) { // recreate slot
+				evm.State.SubRefund(SstoreClearsScheduleRefundEIP2200)
+			} else if value == (crypto.Hash{}) { // delete slot
+				evm.State.AddRefund(SstoreClearsScheduleRefundEIP2200)
+			}
+		}
+		if original == value {
+			if original == (crypto.Hash{}) { // reset to original zero
+				evm.State.AddRefund(SstoreSetEIP2200 - readGas)
+			} else { // reset to original non-zero
+				evm.State.AddRefund(resetGas - readGas)
+			}
+		}
+		return cost + readGas, nil
+	}
+}
+
+// accessAddressGas warms addr and returns the eip-2929 cost of accessing it
+func accessAddressGas(evm *EVM, addr crypto.CommonAddress) uint64 {
+	if evm.accessList.addAddress(addr) {
+		return ColdAccountAccessEIP2929
+	}
+	return WarmStorageReadEIP2929
+}
+
+func gasSLoadEIP2929(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	if evm.accessList.addSlot(contract.ContractAddr, crypto.BigToHash(stack.Back(0))) {
+		return ColdSLoadEIP2929, nil
+	}
+	return WarmStorageReadEIP2929, nil
+}
+
+// gasAccountAccessEIP2929 is the gas of BALANCE, EXTCODESIZE and EXTCODEHASH
+func gasAccountAccessEIP2929(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	return accessAddressGas(evm, crypto.BigToAddress(stack.Back(0))), nil
+}
+
+func gasExtCodeCopyEIP2929(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	gas, err := gasExtCodeCopy(evm, contract, stack, mem, memorySize)
+	if err != nil {
+		return 0, err
+	}
+	var overflow bool
+	if gas, overflow = common.SafeAdd(gas-ExtcodeCopy, accessAddressGas(evm, crypto.BigToAddress(stack.Back(0)))); overflow {
+		return 0, errGasUintOverflow
+	}
+	return gas, nil
+}
+
+// makeCallGasEIP2929 replaces the Calls base cost charged by the inner call gas function with the access cost of
+// the callee. The base cost is swapped before the inner function runs so that the callee is
+// given 63/64 of the gas left after the access cost.
+func makeCallGasEIP2929(inner gasFunc) gasFunc {
+	return func(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		accessGas := accessAddressGas(evm, crypto.BigToAddress(stack.Back(1)))
+		if contract.Gas+Calls < accessGas {
+			return 0, ErrOutOfGas
+		}
+		contract.Gas = contract.Gas + Calls - accessGas
+		gas, err := inner(evm, contract, stack, mem, memorySize)
+		contract.Gas = contract.Gas + accessGas - Calls
+		if err != nil {
+			return 0, err
+		}
+		var overflow bool
+		if gas, overflow = common.SafeAdd(gas-Calls, accessGas); overflow {
+			return 0, errGasUintOverflow
+		}
+		return gas, nil
+	}
+}
+
+func gasSuicideEIP2929(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	gas, err := gasSuicide(evm, contract, stack, mem, memorySize)
+	if err != nil {
+		return 0, err
+	}
+	if evm.accessList.addAddress(crypto.BigToAddress(stack.Back(0))) {
+		gas += ColdAccountAccessEIP2929
+	}
+	return gas, nil
+}
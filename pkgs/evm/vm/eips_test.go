@@ -0,0 +1,153 @@
+package vm
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/params"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+func TestForkInstructionSet(t *testing.T) {
+	istanbul, berlin := uint64(100), uint64(200)
+	forks := &params.ForkConfig{IstanbulHeight: &istanbul, BerlinHeight: &berlin}
+
+	tests := []struct {
+		height   uint64
+		chainId  bool
+		sloadGas uint64
+	}{
+		{99, false, SLoad},
+		{100, true, SLoadEIP1884},
+		{200, true, ColdSLoadEIP2929},
+	}
+	for _, test := range tests {
+		env := NewEVM(Context{Rules: forks.Rules(test.height)}, nil, &VMConfig{})
+		jumpTable := env.interpreter.JumpTable
+		if jumpTable[CHAINID].valid != test.chainId || jumpTable[SELFBALANCE].valid != test.chainId {
+			t.Errorf("height %d: CHAINID valid %v, want %v", test.height, jumpTable[CHAINID].valid, test.chainId)
+		}
+		contract := &Contract{}
+		stack := newstack()
+		stack.push(bigZero)
+		gas, err := jumpTable[SLOAD].gasCost(env, contract, stack, nil, 0)
+		if err != nil || gas != test.sloadGas {
+			t.Errorf("height %d: SLOAD gas %d, want %d", test.height, gas, test.sloadGas)
+		}
+	}
+
+	// blocks of chains without forks keep the constantinople rules
+	var noForks *params.ForkConfig
+	if env := NewEVM(Context{Rules: noForks.Rules(1000000)}, nil, &VMConfig{}); env.interpreter.JumpTable[CHAINID].valid {
+		t.Error("CHAINID enabled without fork config")
+	}
+	if err := (&params.ForkConfig{BerlinHeight: &berlin}).Validate(); err != params.ErrForkOrder {
+		t.Error("berlin before istanbul accepted", err)
+	}
+}
+
+func TestOpChainID(t *testing.T) {
+	env := NewEVM(Context{ChainID: types.ChainIdType(7)}, nil, &VMConfig{})
+	env.interpreter.IntPool = poolOfIntPools.get()
+	stack := newstack()
+	pc := uint64(0)
+	opChainID(&pc, env.interpreter, nil, nil, stack)
+	if chainId := stack.pop(); chainId.Uint64() != 7 {
+		t.Errorf("CHAINID pushed %v, want 7", chainId)
+	}
+}
+
+func TestAccessListRevert(t *testing.T) {
+	al := newAccessList()
+	addr, slot := crypto.CommonAddress{1}, crypto.Hash{2}
+	if !al.addAddress(addr) || al.addAddress(addr) {
+		t.Fatal("address should be cold only once")
+	}
+	snapshot := al.snapshot()
+	if !al.addSlot(addr, slot) || al.addSlot(addr, slot) {
+		t.Fatal("slot should be cold only once")
+	}
+	al.revertToSnapshot(snapshot)
+	if !al.addSlot(addr, slot) {
+		t.Error("reverted slot should be cold")
+	}
+	if al.addAddress(addr) {
+		t.Error("address warmed before the snapshot should stay warm")
+	}
+
+	// a nil access list treats everything as warm
+	var nilList *accessList
+	if nilList.addAddress(addr) || nilList.addSlot(addr, slot) {
+		t.Error("nil access list reported a cold access")
+	}
+}
+
+func TestSStoreGas(t *testing.T) {
+	defer os.RemoveAll("./test")
+	diskDb, err := database.NewDatabase("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer diskDb.Close()
+
+	istanbul := params.Rules{IsIstanbul: true, IsStorageTrie: true}
+	berlin := params.Rules{IsIstanbul: true, IsBerlin: true, IsStorageTrie: true}
+	tests := []struct {
+		rules    params.Rules
+		original int64   // value of the slot before the transaction
+		values   []int64 // values written one after the other
+		gas      uint64  // gas of the last write
+		refund   uint64
+	}{
+		{istanbul, 0, []int64{0}, SLoadEIP1884, 0},
+		{istanbul, 0, []int64{1}, SstoreSetEIP2200, 0},
+		{istanbul, 1, []int64{2}, SstoreResetEIP2200, 0},
+		{istanbul, 1, []int64{0}, SstoreResetEIP2200, SstoreClearsScheduleRefundEIP2200},
+		{istanbul, 0, []int64{1, 2}, SLoadEIP1884, 0},
+		{istanbul, 0, []int64{1, 0}, SLoadEIP1884, SstoreSetEIP2200 - SLoadEIP1884},
+		{istanbul, 1, []int64{0, 1}, SLoadEIP1884, SstoreResetEIP2200 - SLoadEIP1884},
+		{berlin, 0, []int64{1}, ColdSLoadEIP2929 + SstoreSetEIP2200, 0},
+		{berlin, 1, []int64{2}, ColdSLoadEIP2929 + SstoreResetEIP2200 - ColdSLoadEIP2929, 0},
+		{berlin, 0, []int64{1, 2}, WarmStorageReadEIP2929, 0},
+		{berlin, 1, []int64{0, 1}, WarmStorageReadEIP2929, SstoreResetEIP2200 - ColdSLoadEIP2929 - WarmStorageReadEIP2929},
+	}
+	addr := crypto.CommonAddress{1}
+	for i, test := range tests {
+		s := NewState(diskDb.BeginTransaction(true))
+		if err := s.SetByteCode(&addr, []byte{0x00}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetState(&addr, crypto.Hash{}, big.NewInt(test.original).Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		env := NewEVM(Context{Rules: test.rules}, s, &VMConfig{})
+		contract := &Contract{ContractAddr: addr, Gas: 1000000}
+		var gas uint64
+		for _, value := range test.values {
+			stack := newstack()
+			stack.push(big.NewInt(value))
+			stack.push(new(big.Int))
+			if gas, err = env.interpreter.JumpTable[SSTORE].gasCost(env, contract, stack, nil, 0); err != nil {
+				t.Fatalf("test %d: %v", i, err)
+			}
+			s.SetState(&addr, crypto.Hash{}, big.NewInt(value).Bytes())
+		}
+		if gas != test.gas || s.GetRefund() != test.refund {
+			t.Errorf("test %d: gas %d refund %d, want %d %d", i, gas, s.GetRefund(), test.gas, test.refund)
+		}
+	}
+
+	// the calls given only the stipend can not write storage
+	s := NewState(diskDb.BeginTransaction(true))
+	env := NewEVM(Context{Rules: istanbul}, s, &VMConfig{})
+	stack := newstack()
+	stack.push(big.NewInt(1))
+	stack.push(new(big.Int))
+	contract := &Contract{ContractAddr: addr, Gas: params.CallStipend}
+	if _, err := env.interpreter.JumpTable[SSTORE].gasCost(env, contract, stack, nil, 0); err != errSStoreSentry {
+		t.Errorf("SSTORE with the call stipend: %v, want %v", err, errSStoreSentry)
+	}
+}
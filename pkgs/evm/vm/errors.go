@@ -36,6 +36,7 @@ var (
 	errReturnDataOutOfBounds = errors.New("evm: return data out of bounds")
 	errExecutionReverted     = errors.New("evm: execution reverted")
 	errMaxCodeSizeExceeded   = errors.New("evm: max code size exceeded")
+	errSStoreSentry          = errors.New("evm: not enough gas for reentrancy sentry")
 
 	ErrNotAccountAddress    = errors.New("a non account address occupied")
 	ErrAccountAlreadyExists = errors.New("account already exists")
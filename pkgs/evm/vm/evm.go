@@ -19,6 +19,7 @@ package vm
 import (
 	"fmt"
 	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/database"
 	"github.com/drep-project/DREP-Chain/params"
 	"github.com/drep-project/DREP-Chain/types"
 	"math/big"
@@ -43,9 +44,8 @@ type (
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
 func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error) {
 	if !contract.ContractAddr.IsEmpty() {
-		precompiles := PrecompiledContracts
 		//原生合约
-		if p := precompiles[contract.ContractAddr]; p != nil {
+		if p := evm.precompile(contract.ContractAddr); p != nil {
 			return RunPrecompiledContract(p, input, contract)
 		}
 	}
@@ -91,6 +91,11 @@ type Context struct {
 	BlockNumber *big.Int // Provides information for NUMBER
 	Time        *big.Int // Provides information for TIME
 	TxHash      *crypto.Hash
+
+	// Chain information
+	ChainID   types.ChainIdType      // Provides information for CHAINID
+	Rules     params.Rules           // Rule set of the block, selects the instruction set
+	Producers []crypto.CommonAddress // Block producer set read by the producer native contract
 }
 
 // EVM is the Ethereum Virtual Machine base object and provides
@@ -125,6 +130,12 @@ type EVM struct {
 	CallGasTemp uint64
 
 	ChainId types.ChainIdType
+
+	// accessList holds the accounts and slots warmed by the transaction, nil before berlin
+	accessList *accessList
+	// originStorage holds the values of the slots written by the transaction at its start,
+	// used by the SSTORE net gas metering
+	originStorage map[storageSlot]crypto.Hash
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -135,12 +146,91 @@ func NewEVM(ctx Context, statedb VMState, vmConfig *VMConfig) *EVM {
 		State:    statedb,
 		vmConfig: vmConfig,
 	}
+	if ctx.Rules.IsBerlin {
+		evm.accessList = newAccessList()
+	}
 
 	evm.interpreter = NewEVMInterpreter(evm)
 
 	return evm
 }
 
+// PrepareAccessList warms the sender, the destination and the precompiled contracts before a
+// transaction is executed under the berlin rules, dest is nil for contract creation
+func (evm *EVM) PrepareAccessList(sender crypto.CommonAddress, dest *crypto.CommonAddress) {
+	if evm.accessList == nil {
+		return
+	}
+	evm.accessList.addAddress(sender)
+	if dest != nil {
+		evm.accessList.addAddress(*dest)
+	}
+	for addr := range PrecompiledContracts {
+		evm.accessList.addAddress(addr)
+	}
+	for addr := range DrepPrecompiledContracts {
+		evm.accessList.addAddress(addr)
+	}
+}
+
+// originalState returns the value of the slot at the start of the transaction, current is recorded
+// as the original value the first time the slot is met as every write is metered before it happens
+func (evm *EVM) originalState(addr crypto.CommonAddress, slot crypto.Hash, current crypto.Hash) crypto.Hash {
+	if evm.originStorage == nil {
+		evm.originStorage = make(map[storageSlot]crypto.Hash)
+	}
+	key := storageSlot{addr: addr, slot: slot}
+	if original, ok := evm.originStorage[key]; ok {
+		return original
+	}
+	evm.originStorage[key] = current
+	return current
+}
+
+// getState returns the value of slot in the storage of contract. Below the storage trie fork the
+// slots of every contract share one keyspace keyed by the hash of the contract code, from the fork
+// on the storage trie falls back to that keyspace for the slots it does not hold.
+func (evm *EVM) getState(contract *Contract, slot crypto.Hash) []byte {
+	if evm.Rules.IsStorageTrie {
+		return evm.State.GetState(&contract.ContractAddr, slot)
+	}
+	return evm.State.Load(legacySlotKey(contract, slot))
+}
+
+// setState writes value into slot of the storage of contract
+func (evm *EVM) setState(contract *Contract, slot crypto.Hash, value *big.Int) error {
+	if evm.Rules.IsStorageTrie {
+		return evm.State.SetState(&contract.ContractAddr, slot, value.Bytes())
+	}
+	evm.State.Store(legacySlotKey(contract, slot), value)
+	return nil
+}
+
+func legacySlotKey(contract *Contract, slot crypto.Hash) *big.Int {
+	return database.LegacySlotKey(contract.ByteCode, slot)
+}
+
+// snapshot takes a snapshot of the state at the start of a call frame, the frames below the call
+// revert fork keep their state changes when they fail and take none
+func (evm *EVM) snapshot() int {
+	if !evm.Rules.IsCallRevert {
+		return -1
+	}
+	return evm.State.Snapshot()
+}
+
+func (evm *EVM) revertToSnapshot(id int) {
+	if id >= 0 {
+		evm.State.RevertToSnapshot(id)
+	}
+}
+
+func (evm *EVM) discardSnapshot(id int) {
+	if id >= 0 {
+		evm.State.DiscardSnapshot(id)
+	}
+}
+
 // Cancel cancels any running EVM operation. This may be called concurrently and
 // it's safe to be called multiple times.
 func (evm *EVM) Cancel() {
@@ -174,8 +264,8 @@ func (evm *EVM) Call(caller crypto.CommonAddress, addr crypto.CommonAddress, cha
 		to = addr
 	)
 	if !evm.State.Exist(addr) {
-		precompiles := PrecompiledContracts
-		if precompiles[addr] == nil && value.Sign() == 0 {
+		precompile := evm.precompile(addr)
+		if precompile == nil && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
 			//if evm.vmConfig.Debug && evm.depth == 0 {
 			//	evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
@@ -183,10 +273,14 @@ func (evm *EVM) Call(caller crypto.CommonAddress, addr crypto.CommonAddress, cha
 			//}
 			return nil, gas, nil
 		}
-
-		//evm.State.CreateAccount(addr)
-		return nil, 0, ErrNoAccount
+		// native contracts have no account, they can be called from the istanbul rules on
+		if precompile == nil || !evm.Rules.IsIstanbul {
+			//evm.State.CreateAccount(addr)
+			return nil, 0, ErrNoAccount
+		}
 	}
+	snapshot := evm.snapshot()
+	accessSnapshot := evm.accessList.snapshot()
 	evm.Transfer(evm.State, caller, to, value)
 	// Initialise a new contract and set the code that is to be used by the EVM.
 	// The contract is a scoped environment for this execution context only.
@@ -211,9 +305,13 @@ func (evm *EVM) Call(caller crypto.CommonAddress, addr crypto.CommonAddress, cha
 	// above we revert to the snapshot and consume any gas remaining. Additionally
 	// when we're in homestead this also counts for code storage gas errors.
 	if err != nil {
+		evm.revertToSnapshot(snapshot)
+		evm.accessList.revertToSnapshot(accessSnapshot)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
+	} else {
+		evm.discardSnapshot(snapshot)
 	}
 	return ret, contract.Gas, err
 }
@@ -239,6 +337,8 @@ func (evm *EVM) CallCode(caller crypto.CommonAddress, addr crypto.CommonAddress,
 		return nil, gas, ErrInsufficientBalance
 	}
 
+	snapshot := evm.snapshot()
+	accessSnapshot := evm.accessList.snapshot()
 	// initialise a new contract and set the code that is to be used by the
 	// EVM. The contract is a scoped environment for this execution context
 	// only.
@@ -247,9 +347,13 @@ func (evm *EVM) CallCode(caller crypto.CommonAddress, addr crypto.CommonAddress,
 
 	ret, err = run(evm, contract, input, false)
 	if err != nil {
+		evm.revertToSnapshot(snapshot)
+		evm.accessList.revertToSnapshot(accessSnapshot)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
+	} else {
+		evm.discardSnapshot(snapshot)
 	}
 	return ret, contract.Gas, err
 }
@@ -272,17 +376,20 @@ func (evm *EVM) DelegateCall(con *Contract, contractAddr crypto.CommonAddress, i
 		return nil, gas, ErrCodeNotExists
 	}
 
+	snapshot := evm.snapshot()
+	accessSnapshot := evm.accessList.snapshot()
 	contract := NewContract(callerAddr, evm.TxHash, chainId, gas, new(big.Int), jumpdests)
 	contract.SetCode(contractAddr, byteCode)
 
 	ret, err = run(evm, contract, input, false)
 	if err != nil {
-		//evm.State.dt.Discard()
+		evm.revertToSnapshot(snapshot)
+		evm.accessList.revertToSnapshot(accessSnapshot)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
 	} else {
-		//evm.State.dt.Commit()
+		evm.discardSnapshot(snapshot)
 	}
 
 	return ret, con.Gas, err
@@ -306,6 +413,8 @@ func (evm *EVM) StaticCall(caller crypto.CommonAddress, addr crypto.CommonAddres
 		return nil, gas, ErrCodeNotExists
 	}
 
+	snapshot := evm.snapshot()
+	accessSnapshot := evm.accessList.snapshot()
 	contract := NewContract(caller, evm.TxHash, evm.ChainId, gas, new(big.Int), nil)
 	contract.SetCode(addr, byteCode)
 
@@ -320,9 +429,13 @@ func (evm *EVM) StaticCall(caller crypto.CommonAddress, addr crypto.CommonAddres
 	// when we're in Homestead this also counts for code storage gas errors.
 	ret, err = run(evm, contract, input, true)
 	if err != nil {
+		evm.revertToSnapshot(snapshot)
+		evm.accessList.revertToSnapshot(accessSnapshot)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
+	} else {
+		evm.discardSnapshot(snapshot)
 	}
 	return ret, contract.Gas, err
 }
@@ -356,6 +469,10 @@ func (evm *EVM) CreateContractCode(caller crypto.CommonAddress, codeAndHash *cod
 	if evm.State.GetNonce(&address) != 0 || (contractHash != (crypto.Hash{}) && contractHash != emptyCodeHash) {
 		return nil, crypto.CommonAddress{}, 0, ErrContractAddressCollision
 	}
+	// the nonce of the caller and the warm address stay when the creation fails
+	evm.accessList.addAddress(address)
+	snapshot := evm.snapshot()
+	accessSnapshot := evm.accessList.snapshot()
 	// Create a new account on the state
 	account, err := evm.State.CreateContractAccount(address, codeAndHash.code)
 	evm.Transfer(evm.State, caller, address, value)
@@ -368,6 +485,7 @@ func (evm *EVM) CreateContractCode(caller crypto.CommonAddress, codeAndHash *cod
 	contract.SetCode(*contractAddr, codeAndHash.code)
 
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
+		evm.discardSnapshot(snapshot)
 		return nil, address, gas, nil
 	}
 
@@ -396,11 +514,18 @@ func (evm *EVM) CreateContractCode(caller crypto.CommonAddress, codeAndHash *cod
 	// When an error was returned by the EVM or when setting the creation code
 	// above we revert to the snapshot and consume any gas remaining. Additionally
 	// when we're in homestead this also counts for code storage gas errors.
-	if maxCodeSizeExceeded || (err != nil || err != ErrCodeStoreOutOfGas) {
-		//evm.State.RevertToSnapshot(snapshot)
+	if maxCodeSizeExceeded || err != nil {
+		evm.revertToSnapshot(snapshot)
+		evm.accessList.revertToSnapshot(accessSnapshot)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
+	} else {
+		evm.discardSnapshot(snapshot)
+		// below the call revert fork the gas left is burnt by successful creations as well
+		if !evm.Rules.IsCallRevert {
+			contract.UseGas(contract.Gas)
+		}
 	}
 	// Assign err if contract code size exceeds the max while the err is still empty.
 	if maxCodeSizeExceeded && err == nil {
@@ -21,6 +21,23 @@ const (
 	Suicide         uint64 = 5000 //5000
 	ExpByte         uint64 = 5000 //50
 	CreateBySuicide uint64 = 2500 //25000
+
+	// istanbul, eip-1884
+	SLoadEIP1884       uint64 = 8000 //800
+	BalanceEIP1884     uint64 = 7000 //700
+	ExtcodeHashEIP1884 uint64 = 7000 //700
+
+	// istanbul, eip-2200 SSTORE net gas metering. The sentry is the unscaled call stipend, SSTORE
+	// fails in the calls given only the stipend
+	SstoreSentryEIP2200               uint64 = 2300   //2300
+	SstoreSetEIP2200                  uint64 = 200000 //20000
+	SstoreResetEIP2200                uint64 = 50000  //5000
+	SstoreClearsScheduleRefundEIP2200 uint64 = 150000 //15000
+
+	// berlin, eip-2929
+	ColdAccountAccessEIP2929 uint64 = 26000 //2600
+	ColdSLoadEIP2929         uint64 = 21000 //2100
+	WarmStorageReadEIP2929   uint64 = 1000  //100
 )
 
 // calcGas returns the actual gas cost of the call.
@@ -102,8 +102,15 @@ func gasReturnDataCopy(evm *EVM, contract *Contract, stack *Stack, mem *Memory,
 func gasSStore(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
 	var (
 		y, x = stack.Back(1), stack.Back(0)
-		val  = evm.State.Load(x)
+		val  []byte
 	)
+	// the metering below the storage trie fork reads the legacy keyspace at the slot itself
+	// rather than at the key of the contract slot, kept as it is part of the mined blocks
+	if evm.Rules.IsStorageTrie {
+		val = evm.State.GetState(&contract.ContractAddr, crypto.BigToHash(x))
+	} else {
+		val = evm.State.Load(x)
+	}
 	// This checks for 3 scenario's and calculates gas accordingly
 	// 1. From a zero-value address to a non-zero value         (NEW VALUE)
 	// 2. From a non-zero value address to a zero-value address (DELETE)
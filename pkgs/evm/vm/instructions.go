@@ -627,37 +627,17 @@ func opMstore8(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memo
 }
 
 func opSload(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
-	fmt.Println("is sloading")
 	loc := stack.peek()
-	fmt.Println("loc: ", loc)
-	modifiedLoc := new(big.Int).SetBytes(sha3.HashS256(contract.ByteCode, loc.Bytes()))
-	//addr, err := interpreter.EVM.State.GetAccountAddress(loc)
-	//if err != nil {
-	//	loc.Set(new(big.Int))
-	//	return nil, err
-	//}
-	////val := interpreter.evm.StateDB.GetState(contract.GetAddress(), BigToHash(loc))
-	////loc.SetBytes(val.Bytes())
-	//b, err := AddressToBig(addr)
-	//if err != nil {
-	//	loc.Set(new(big.Int))
-	//	return nil, err
-	//}
-	//b := interpreter.EVM.State.Load(loc)
-	b := interpreter.EVM.State.Load(modifiedLoc)
-	loc.SetBytes(b)
+	val := interpreter.EVM.getState(contract, crypto.BigToHash(loc))
+	loc.SetBytes(val)
 	return nil, nil
 }
 
 func opSstore(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
-	//loc := BigToHash(stack.pop())
 	loc, val := stack.pop(), stack.pop()
-	//fmt.Println("loc: ", loc)
-	modifiedLoc := new(big.Int).SetBytes(sha3.HashS256(contract.ByteCode, loc.Bytes()))
-	interpreter.EVM.State.Store(modifiedLoc, val)
-	//interpreter.EVM.State.Store(loc, val)
+	err := interpreter.EVM.setState(contract, crypto.BigToHash(loc), val)
 	interpreter.IntPool.put(val)
-	return nil, nil
+	return nil, err
 }
 
 func opJump(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
@@ -16,10 +16,21 @@ type EVMInterpreter struct {
 	Tracer     Tracer // Opcode logger
 }
 
+// NewEVMInterpreter returns an interpreter running the instruction set of the rules of the block,
+// blocks below every fork height run the constantinople instructions
 func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
+	var jumpTable [256]operation
+	switch {
+	case evm.Rules.IsBerlin:
+		jumpTable = berlinInstructionSet
+	case evm.Rules.IsIstanbul:
+		jumpTable = istanbulInstructionSet
+	default:
+		jumpTable = constantinopleInstructionSet
+	}
 	return &EVMInterpreter{
 		EVM:       evm,
-		JumpTable: constantinopleInstructionSet,
+		JumpTable: jumpTable,
 		Tracer:    NewStructLogger(evm.vmConfig.LogConfig),
 	}
 }
@@ -38,8 +38,51 @@ var (
 	homesteadInstructionSet      = newHomesteadInstructionSet()
 	byzantiumInstructionSet      = newByzantiumInstructionSet()
 	constantinopleInstructionSet = newConstantinopleInstructionSet()
+	istanbulInstructionSet       = newIstanbulInstructionSet()
+	berlinInstructionSet         = newBerlinInstructionSet()
 )
 
+// newBerlinInstructionSet returns the istanbul instructions with the eip-2929 gas costs, the first
+// access of an account or a storage slot in a transaction is cold and costs more than the later ones.
+func newBerlinInstructionSet() [256]operation {
+	instructionSet := newIstanbulInstructionSet()
+	instructionSet[SLOAD].gasCost = gasSLoadEIP2929
+	instructionSet[SSTORE].gasCost = makeGasSStoreFunc(WarmStorageReadEIP2929, SstoreResetEIP2200-ColdSLoadEIP2929)
+	instructionSet[BALANCE].gasCost = gasAccountAccessEIP2929
+	instructionSet[EXTCODESIZE].gasCost = gasAccountAccessEIP2929
+	instructionSet[EXTCODEHASH].gasCost = gasAccountAccessEIP2929
+	instructionSet[EXTCODECOPY].gasCost = gasExtCodeCopyEIP2929
+	instructionSet[CALL].gasCost = makeCallGasEIP2929(gasCall)
+	instructionSet[CALLCODE].gasCost = makeCallGasEIP2929(gasCallCode)
+	instructionSet[DELEGATECALL].gasCost = makeCallGasEIP2929(gasDelegateCall)
+	instructionSet[STATICCALL].gasCost = makeCallGasEIP2929(gasStaticCall)
+	instructionSet[SELFDESTRUCT].gasCost = gasSuicideEIP2929
+	return instructionSet
+}
+
+// newIstanbulInstructionSet returns the constantinople instructions with CHAINID and SELFBALANCE,
+// the eip-1884 repricing of the state reading instructions and the eip-2200 SSTORE net gas metering.
+func newIstanbulInstructionSet() [256]operation {
+	instructionSet := newConstantinopleInstructionSet()
+	instructionSet[CHAINID] = operation{
+		execute:       opChainID,
+		gasCost:       constGasFunc(GasQuickStep),
+		validateStack: makeStackFunc(0, 1),
+		valid:         true,
+	}
+	instructionSet[SELFBALANCE] = operation{
+		execute:       opSelfBalance,
+		gasCost:       constGasFunc(GasFastStep),
+		validateStack: makeStackFunc(0, 1),
+		valid:         true,
+	}
+	instructionSet[SLOAD].gasCost = constGasFunc(SLoadEIP1884)
+	instructionSet[BALANCE].gasCost = constGasFunc(BalanceEIP1884)
+	instructionSet[EXTCODEHASH].gasCost = constGasFunc(ExtcodeHashEIP1884)
+	instructionSet[SSTORE].gasCost = makeGasSStoreFunc(SLoadEIP1884, SstoreResetEIP2200)
+	return instructionSet
+}
+
 // NewConstantinopleInstructionSet returns the frontier, homestead
 // byzantium and contantinople instructions.
 func newConstantinopleInstructionSet() [256]operation {
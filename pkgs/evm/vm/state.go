@@ -2,6 +2,7 @@ package vm
 
 import (
 	"bytes"
+	"fmt"
 	"math/big"
 	"sync"
 
@@ -34,15 +35,30 @@ type VMState interface {
 	GetRefund() uint64
 	Load(x *big.Int) []byte
 	Store(x, y *big.Int)
+	GetState(addr *crypto.CommonAddress, slot crypto.Hash) []byte
+	SetState(addr *crypto.CommonAddress, slot crypto.Hash, value []byte) error
 	Exist(contractAddr crypto.CommonAddress) bool
 	Empty(addr *crypto.CommonAddress) bool
 	HasSuicided(addr crypto.CommonAddress) bool
+	AliasGet(alias string) *crypto.CommonAddress
+	GetReputation(addr *crypto.CommonAddress) *big.Int
+	Snapshot() int
+	RevertToSnapshot(id int)
+	DiscardSnapshot(id int)
 }
 
 type State struct {
-	db     *database.Database
-	refund uint64
-	logs   []*types.Log
+	db        *database.Database
+	refund    uint64
+	logs      []*types.Log
+	snapshots []snapshot
+}
+
+// snapshot is the state at the start of a call frame
+type snapshot struct {
+	journal int
+	refund  uint64
+	logs    int
 }
 
 func NewState(database *database.Database) *State {
@@ -162,6 +178,23 @@ func (s *State) Store(x, y *big.Int) {
 	s.db.Store(x, y)
 }
 
+func (s *State) GetState(addr *crypto.CommonAddress, slot crypto.Hash) []byte {
+	value, _ := s.db.GetState(addr, slot)
+	return value
+}
+
+func (s *State) SetState(addr *crypto.CommonAddress, slot crypto.Hash, value []byte) error {
+	return s.db.SetState(addr, slot, value)
+}
+
+func (s *State) AliasGet(alias string) *crypto.CommonAddress {
+	return s.db.AliasGet(alias)
+}
+
+func (s *State) GetReputation(addr *crypto.CommonAddress) *big.Int {
+	return s.db.GetReputation(addr)
+}
+
 func (s *State) Exist(contractAddr crypto.CommonAddress) bool {
 	storage, err := s.db.GetStorage(&contractAddr)
 	if err != nil || storage == nil {
@@ -169,3 +202,37 @@ func (s *State) Exist(contractAddr crypto.CommonAddress) bool {
 	}
 	return len(storage.ByteCode) > 0
 }
+
+// Snapshot record the current state and return its id, it is taken at the start of every call
+// frame so that a failed frame can be rolled back without touching its callers
+func (s *State) Snapshot() int {
+	s.snapshots = append(s.snapshots, snapshot{
+		journal: s.db.Snapshot(),
+		refund:  s.refund,
+		logs:    len(s.logs),
+	})
+	return len(s.snapshots) - 1
+}
+
+// RevertToSnapshot roll back the accounts, slots, refund and logs changed since the snapshot
+// with id was taken, the snapshot and every later one are discarded
+func (s *State) RevertToSnapshot(id int) {
+	if id < 0 || id >= len(s.snapshots) {
+		panic(fmt.Sprintf("revision id %v cannot be reverted", id))
+	}
+	shot := s.snapshots[id]
+	s.db.RevertToSnapshot(shot.journal)
+	s.refund = shot.refund
+	s.logs = s.logs[:shot.logs]
+	s.snapshots = s.snapshots[:id]
+}
+
+// DiscardSnapshot release the snapshot with id and every later one once its frame has succeeded,
+// the changes are kept and are reverted together with the frames of the callers
+func (s *State) DiscardSnapshot(id int) {
+	if id < 0 || id >= len(s.snapshots) {
+		panic(fmt.Sprintf("revision id %v cannot be discarded", id))
+	}
+	s.db.DiscardSnapshot(s.snapshots[id].journal)
+	s.snapshots = s.snapshots[:id]
+}
@@ -0,0 +1,117 @@
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/params"
+)
+
+func TestStateSnapshot(t *testing.T) {
+	defer os.RemoveAll("./test")
+	diskDb, err := database.NewDatabase("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer diskDb.Close()
+
+	addr := crypto.CommonAddress{1}
+	slot := crypto.Hash{}
+	s := NewState(diskDb.BeginTransaction(true))
+	if err := s.SetByteCode(&addr, []byte{0x00}); err != nil {
+		t.Fatal(err)
+	}
+	s.AddBalance(&addr, big.NewInt(100))
+
+	outer := s.Snapshot()
+	s.SubBalance(&addr, big.NewInt(10))
+	s.SetState(&addr, slot, []byte{1})
+	s.AddLog(addr, crypto.Hash{}, nil, nil, 0)
+	s.AddRefund(5)
+
+	// a failed inner frame only rolls back its own changes
+	inner := s.Snapshot()
+	s.SubBalance(&addr, big.NewInt(20))
+	s.SetState(&addr, slot, []byte{2})
+	s.AddLog(addr, crypto.Hash{}, nil, nil, 0)
+	s.RevertToSnapshot(inner)
+	if balance := s.GetBalance(&addr); balance.Cmp(big.NewInt(90)) != 0 {
+		t.Fatal("revert inner balance err", balance)
+	}
+	if value := s.GetState(&addr, slot); !bytes.Equal(value, []byte{1}) {
+		t.Fatal("revert inner slot err", value)
+	}
+	if len(s.logs) != 1 {
+		t.Fatal("revert inner logs err", len(s.logs))
+	}
+
+	// a succeeded inner frame is rolled back with its caller
+	inner = s.Snapshot()
+	s.SetState(&addr, slot, []byte{3})
+	s.AddRefund(5)
+	s.DiscardSnapshot(inner)
+	s.RevertToSnapshot(outer)
+	if balance := s.GetBalance(&addr); balance.Cmp(big.NewInt(100)) != 0 {
+		t.Fatal("revert outer balance err", balance)
+	}
+	if value := s.GetState(&addr, slot); value != nil {
+		t.Fatal("revert outer slot err", value)
+	}
+	if len(s.logs) != 0 || s.GetRefund() != 0 {
+		t.Fatal("revert outer logs and refund err", len(s.logs), s.GetRefund())
+	}
+}
+
+func TestCallRevertFork(t *testing.T) {
+	defer os.RemoveAll("./test")
+	diskDb, err := database.NewDatabase("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer diskDb.Close()
+
+	canTransfer := func(db VMState, addr crypto.CommonAddress, amount *big.Int) bool {
+		return db.GetBalance(&addr).Cmp(amount) >= 0
+	}
+	transfer := func(db VMState, sender, to crypto.CommonAddress, amount *big.Int) {
+		db.SubBalance(&sender, amount)
+		db.AddBalance(&to, amount)
+	}
+	caller, addr := crypto.CommonAddress{1}, crypto.CommonAddress{2}
+	slot := crypto.Hash{}
+	tests := []struct {
+		rules    params.Rules
+		slot     []byte
+		leftOver bool
+	}{
+		// the state changes of failed frames and the gas of creations used to be kept
+		{params.Rules{IsStorageTrie: true}, []byte{1}, false},
+		{params.Rules{IsStorageTrie: true, IsCallRevert: true}, nil, true},
+	}
+	for i, test := range tests {
+		s := NewState(diskDb.BeginTransaction(true))
+		// sstore(0, 1) followed by an invalid opcode
+		if err := s.SetByteCode(&addr, []byte{0x60, 0x01, 0x60, 0x00, 0x55, 0xfe}); err != nil {
+			t.Fatal(err)
+		}
+		env := NewEVM(Context{CanTransfer: canTransfer, Transfer: transfer, Rules: test.rules}, s, &VMConfig{})
+		if _, _, err := env.Call(caller, addr, 0, nil, 100000, new(big.Int)); err == nil {
+			t.Fatalf("test %d: call to invalid opcode succeeded", i)
+		}
+		if value := s.GetState(&addr, slot); !bytes.Equal(value, test.slot) {
+			t.Errorf("test %d: slot after failed call %v, want %v", i, value, test.slot)
+		}
+
+		_, _, leftOver, err := env.Create(caller, []byte{0x00}, 100000, new(big.Int))
+		if err != nil {
+			t.Fatalf("test %d: create failed %v", i, err)
+		}
+		if (leftOver > 0) != test.leftOver {
+			t.Errorf("test %d: create left %d gas", i, leftOver)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package filter
 import (
 	"context"
 	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/rpc"
 )
 
 /*
@@ -31,8 +32,8 @@ type FilterApi struct {
   }
 }
 */
-func (filter *FilterApi) NewPendingTransactionFilter() ID {
-	return filter.filterService.NewPendingTransactionFilter()
+func (filter *FilterApi) NewPendingTransactionFilter(ctx context.Context) (ID, error) {
+	return filter.filterService.NewPendingTransactionFilter(ctx)
 }
 
 /*
@@ -51,8 +52,8 @@ func (filter *FilterApi) NewPendingTransactionFilter() ID {
   }
 }
 */
-func (filter *FilterApi) NewBlockFilter() ID {
-	return filter.filterService.NewBlockFilter()
+func (filter *FilterApi) NewBlockFilter(ctx context.Context) (ID, error) {
+	return filter.filterService.NewBlockFilter(ctx)
 }
 
 /*
@@ -75,8 +76,8 @@ func (filter *FilterApi) NewBlockFilter() ID {
   }
 }
 */
-func (filter *FilterApi) NewFilter(crit FilterQuery) (ID, error) {
-	return filter.filterService.NewFilter(crit)
+func (filter *FilterApi) NewFilter(ctx context.Context, crit FilterQuery) (ID, error) {
+	return filter.filterService.NewFilter(ctx, crit)
 }
 
 /*
@@ -110,7 +111,7 @@ func (filter *FilterApi) UninstallFilter(id ID) bool {
 		topics: Array of DATA, - (optional) Array of 32 Bytes DATA topics. Topics are order-dependent. Each topic can also be an array of DATA with "or" options.
 		blockhash: DATA, 32 Bytes - (optional) , blockHash is a new filter option which restricts the logs returned to the single block with the 32-byte hash blockHash. Using blockHash is equivalent to fromBlock = toBlock = the block number with hash blockHash. If blockHash is present in the filter criteria, then neither fromBlock nor toBlock are allowed.
  return:
-	Array - Array of log objects, or an empty array if nothing has changed since last poll.
+	Array - Array of log objects, or an empty array if nothing has changed since last poll. Logs of contracts with an abi registered by vm_registerAbi carry the decoded event name and arguments in Event.
  example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"filter_getLogs","params":[{"topics":["0x000000000000000000000000a94f5374fce5edbc8e2a8697c15331677e6ebf0b"]}], "id": 3}' -H "Content-Type:application/json"
  response:
 {
@@ -141,7 +142,7 @@ func (filter *FilterApi) GetLogs(crit FilterQuery) ([]*types.Log, error) {
  params:
 	1. QUANTITY - The filter id.
  return:
-	Array - Array of log objects, or an empty array if nothing has changed since last poll.
+	Array - Array of log objects, or an empty array if nothing has changed since last poll. Logs of contracts with an abi registered by vm_registerAbi carry the decoded event name and arguments in Event.
  example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"filter_getFilterLogs","params":["0x16"], "id": 3}' -H "Content-Type:application/json"
  response:
 {
@@ -172,7 +173,7 @@ func (filter *FilterApi) GetFilterLogs(id ID) ([]*types.Log, error) {
  params:
 	1. QUANTITY - the filter id.
  return:
-	Array - Array of log objects, or an empty array if nothing has changed since last poll.
+	Array - Array of log objects, or an empty array if nothing has changed since last poll. Logs of contracts with an abi registered by vm_registerAbi carry the decoded event name and arguments in Event.
  example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"filter_getFilterChanges","params":["0x16"], "id": 3}' -H "Content-Type:application/json"
  response:
 {
@@ -196,3 +197,93 @@ func (filter *FilterApi) GetFilterLogs(id ID) ([]*types.Log, error) {
 func (filter *FilterApi) GetFilterChanges(id ID) (interface{}, error) {
 	return filter.filterService.GetFilterChanges(id)
 }
+
+/*
+ name: newHeads
+ usage: Subscribes over websocket to the header of every block appended to the chain, including blocks appended by a chain reorganization. Cancel with filter_unsubscribe.
+ params:
+	1. "newHeads"
+ return:
+	QUANTITY - A subscription id, followed by filter_subscription notifications.
+ example: wscat -c ws://localhost:15646 -x '{"jsonrpc":"2.0","method":"filter_subscribe","params":["newHeads"], "id": 3}'
+ response:
+{"jsonrpc":"2.0","id":3,"result":"0x9cef478923ff08bf67fde6c64013158d"}
+{
+  "jsonrpc": "2.0",
+  "method": "filter_subscription",
+  "params": {
+    "subscription": "0x9cef478923ff08bf67fde6c64013158d",
+    "result": {
+      "ChainId": "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+      "Version": 1,
+      "PreviousHash": "0x1717b4b9f740cebeb2659886122a29c0876ed906dd05370319fee4ecf219b1e9",
+      "GasLimit": 180000000,
+      "GasUsed": 0,
+      "Height": 1,
+      "Timestamp": 1559272779,
+      "StateRoot": "0xd7bd5b3af4f2f1fb3d484743052c2e911f9fb7b04131660912244347508f16a9",
+      "TxRoot": "0x"
+    }
+  }
+}
+*/
+func (filter *FilterApi) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	return filter.filterService.NewHeads(ctx)
+}
+
+/*
+ name: logs
+ usage: Subscribes over websocket to new logs matching the filter options. Logs of blocks detached by a chain reorganization are sent again with "Removed" set to true. Cancel with filter_unsubscribe.
+ params:
+	1. "logs"
+	2. Object - The filter options:
+		address: DATA|Array, 20 Bytes - (optional) Contract address or a list of addresses from which logs should originate.
+		topics: Array of DATA, - (optional) Array of 32 Bytes DATA topics. Topics are order-dependent. Each topic can also be an array of DATA with "or" options.
+ return:
+	QUANTITY - A subscription id, followed by filter_subscription notifications. Logs of contracts with an abi registered by vm_registerAbi carry the decoded event in Event.
+ example: wscat -c ws://localhost:15646 -x '{"jsonrpc":"2.0","method":"filter_subscribe","params":["logs",{"address":"0x16c5785ac562ff41e2dcfdf829c5a142f1fccd7d"}], "id": 3}'
+ response:
+{"jsonrpc":"2.0","id":3,"result":"0x4a8a4c0517381924f9838102c5a4dcb7"}
+{
+  "jsonrpc": "2.0",
+  "method": "filter_subscription",
+  "params": {
+    "subscription": "0x4a8a4c0517381924f9838102c5a4dcb7",
+    "result": {
+      "Address": "0x16c5785ac562ff41e2dcfdf829c5a142f1fccd7d",
+      "Topics": ["0x59ebeb90bc63057b6515673c3ecf9438e5058bca0f92585014eced636878c9a5"],
+      "Data": "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+      "TxHash": "0xdf829c5a142f1fccd7d8216c5785ac562ff41e2dcfdf5785ac562ff41e2dcf",
+      "Height": 436,
+      "TxIndex": 0,
+      "Removed": false
+    }
+  }
+}
+*/
+func (filter *FilterApi) Logs(ctx context.Context, crit FilterQuery) (*rpc.Subscription, error) {
+	return filter.filterService.Logs(ctx, crit)
+}
+
+/*
+ name: newPendingTransactions
+ usage: Subscribes over websocket to the hash of every transaction entering the transaction pool. Cancel with filter_unsubscribe.
+ params:
+	1. "newPendingTransactions"
+ return:
+	QUANTITY - A subscription id, followed by filter_subscription notifications.
+ example: wscat -c ws://localhost:15646 -x '{"jsonrpc":"2.0","method":"filter_subscribe","params":["newPendingTransactions"], "id": 3}'
+ response:
+{"jsonrpc":"2.0","id":3,"result":"0xc3b33aa549fb9a60e95d21862596617c"}
+{
+  "jsonrpc": "2.0",
+  "method": "filter_subscription",
+  "params": {
+    "subscription": "0xc3b33aa549fb9a60e95d21862596617c",
+    "result": "0xd6fdc5cc41a9959e922f30cb772a9aef46f4daea279307bc5f7024edc4ccd7fa"
+  }
+}
+*/
+func (filter *FilterApi) NewPendingTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	return filter.filterService.NewPendingTransactions(ctx)
+}
@@ -2,10 +2,20 @@ package filter
 
 type FilterConfig struct {
 	Enable bool `json:"enable"`
+	// max number of filters installed by a client, 0 for no limit. Clients are websocket
+	// connections and the ip of http requests.
+	MaxFilters int `json:"maxFilters"`
+	// max number of blocks searched by one getLogs query, 0 for no limit
+	MaxBlockRange uint64 `json:"maxBlockRange"`
+	// max number of subscriptions of a websocket connection, 0 for no limit
+	MaxSubscriptions int `json:"maxSubscriptions"`
 }
 
 var (
 	DefaultConfig = &FilterConfig{
-		Enable: true,
+		Enable:           true,
+		MaxFilters:       1024,
+		MaxBlockRange:    10000,
+		MaxSubscriptions: 128,
 	}
 )
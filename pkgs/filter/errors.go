@@ -0,0 +1,35 @@
+package filter
+
+import (
+	"fmt"
+)
+
+const (
+	// json rpc error code of requests over the limits of filter service
+	errCodeLimitExceeded = -32005
+)
+
+// limitError is returned to rpc clients with errCodeLimitExceeded
+type limitError struct {
+	message string
+}
+
+func (err *limitError) Error() string {
+	return err.message
+}
+
+func (err *limitError) ErrorCode() int {
+	return errCodeLimitExceeded
+}
+
+func errTooManyFilters(max int) error {
+	return &limitError{fmt.Sprintf("too many filters, limit %d", max)}
+}
+
+func errTooManySubscriptions(max int) error {
+	return &limitError{fmt.Sprintf("too many subscriptions, limit %d", max)}
+}
+
+func errBlockRangeTooLarge(size, max uint64) error {
+	return &limitError{fmt.Sprintf("block range of %d blocks exceeds limit %d", size, max)}
+}
@@ -0,0 +1,255 @@
+package filter
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/common/bloombits"
+	"github.com/drep-project/DREP-Chain/common/event"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/pkgs/evm"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/rpc"
+)
+
+const testTimeout = 2 * time.Second
+
+// testBackend feeds the event system with the events sent by the tests
+type testBackend struct {
+	txFeed     event.Feed
+	chainFeed  event.Feed
+	rmLogsFeed event.Feed
+	logsFeed   event.Feed
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, blockNr common.BlockNumber) (*types.BlockHeader, error) {
+	return nil, nil
+}
+
+func (b *testBackend) HeaderByHash(ctx context.Context, blockHash crypto.Hash) (*types.BlockHeader, error) {
+	return nil, nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, blockHash crypto.Hash) (types.Receipts, error) {
+	return nil, nil
+}
+
+func (b *testBackend) GetLogsByHash(ctx context.Context, blockHash crypto.Hash) ([][]*types.Log, error) {
+	return nil, nil
+}
+
+func (b *testBackend) SubscribeNewTxsEvent(ch chan<- types.NewTxsEvent) event.Subscription {
+	return b.txFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeChainEvent(ch chan<- *types.ChainEvent) event.Subscription {
+	return b.chainFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeRemovedLogsEvent(ch chan<- types.RemovedLogsEvent) event.Subscription {
+	return b.rmLogsFeed.Subscribe(ch)
+}
+
+func (b *testBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.logsFeed.Subscribe(ch)
+}
+
+func (b *testBackend) BloomStatus() (uint64, uint64) {
+	return 0, 0
+}
+
+func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {}
+
+// testVm leaves the logs undecoded
+type testVm struct {
+	evm.Vm
+}
+
+func (vm *testVm) DecodeLogs(logs []*types.Log) {}
+
+// newTestFilter serve the subscriptions of a filter service fed by backend over an in process
+// connection, the returned function closes the connection
+func newTestFilter(backend *testBackend, config *FilterConfig) (*FilterService, *rpc.Client, func()) {
+	service := &FilterService{
+		VmService:     &testVm{},
+		Config:        config,
+		mux:           new(event.TypeMux),
+		filters:       make(map[ID]*filter),
+		subscriptions: make(map[*rpc.Notifier]int),
+	}
+	service.events = NewEventSystem(service.mux, backend, false)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName(MODULENAME, &FilterApi{filterService: service}); err != nil {
+		panic(err)
+	}
+	client := rpc.DialInProc(server)
+	return service, client, func() {
+		client.Close()
+		server.Stop()
+	}
+}
+
+// subscriptionCount wait until the connections of service hold count subscriptions
+func subscriptionCount(t *testing.T, service *FilterService, count int) {
+	deadline := time.Now().Add(testTimeout)
+	for {
+		service.filtersMu.Lock()
+		held := 0
+		for _, n := range service.subscriptions {
+			held += n
+		}
+		service.filtersMu.Unlock()
+		if held == count {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d subscriptions held, want %d", held, count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSubscriptionNotifications(t *testing.T) {
+	backend := &testBackend{}
+	_, client, closeFilter := newTestFilter(backend, &FilterConfig{})
+	defer closeFilter()
+
+	ctx := context.Background()
+	contract := crypto.CommonAddress{19: 1}
+	heads := make(chan *types.BlockHeader)
+	headSub, err := client.Subscribe(ctx, MODULENAME, heads, "newHeads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer headSub.Unsubscribe()
+	logs := make(chan *types.Log)
+	logSub, err := client.Subscribe(ctx, MODULENAME, logs, "logs", map[string]interface{}{"address": contract})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logSub.Unsubscribe()
+	txs := make(chan crypto.Hash)
+	txSub, err := client.Subscribe(ctx, MODULENAME, txs, "newPendingTransactions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer txSub.Unsubscribe()
+
+	block := &types.Block{Header: &types.BlockHeader{Height: 7}, Data: &types.BlockData{}}
+	backend.chainFeed.Send(&types.ChainEvent{Block: block, Hash: *block.Header.Hash()})
+	select {
+	case header := <-heads:
+		if header.Height != 7 {
+			t.Errorf("head at height %d, want 7", header.Height)
+		}
+	case err := <-headSub.Err():
+		t.Fatal(err)
+	case <-time.After(testTimeout):
+		t.Fatal("no head notified")
+	}
+
+	// the log of another contract is filtered out
+	backend.logsFeed.Send([]*types.Log{
+		{Address: crypto.CommonAddress{19: 2}, Height: 7},
+		{Address: contract, Height: 7, Data: []byte{1}},
+	})
+	select {
+	case l := <-logs:
+		if l.Address != contract || l.Height != 7 || l.Removed {
+			t.Errorf("unexpected log %+v", l)
+		}
+	case err := <-logSub.Err():
+		t.Fatal(err)
+	case <-time.After(testTimeout):
+		t.Fatal("no log notified")
+	}
+
+	tx := types.NewTransaction(contract, big.NewInt(1), big.NewInt(1), big.NewInt(21000), 1)
+	backend.txFeed.Send(types.NewTxsEvent{Txs: []*types.Transaction{tx}})
+	select {
+	case hash := <-txs:
+		if hash != *tx.TxHash() {
+			t.Errorf("pending tx %s, want %s", hash.String(), tx.TxHash().String())
+		}
+	case err := <-txSub.Err():
+		t.Fatal(err)
+	case <-time.After(testTimeout):
+		t.Fatal("no pending tx notified")
+	}
+}
+
+func TestSubscriptionRemovedLogs(t *testing.T) {
+	backend := &testBackend{}
+	_, client, closeFilter := newTestFilter(backend, &FilterConfig{})
+	defer closeFilter()
+
+	logs := make(chan *types.Log)
+	sub, err := client.Subscribe(context.Background(), MODULENAME, logs, "logs", map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	txHash := crypto.Hash{31: 1}
+	next := func() *types.Log {
+		select {
+		case l := <-logs:
+			return l
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(testTimeout):
+			t.Fatal("no log notified")
+		}
+		return nil
+	}
+	backend.logsFeed.Send([]*types.Log{{TxHash: txHash, Height: 3}})
+	if l := next(); l.Removed || l.TxHash != txHash {
+		t.Fatalf("unexpected log %+v", l)
+	}
+
+	// a reorg detaching the block sends its log again, marked as removed
+	backend.rmLogsFeed.Send(types.RemovedLogsEvent{Logs: []*types.Log{{TxHash: txHash, Height: 3, Removed: true}}})
+	if l := next(); !l.Removed || l.TxHash != txHash || l.Height != 3 {
+		t.Fatalf("removed log %+v, want the log of tx %s marked removed", l, txHash.String())
+	}
+}
+
+func TestSubscriptionLimit(t *testing.T) {
+	backend := &testBackend{}
+	service, client, closeFilter := newTestFilter(backend, &FilterConfig{MaxSubscriptions: 2})
+	defer closeFilter()
+
+	ctx := context.Background()
+	heads := make(chan *types.BlockHeader)
+	first, err := client.Subscribe(ctx, MODULENAME, heads, "newHeads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Subscribe(ctx, MODULENAME, make(chan crypto.Hash), "newPendingTransactions"); err != nil {
+		t.Fatal(err)
+	}
+	subscriptionCount(t, service, 2)
+
+	// every kind of subscription is counted against the cap of the connection
+	if _, err := client.Subscribe(ctx, MODULENAME, make(chan *types.Log), "logs", map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), errTooManySubscriptions(2).Error()) {
+		t.Fatalf("subscription over the cap: %v, want %v", err, errTooManySubscriptions(2))
+	}
+	subscriptionCount(t, service, 2)
+
+	// ending a subscription releases its slot
+	first.Unsubscribe()
+	subscriptionCount(t, service, 1)
+	if _, err := client.Subscribe(ctx, MODULENAME, make(chan *types.Log), "logs", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	subscriptionCount(t, service, 2)
+
+	// so does closing the connection
+	closeFilter()
+	subscriptionCount(t, service, 0)
+}
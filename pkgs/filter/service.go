@@ -18,7 +18,10 @@ import (
 	"github.com/drep-project/DREP-Chain/crypto"
 	"github.com/drep-project/DREP-Chain/database"
 	"github.com/drep-project/DREP-Chain/pkgs/chain_indexer"
+	"github.com/drep-project/DREP-Chain/pkgs/evm"
+	rpc2 "github.com/drep-project/DREP-Chain/pkgs/rpc"
 	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/rpc"
 )
 
 var (
@@ -74,6 +77,7 @@ type FilterService struct {
 	Notifier            blockmgr.IBlockNotify                      `service:"blockmgr"`
 	ChainService        chain.ChainServiceInterface                `service:"chain"`
 	ChainIndexerService chain_indexer.ChainIndexerServiceInterface `service:"chain_indexer"`
+	VmService           evm.Vm                                     `service:"vm"`
 	Config              *FilterConfig
 
 	apis []app.API
@@ -83,6 +87,7 @@ type FilterService struct {
 	events        *EventSystem
 	filtersMu     sync.Mutex
 	filters       map[ID]*filter
+	subscriptions map[*rpc.Notifier]int // subscriptions of each websocket connection, guarded by filtersMu
 }
 
 // filter is a helper struct that holds meta information over the filter type
@@ -94,6 +99,7 @@ type filter struct {
 	crit     FilterQuery
 	logs     []*types.Log
 	s        *Subscription // associated subscription in event system
+	owner    interface{}   // client installing the filter, see filterOwner
 }
 
 // implement Service interface
@@ -109,10 +115,13 @@ func (service *FilterService) CommandFlags() ([]cli.Command, []cli.Flag) {
 	return nil, []cli.Flag{EnableFilterFlag}
 }
 
-func (service *FilterService) Init(executeContext *app.ExecuteContext) error {
+func (service *FilterService) ApplyFlags(executeContext *app.ExecuteContext) {
 	if executeContext.Cli.GlobalIsSet(EnableFilterFlag.Name) {
 		service.Config.Enable = executeContext.Cli.GlobalBool(EnableFilterFlag.Name)
 	}
+}
+
+func (service *FilterService) Init(executeContext *app.ExecuteContext) error {
 	if !service.Config.Enable {
 		return nil
 	}
@@ -121,6 +130,7 @@ func (service *FilterService) Init(executeContext *app.ExecuteContext) error {
 	service.mux = new(event.TypeMux)
 	service.events = NewEventSystem(service.mux, service, false)
 	service.filters = make(map[ID]*filter)
+	service.subscriptions = make(map[*rpc.Notifier]int)
 	service.bloomRequests = make(chan chan *bloombits.Retrieval)
 
 	service.apis = []app.API{
@@ -212,15 +222,17 @@ func (service *FilterService) timeoutLoop() {
 // `eth_getFilterChanges` polling method that is also used for log filters.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_newpendingtransactionfilter
-func (service *FilterService) NewPendingTransactionFilter() ID {
+func (service *FilterService) NewPendingTransactionFilter(ctx context.Context) (ID, error) {
 	var (
 		pendingTxs   = make(chan []crypto.Hash)
 		pendingTxSub = service.events.SubscribePendingTxs(pendingTxs)
 	)
 
-	service.filtersMu.Lock()
-	service.filters[pendingTxSub.ID] = &filter{typ: PendingTransactionsSubscription, deadline: time.NewTimer(deadline), hashes: make([]crypto.Hash, 0), s: pendingTxSub}
-	service.filtersMu.Unlock()
+	f := &filter{typ: PendingTransactionsSubscription, deadline: time.NewTimer(deadline), hashes: make([]crypto.Hash, 0), s: pendingTxSub, owner: filterOwner(ctx)}
+	if err := service.installFilter(pendingTxSub.ID, f); err != nil {
+		pendingTxSub.Unsubscribe()
+		return ID(""), err
+	}
 
 	go func() {
 		for {
@@ -240,22 +252,24 @@ func (service *FilterService) NewPendingTransactionFilter() ID {
 		}
 	}()
 
-	return pendingTxSub.ID
+	return pendingTxSub.ID, nil
 }
 
 // NewBlockFilter creates a filter that fetches blocks that are imported into the chain.
 // It is part of the filter package since polling goes with eth_getFilterChanges.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_newblockfilter
-func (service *FilterService) NewBlockFilter() ID {
+func (service *FilterService) NewBlockFilter(ctx context.Context) (ID, error) {
 	var (
 		headers   = make(chan *types.BlockHeader)
 		headerSub = service.events.SubscribeNewHeads(headers)
 	)
 
-	service.filtersMu.Lock()
-	service.filters[headerSub.ID] = &filter{typ: BlocksSubscription, deadline: time.NewTimer(deadline), hashes: make([]crypto.Hash, 0), s: headerSub}
-	service.filtersMu.Unlock()
+	f := &filter{typ: BlocksSubscription, deadline: time.NewTimer(deadline), hashes: make([]crypto.Hash, 0), s: headerSub, owner: filterOwner(ctx)}
+	if err := service.installFilter(headerSub.ID, f); err != nil {
+		headerSub.Unsubscribe()
+		return ID(""), err
+	}
 
 	go func() {
 		for {
@@ -275,7 +289,7 @@ func (service *FilterService) NewBlockFilter() ID {
 		}
 	}()
 
-	return headerSub.ID
+	return headerSub.ID, nil
 }
 
 // NewFilter creates a new filter and returns the filter id. It can be
@@ -291,16 +305,18 @@ func (service *FilterService) NewBlockFilter() ID {
 // In case "fromBlock" > "toBlock" an error is returned.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_newfilter
-func (service *FilterService) NewFilter(crit FilterQuery) (ID, error) {
+func (service *FilterService) NewFilter(ctx context.Context, crit FilterQuery) (ID, error) {
 	logs := make(chan []*types.Log)
 	logsSub, err := service.events.SubscribeLogs(crit, logs)
 	if err != nil {
 		return ID(""), err
 	}
 
-	service.filtersMu.Lock()
-	service.filters[logsSub.ID] = &filter{typ: LogsSubscription, crit: crit, deadline: time.NewTimer(deadline), logs: make([]*types.Log, 0), s: logsSub}
-	service.filtersMu.Unlock()
+	f := &filter{typ: LogsSubscription, crit: crit, deadline: time.NewTimer(deadline), logs: make([]*types.Log, 0), s: logsSub, owner: filterOwner(ctx)}
+	if err := service.installFilter(logsSub.ID, f); err != nil {
+		logsSub.Unsubscribe()
+		return ID(""), err
+	}
 
 	go func() {
 		for {
@@ -323,33 +339,227 @@ func (service *FilterService) NewFilter(crit FilterQuery) (ID, error) {
 	return logsSub.ID, nil
 }
 
+// NewHeads send a notification each time a new block is appended to the chain, including
+// blocks appended by a chain reorganization.
+func (service *FilterService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if err := service.acquireSubscription(notifier); err != nil {
+		return nil, err
+	}
+	var (
+		headers   = make(chan *types.BlockHeader)
+		headerSub = service.events.SubscribeNewHeads(headers)
+		rpcSub    = notifier.CreateSubscription()
+	)
+
+	go func() {
+		defer service.releaseSubscription(notifier)
+		defer headerSub.Unsubscribe()
+		for {
+			select {
+			case h := <-headers:
+				notifier.Notify(rpcSub.ID, h)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// Logs send a notification for every new log matching crit. Logs of blocks detached by a
+// chain reorganization are sent again with the removed property set to true.
+func (service *FilterService) Logs(ctx context.Context, crit FilterQuery) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if err := service.acquireSubscription(notifier); err != nil {
+		return nil, err
+	}
+	logs := make(chan []*types.Log)
+	logsSub, err := service.events.SubscribeLogs(crit, logs)
+	if err != nil {
+		service.releaseSubscription(notifier)
+		return nil, err
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer service.releaseSubscription(notifier)
+		defer logsSub.Unsubscribe()
+		for {
+			select {
+			case ls := <-logs:
+				for _, l := range service.returnLogs(ls) {
+					notifier.Notify(rpcSub.ID, l)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// NewPendingTransactions send a notification with the hash of each transaction entering the
+// transaction pool.
+func (service *FilterService) NewPendingTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if err := service.acquireSubscription(notifier); err != nil {
+		return nil, err
+	}
+	var (
+		pendingTxs   = make(chan []crypto.Hash)
+		pendingTxSub = service.events.SubscribePendingTxs(pendingTxs)
+		rpcSub       = notifier.CreateSubscription()
+	)
+
+	go func() {
+		defer service.releaseSubscription(notifier)
+		defer pendingTxSub.Unsubscribe()
+		for {
+			select {
+			case hashes := <-pendingTxs:
+				for _, h := range hashes {
+					notifier.Notify(rpcSub.ID, h)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // GetLogs returns logs matching the given argument that are stored within the state.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getlogs
 func (service *FilterService) GetLogs(ctx context.Context, crit FilterQuery) ([]*types.Log, error) {
-	var filter *Filter
-	if crit.BlockHash != nil {
-		// Block filter requested, construct a single-shot filter
-		filter = NewBlockFilter(service, *crit.BlockHash, crit.Addresses, crit.Topics)
-	} else {
-		// Convert the RPC block numbers into internal representations
-		begin := common.LatestBlockNumber.Int64()
-		if crit.FromBlock != nil {
-			begin = crit.FromBlock.Int64()
-		}
-		end := common.LatestBlockNumber.Int64()
-		if crit.ToBlock != nil {
-			end = crit.ToBlock.Int64()
-		}
-		// Construct the range filter
-		filter = NewRangeFilter(service, begin, end, crit.Addresses, crit.Topics)
+	filter, err := service.queryFilter(crit)
+	if err != nil {
+		return nil, err
 	}
 	// Run the filter and return all the logs
 	logs, err := filter.Logs(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return returnLogs(logs), err
+	return service.returnLogs(logs), err
+}
+
+// queryFilter construct the single-shot filter of crit, range filters searching more blocks than
+// MaxBlockRange are refused
+func (service *FilterService) queryFilter(crit FilterQuery) (*Filter, error) {
+	if crit.BlockHash != nil {
+		// Block filter requested, construct a single-shot filter
+		return NewBlockFilter(service, *crit.BlockHash, crit.Addresses, crit.Topics), nil
+	}
+	// Convert the RPC block numbers into internal representations
+	begin := common.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.Int64()
+	}
+	end := common.LatestBlockNumber.Int64()
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.Int64()
+	}
+	if err := service.checkBlockRange(begin, end); err != nil {
+		return nil, err
+	}
+	// Construct the range filter
+	return NewRangeFilter(service, begin, end, crit.Addresses, crit.Topics), nil
+}
+
+// checkBlockRange check the number of blocks between begin and end, negative numbers stand for
+// the current head as they do in range filters
+func (service *FilterService) checkBlockRange(begin, end int64) error {
+	if service.Config.MaxBlockRange == 0 {
+		return nil
+	}
+	head := int64(service.ChainService.GetCurrentHeader().Height)
+	if begin < 0 {
+		begin = head
+	}
+	if end < 0 {
+		end = head
+	}
+	if end < begin {
+		return nil
+	}
+	if size := uint64(end-begin) + 1; size > service.Config.MaxBlockRange {
+		return errBlockRangeTooLarge(size, service.Config.MaxBlockRange)
+	}
+	return nil
+}
+
+// filterOwner return the client calling a filter method, the websocket connection of a call over
+// websocket or the client ip of a call over http. Calls over ipc share a nil owner.
+func filterOwner(ctx context.Context) interface{} {
+	if notifier, supported := rpc.NotifierFromContext(ctx); supported {
+		return notifier
+	}
+	if ip, ok := rpc2.ClientIPFromContext(ctx); ok {
+		return ip
+	}
+	return nil
+}
+
+// installFilter add f with id, it is refused if the owner of f has MaxFilters filters installed
+func (service *FilterService) installFilter(id ID, f *filter) error {
+	service.filtersMu.Lock()
+	defer service.filtersMu.Unlock()
+	if service.Config.MaxFilters > 0 {
+		count := 0
+		for _, installed := range service.filters {
+			if installed.owner == f.owner {
+				count++
+			}
+		}
+		if count >= service.Config.MaxFilters {
+			return errTooManyFilters(service.Config.MaxFilters)
+		}
+	}
+	service.filters[id] = f
+	return nil
+}
+
+// acquireSubscription count a new subscription of the websocket connection of notifier, refused
+// if the connection has MaxSubscriptions subscriptions
+func (service *FilterService) acquireSubscription(notifier *rpc.Notifier) error {
+	service.filtersMu.Lock()
+	defer service.filtersMu.Unlock()
+	if service.Config.MaxSubscriptions > 0 && service.subscriptions[notifier] >= service.Config.MaxSubscriptions {
+		return errTooManySubscriptions(service.Config.MaxSubscriptions)
+	}
+	service.subscriptions[notifier]++
+	return nil
+}
+
+// releaseSubscription uncount a subscription of the connection of notifier once it has ended
+func (service *FilterService) releaseSubscription(notifier *rpc.Notifier) {
+	service.filtersMu.Lock()
+	defer service.filtersMu.Unlock()
+	if service.subscriptions[notifier] <= 1 {
+		delete(service.subscriptions, notifier)
+		return
+	}
+	service.subscriptions[notifier]--
 }
 
 // UninstallFilter removes the filter with the given filter id.
@@ -382,22 +592,9 @@ func (service *FilterService) GetFilterLogs(ctx context.Context, id ID) ([]*type
 		return nil, fmt.Errorf("filter not found")
 	}
 
-	var filter *Filter
-	if f.crit.BlockHash != nil {
-		// Block filter requested, construct a single-shot filter
-		filter = NewBlockFilter(service, *f.crit.BlockHash, f.crit.Addresses, f.crit.Topics)
-	} else {
-		// Convert the RPC block numbers into internal representations
-		begin := common.LatestBlockNumber.Int64()
-		if f.crit.FromBlock != nil {
-			begin = f.crit.FromBlock.Int64()
-		}
-		end := common.LatestBlockNumber.Int64()
-		if f.crit.ToBlock != nil {
-			end = f.crit.ToBlock.Int64()
-		}
-		// Construct the range filter
-		filter = NewRangeFilter(service, begin, end, f.crit.Addresses, f.crit.Topics)
+	filter, err := service.queryFilter(f.crit)
+	if err != nil {
+		return nil, err
 	}
 
 	// Run the filter and return all the logs
@@ -405,7 +602,7 @@ func (service *FilterService) GetFilterLogs(ctx context.Context, id ID) ([]*type
 	if err != nil {
 		return nil, err
 	}
-	return returnLogs(logs), nil
+	return service.returnLogs(logs), nil
 }
 
 // GetFilterChanges returns the logs for the filter with the given id since
@@ -435,7 +632,7 @@ func (service *FilterService) GetFilterChanges(id ID) (interface{}, error) {
 		case LogsSubscription:
 			logs := f.logs
 			f.logs = nil
-			return returnLogs(logs), nil
+			return service.returnLogs(logs), nil
 		}
 	}
 
@@ -452,12 +649,19 @@ func returnHashes(hashes []crypto.Hash) []crypto.Hash {
 }
 
 // returnLogs is a helper that will return an empty log array in case the given logs array is nil,
-// otherwise the given logs array is returned.
-func returnLogs(logs []*types.Log) []*types.Log {
+// otherwise copies of the given logs are returned with the events of contracts with a registered
+// abi decoded. The logs are copied as the ones of the event system are shared by all filters.
+func (service *FilterService) returnLogs(logs []*types.Log) []*types.Log {
 	if logs == nil {
 		return []*types.Log{}
 	}
-	return logs
+	decoded := make([]*types.Log, len(logs))
+	for i, l := range logs {
+		cpy := *l
+		decoded[i] = &cpy
+	}
+	service.VmService.DecodeLogs(decoded)
+	return decoded
 }
 
 // ------------------------------------
@@ -0,0 +1,148 @@
+package filter
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/chain"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/rpc"
+)
+
+// testChain is a chain at height head
+type testChain struct {
+	chain.ChainServiceInterface
+	head uint64
+}
+
+func (c *testChain) GetCurrentHeader() *types.BlockHeader {
+	return &types.BlockHeader{Height: c.head}
+}
+
+func TestCheckBlockRange(t *testing.T) {
+	service, _, closeFilter := newTestFilter(&testBackend{}, &FilterConfig{MaxBlockRange: 10})
+	defer closeFilter()
+	service.ChainService = &testChain{head: 100}
+
+	number := func(n int64) *big.Int {
+		return big.NewInt(n)
+	}
+	tests := []struct {
+		from, to *big.Int
+		size     uint64 // size of a range over the limit, 0 if accepted
+	}{
+		{from: number(0), to: number(9)},
+		{from: number(0), to: number(10), size: 11},
+		{from: number(50), to: number(50)},
+		{from: number(9), to: number(0)},
+		// latest stands for the head of the chain
+		{from: number(91)},
+		{from: number(90), size: 11},
+		{from: number(91), to: number(-1)},
+		{},
+		{to: number(-1)},
+		{from: number(0), size: 101},
+		{from: number(0), to: number(100000), size: 100001},
+	}
+	for _, test := range tests {
+		crit := FilterQuery{FromBlock: test.from, ToBlock: test.to}
+		_, err := service.queryFilter(crit)
+		if test.size == 0 {
+			if err != nil {
+				t.Errorf("range %v-%v refused: %v", test.from, test.to, err)
+			}
+			continue
+		}
+		want := errBlockRangeTooLarge(test.size, 10)
+		if err == nil || err.Error() != want.Error() {
+			t.Errorf("range %v-%v: %v, want %v", test.from, test.to, err, want)
+		}
+		if _, err := service.GetLogs(context.Background(), crit); err == nil || err.Error() != want.Error() {
+			t.Errorf("getLogs %v-%v: %v, want %v", test.from, test.to, err, want)
+		}
+	}
+
+	// a block hash query searches a single block
+	if _, err := service.queryFilter(FilterQuery{BlockHash: &crypto.Hash{1}}); err != nil {
+		t.Error(err)
+	}
+
+	// installed filters are checked when their logs are queried
+	id, err := service.NewFilter(context.Background(), FilterQuery{FromBlock: number(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.GetFilterLogs(context.Background(), id); err == nil || err.Error() != errBlockRangeTooLarge(101, 10).Error() {
+		t.Errorf("getFilterLogs: %v, want %v", err, errBlockRangeTooLarge(101, 10))
+	}
+	service.UninstallFilter(id)
+
+	// no limit
+	service.Config.MaxBlockRange = 0
+	if _, err := service.queryFilter(FilterQuery{FromBlock: number(0), ToBlock: number(100000)}); err != nil {
+		t.Errorf("unlimited range refused: %v", err)
+	}
+}
+
+func TestMaxFilters(t *testing.T) {
+	service, _, closeFilter := newTestFilter(&testBackend{}, &FilterConfig{MaxFilters: 2})
+	defer closeFilter()
+
+	// filters installed over two connections have their own cap
+	server := rpc.NewServer()
+	if err := server.RegisterName(MODULENAME, &FilterApi{filterService: service}); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client, other := rpc.DialInProc(server), rpc.DialInProc(server)
+	defer client.Close()
+	defer other.Close()
+
+	var first, id ID
+	if err := client.Call(&first, "filter_newBlockFilter"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(&id, "filter_newFilter", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	// every kind of filter is counted against the cap
+	want := errTooManyFilters(2).Error()
+	for _, method := range []string{"filter_newBlockFilter", "filter_newPendingTransactionFilter"} {
+		if err := client.Call(&id, method); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s over the cap: %v, want %s", method, err, want)
+		}
+	}
+	if err := client.Call(&id, "filter_newFilter", map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("filter_newFilter over the cap: %v, want %s", err, want)
+	}
+
+	// the other connection is not held by the filters of the first
+	for i := 0; i < 2; i++ {
+		if err := other.Call(&id, "filter_newPendingTransactionFilter"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := other.Call(&id, "filter_newBlockFilter"); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("second connection over the cap: %v, want %s", err, want)
+	}
+
+	// uninstalling a filter releases its slot
+	var uninstalled bool
+	if err := client.Call(&uninstalled, "filter_uninstallFilter", first); err != nil || !uninstalled {
+		t.Fatalf("uninstall filter: %v", err)
+	}
+	if err := client.Call(&id, "filter_newBlockFilter"); err != nil {
+		t.Fatal(err)
+	}
+
+	// no limit
+	service.Config.MaxFilters = 0
+	for i := 0; i < 3; i++ {
+		if _, err := service.NewBlockFilter(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
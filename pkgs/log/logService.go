@@ -1,6 +1,7 @@
 package log
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/AsynkronIT/protoactor-go/actor"
 	"github.com/drep-project/DREP-Chain/app"
@@ -29,6 +30,7 @@ var (
 type LogService struct {
 	Config *LogConfig
 	apis   []app.API
+	hook   *ModuleHook
 }
 
 // Name log name
@@ -51,9 +53,13 @@ func (logService *LogService) P2pMessages() map[int]interface{} {
 	return map[int]interface{}{}
 }
 
+// ApplyFlags set log level, module levels and log dir from command line flags
+func (logService *LogService) ApplyFlags(executeContext *app.ExecuteContext) {
+	setLogConfig(logService.Config, executeContext.Cli, executeContext.CommonConfig.HomeDir)
+}
+
 //Init init log format and output
 func (logService *LogService) Init(executeContext *app.ExecuteContext) error {
-	logService.setLogConfig(executeContext.Cli, executeContext.CommonConfig.HomeDir)
 	baseLogPath := path.Join(logService.Config.DataDir, "log")
 
 	wirter1 := &lumberjack.Logger{
@@ -81,26 +87,21 @@ func (logService *LogService) Init(executeContext *app.ExecuteContext) error {
 	}
 
 	if logService.Config.Vmodule != "" {
-		args := []interface{}{}
-		pairs := strings.Split(logService.Config.Vmodule, ";")
-		for _, pair := range pairs {
-			k_v := strings.Split(pair, "=")
-			if len(k_v) != 2 {
-				return errors.New("not correct module format")
-			}
-			args = append(args, k_v[0])
-			args = append(args, k_v[1])
+		args, err := parseVmodule(logService.Config.Vmodule)
+		if err != nil {
+			return err
 		}
 		mHook.SetModulesLevel(args...)
 	}
 	logrus.AddHook(mHook)
+	logService.hook = mHook
 
 	logService.apis = []app.API{
 		app.API{
 			Namespace: "log",
 			Version:   "1.0",
 			Service:   NewLogApi(mHook),
-			Public:    true,
+			Public:    false,
 		},
 	}
 	return nil
@@ -117,24 +118,72 @@ func (logService *LogService) Stop(executeContext *app.ExecuteContext) error {
 	return nil
 }
 
+// Reload apply the log level and module levels of a changed config, the log directory
+// takes effect after restart
+func (logService *LogService) Reload(executeContext *app.ExecuteContext, phase json.RawMessage) error {
+	config := *DefaultLogConfig
+	if phase != nil {
+		if err := json.Unmarshal(phase, &config); err != nil {
+			return err
+		}
+	}
+	setLogConfig(&config, executeContext.Cli, executeContext.CommonConfig.HomeDir)
+	lv, err := parserLevel(config.LogLevel)
+	if err != nil {
+		return err
+	}
+	var args []interface{}
+	if config.Vmodule != "" {
+		args, err = parseVmodule(config.Vmodule)
+		if err != nil {
+			return err
+		}
+	}
+
+	logService.hook.SetLevel(lv)
+	if len(args) > 0 {
+		if err := logService.hook.SetModulesLevel(args...); err != nil {
+			return err
+		}
+	}
+	config.DataDir = logService.Config.DataDir
+	logService.Config = &config
+	return nil
+}
+
 // Receive
 func (logService *LogService) Receive(context actor.Context) {}
 
 // setLogConfig creates an log configuration from the set command line flags,
-func (logService *LogService) setLogConfig(ctx *cli.Context, homeDir string) {
+func setLogConfig(config *LogConfig, ctx *cli.Context, homeDir string) {
 	if ctx.GlobalIsSet(LogLevelFlag.Name) {
-		logService.Config.LogLevel = ctx.GlobalInt(LogLevelFlag.Name)
+		config.LogLevel = ctx.GlobalInt(LogLevelFlag.Name)
 	}
 
 	if ctx.GlobalIsSet(VmoduleFlag.Name) {
-		logService.Config.Vmodule = ctx.GlobalString(VmoduleFlag.Name)
+		config.Vmodule = ctx.GlobalString(VmoduleFlag.Name)
 	}
 	//logdir
 	if ctx.GlobalIsSet(LogDirFlag.Name) {
-		logService.Config.DataDir = ctx.GlobalString(LogDirFlag.Name)
+		config.DataDir = ctx.GlobalString(LogDirFlag.Name)
 	} else {
-		logService.Config.DataDir = path.Join(homeDir, "log")
+		config.DataDir = path.Join(homeDir, "log")
+	}
+}
+
+// parseVmodule split module levels like "txpool=5;p2p=4" into the args of SetModulesLevel
+func parseVmodule(vmodule string) ([]interface{}, error) {
+	args := []interface{}{}
+	pairs := strings.Split(vmodule, ";")
+	for _, pair := range pairs {
+		k_v := strings.Split(pair, "=")
+		if len(k_v) != 2 {
+			return nil, errors.New("not correct module format")
+		}
+		args = append(args, k_v[0])
+		args = append(args, k_v[1])
 	}
+	return args, nil
 }
 
 // EnsureLogger create logger int other file
@@ -70,6 +70,8 @@ func (hook *ModuleHook) Levels() []log.Level {
 }
 
 func (hook *ModuleHook) SetLevel(lvInt log.Level) {
+	hook.lock.Lock()
+	defer hook.lock.Unlock()
 	log.SetLevel(lvInt)
 	for key, _ := range hook.moduleLevel {
 		hook.moduleLevel[key] = lvInt
@@ -0,0 +1,15 @@
+package metrics
+
+type MetricsConfig struct {
+	Enable     bool   `json:"enable"`
+	ListenAddr string `json:"listenAddr"`
+	Port       int    `json:"port"`
+}
+
+var (
+	DefaultConfig = &MetricsConfig{
+		Enable:     false,
+		ListenAddr: "127.0.0.1",
+		Port:       6060,
+	}
+)
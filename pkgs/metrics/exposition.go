@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ContentType of the prometheus text exposition format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	helpEscaper  = strings.NewReplacer("\\", `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer("\\", `\\`, "\n", `\n`, "\"", `\"`)
+)
+
+// WriteText write all collectors of registry to w in prometheus text format
+func (registry *Registry) WriteText(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	for _, collector := range registry.Collectors() {
+		name := collector.Name()
+		writer.WriteString("# HELP " + name + " " + helpEscaper.Replace(collector.Help()) + "\n")
+		writer.WriteString("# TYPE " + name + " " + collector.Type() + "\n")
+		for _, sample := range collector.Samples() {
+			writer.WriteString(name + sample.Suffix)
+			if len(sample.Labels) > 0 {
+				writer.WriteString("{")
+				for i, label := range sample.Labels {
+					if i > 0 {
+						writer.WriteString(",")
+					}
+					writer.WriteString(label.Name + "=\"" + labelEscaper.Replace(label.Value) + "\"")
+				}
+				writer.WriteString("}")
+			}
+			writer.WriteString(" " + formatFloat(sample.Value) + "\n")
+		}
+	}
+	return writer.Flush()
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
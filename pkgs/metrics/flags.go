@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	EnableMetricsFlag = cli.BoolFlag{
+		Name:  "metrics",
+		Usage: "Enable the metrics, health and readiness http endpoints",
+	}
+	MetricsAddrFlag = cli.StringFlag{
+		Name:  "metricsaddr",
+		Usage: "Metrics server listening interface",
+		Value: DefaultConfig.ListenAddr,
+	}
+	MetricsPortFlag = cli.IntFlag{
+		Name:  "metricsport",
+		Usage: "Metrics server listening port",
+		Value: DefaultConfig.Port,
+	}
+)
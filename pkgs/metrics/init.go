@@ -0,0 +1,13 @@
+package metrics
+
+import (
+	dlog "github.com/drep-project/DREP-Chain/pkgs/log"
+)
+
+const (
+	MODULENAME = "metrics"
+)
+
+var (
+	log = dlog.EnsureLogger(MODULENAME)
+)
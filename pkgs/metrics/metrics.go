@@ -0,0 +1,443 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+var (
+	// DefaultRegistry is the registry every service registers its metrics with,
+	// it is exported by the metrics service in prometheus text format.
+	DefaultRegistry = NewRegistry()
+
+	// DefaultBuckets are the histogram buckets (in seconds) used for latencies.
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// Label is a single name/value pair attached to a sample
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a collector at the time it is gathered
+type Sample struct {
+	Suffix string // appended to the metric name, eg "_bucket"
+	Labels []Label
+	Value  float64
+}
+
+// Collector is implemented by every metric kept in a Registry
+type Collector interface {
+	Name() string
+	Help() string
+	Type() string
+	Samples() []Sample
+}
+
+// Registry holds collectors by name
+type Registry struct {
+	lock       sync.RWMutex
+	collectors map[string]Collector
+}
+
+// NewRegistry create an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: make(map[string]Collector),
+	}
+}
+
+// Register add a collector into registry, a collector with the same name is replaced.
+// Replacing keeps services that are initialized more than once (eg in tests) from panicking.
+func (registry *Registry) Register(collector Collector) Collector {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	registry.collectors[collector.Name()] = collector
+	return collector
+}
+
+// Unregister remove the collector with the given name
+func (registry *Registry) Unregister(name string) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	delete(registry.collectors, name)
+}
+
+// Get return the collector registered with the given name
+func (registry *Registry) Get(name string) Collector {
+	registry.lock.RLock()
+	defer registry.lock.RUnlock()
+	return registry.collectors[name]
+}
+
+// Collectors return all collectors sorted by name
+func (registry *Registry) Collectors() []Collector {
+	registry.lock.RLock()
+	collectors := make([]Collector, 0, len(registry.collectors))
+	for _, collector := range registry.collectors {
+		collectors = append(collectors, collector)
+	}
+	registry.lock.RUnlock()
+
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].Name() < collectors[j].Name()
+	})
+	return collectors
+}
+
+type desc struct {
+	name string
+	help string
+}
+
+func (d *desc) Name() string {
+	return d.name
+}
+
+func (d *desc) Help() string {
+	return d.help
+}
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	desc
+	value uint64
+}
+
+// NewCounter create a counter and register it in the default registry
+func NewCounter(name, help string) *Counter {
+	counter := &Counter{desc: desc{name, help}}
+	DefaultRegistry.Register(counter)
+	return counter
+}
+
+func (counter *Counter) Type() string {
+	return TypeCounter
+}
+
+// Inc increase counter by one
+func (counter *Counter) Inc() {
+	atomic.AddUint64(&counter.value, 1)
+}
+
+// Add increase counter by delta
+func (counter *Counter) Add(delta uint64) {
+	atomic.AddUint64(&counter.value, delta)
+}
+
+// Value return current value of counter
+func (counter *Counter) Value() uint64 {
+	return atomic.LoadUint64(&counter.value)
+}
+
+func (counter *Counter) Samples() []Sample {
+	return []Sample{{Value: float64(counter.Value())}}
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	desc
+	bits uint64
+}
+
+// NewGauge create a gauge and register it in the default registry
+func NewGauge(name, help string) *Gauge {
+	gauge := &Gauge{desc: desc{name, help}}
+	DefaultRegistry.Register(gauge)
+	return gauge
+}
+
+func (gauge *Gauge) Type() string {
+	return TypeGauge
+}
+
+// Set replace the value of gauge
+func (gauge *Gauge) Set(value float64) {
+	atomic.StoreUint64(&gauge.bits, math.Float64bits(value))
+}
+
+// Add change the value of gauge by delta, delta may be negative
+func (gauge *Gauge) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&gauge.bits)
+		newBits := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&gauge.bits, old, newBits) {
+			return
+		}
+	}
+}
+
+// Value return current value of gauge
+func (gauge *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&gauge.bits))
+}
+
+func (gauge *Gauge) Samples() []Sample {
+	return []Sample{{Value: gauge.Value()}}
+}
+
+// GaugeFunc is a gauge whose value is read from a callback when gathered
+type GaugeFunc struct {
+	desc
+	fn func() float64
+}
+
+// NewGaugeFunc create a gauge func and register it in the default registry
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	gauge := &GaugeFunc{desc: desc{name, help}, fn: fn}
+	DefaultRegistry.Register(gauge)
+	return gauge
+}
+
+func (gauge *GaugeFunc) Type() string {
+	return TypeGauge
+}
+
+func (gauge *GaugeFunc) Samples() []Sample {
+	return []Sample{{Value: gauge.fn()}}
+}
+
+// Histogram counts observations in configurable buckets
+type Histogram struct {
+	desc
+	labels  []Label
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sumBits uint64
+}
+
+// NewHistogram create a histogram and register it in the default registry,
+// DefaultBuckets is used when buckets is empty
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	histogram := newHistogram(name, help, buckets, nil)
+	DefaultRegistry.Register(histogram)
+	return histogram
+}
+
+func newHistogram(name, help string, buckets []float64, labels []Label) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+	return &Histogram{
+		desc:    desc{name, help},
+		labels:  labels,
+		buckets: sorted,
+		counts:  make([]uint64, len(sorted)),
+	}
+}
+
+func (histogram *Histogram) Type() string {
+	return TypeHistogram
+}
+
+// Observe add a single observation
+func (histogram *Histogram) Observe(value float64) {
+	index := sort.SearchFloat64s(histogram.buckets, value)
+	if index < len(histogram.counts) {
+		atomic.AddUint64(&histogram.counts[index], 1)
+	}
+	atomic.AddUint64(&histogram.count, 1)
+	for {
+		old := atomic.LoadUint64(&histogram.sumBits)
+		newBits := math.Float64bits(math.Float64frombits(old) + value)
+		if atomic.CompareAndSwapUint64(&histogram.sumBits, old, newBits) {
+			return
+		}
+	}
+}
+
+// ObserveSince add the seconds elapsed since start as an observation
+func (histogram *Histogram) ObserveSince(start time.Time) {
+	histogram.Observe(time.Since(start).Seconds())
+}
+
+// Count return the number of observations
+func (histogram *Histogram) Count() uint64 {
+	return atomic.LoadUint64(&histogram.count)
+}
+
+func (histogram *Histogram) Samples() []Sample {
+	samples := make([]Sample, 0, len(histogram.buckets)+3)
+	cumulative := uint64(0)
+	for i, bound := range histogram.buckets {
+		cumulative += atomic.LoadUint64(&histogram.counts[i])
+		samples = append(samples, Sample{
+			Suffix: "_bucket",
+			Labels: withLabel(histogram.labels, "le", formatFloat(bound)),
+			Value:  float64(cumulative),
+		})
+	}
+	count := histogram.Count()
+	samples = append(samples,
+		Sample{Suffix: "_bucket", Labels: withLabel(histogram.labels, "le", "+Inf"), Value: float64(count)},
+		Sample{Suffix: "_sum", Labels: histogram.labels, Value: math.Float64frombits(atomic.LoadUint64(&histogram.sumBits))},
+		Sample{Suffix: "_count", Labels: histogram.labels, Value: float64(count)},
+	)
+	return samples
+}
+
+// CounterVec is a set of counters partitioned by label values
+type CounterVec struct {
+	desc
+	labelNames []string
+	lock       sync.RWMutex
+	counters   map[string]*labeledCounter
+}
+
+type labeledCounter struct {
+	labels []Label
+	Counter
+}
+
+// NewCounterVec create a counter vector and register it in the default registry
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	vec := &CounterVec{
+		desc:       desc{name, help},
+		labelNames: labelNames,
+		counters:   make(map[string]*labeledCounter),
+	}
+	DefaultRegistry.Register(vec)
+	return vec
+}
+
+func (vec *CounterVec) Type() string {
+	return TypeCounter
+}
+
+// WithLabelValues return the counter for the given label values, values must match label names in order
+func (vec *CounterVec) WithLabelValues(values ...string) *Counter {
+	key := strings.Join(values, "\xff")
+	vec.lock.RLock()
+	counter, ok := vec.counters[key]
+	vec.lock.RUnlock()
+	if ok {
+		return &counter.Counter
+	}
+
+	vec.lock.Lock()
+	defer vec.lock.Unlock()
+	if counter, ok = vec.counters[key]; !ok {
+		counter = &labeledCounter{labels: makeLabels(vec.labelNames, values)}
+		vec.counters[key] = counter
+	}
+	return &counter.Counter
+}
+
+func (vec *CounterVec) Samples() []Sample {
+	vec.lock.RLock()
+	defer vec.lock.RUnlock()
+	samples := make([]Sample, 0, len(vec.counters))
+	for _, counter := range vec.counters {
+		samples = append(samples, Sample{Labels: counter.labels, Value: float64(counter.Value())})
+	}
+	sortSamples(samples)
+	return samples
+}
+
+// HistogramVec is a set of histograms partitioned by label values
+type HistogramVec struct {
+	desc
+	labelNames []string
+	buckets    []float64
+	lock       sync.RWMutex
+	histograms map[string]*Histogram
+}
+
+// NewHistogramVec create a histogram vector and register it in the default registry
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	vec := &HistogramVec{
+		desc:       desc{name, help},
+		labelNames: labelNames,
+		buckets:    buckets,
+		histograms: make(map[string]*Histogram),
+	}
+	DefaultRegistry.Register(vec)
+	return vec
+}
+
+func (vec *HistogramVec) Type() string {
+	return TypeHistogram
+}
+
+// WithLabelValues return the histogram for the given label values, values must match label names in order
+func (vec *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	key := strings.Join(values, "\xff")
+	vec.lock.RLock()
+	histogram, ok := vec.histograms[key]
+	vec.lock.RUnlock()
+	if ok {
+		return histogram
+	}
+
+	vec.lock.Lock()
+	defer vec.lock.Unlock()
+	if histogram, ok = vec.histograms[key]; !ok {
+		histogram = newHistogram(vec.name, vec.help, vec.buckets, makeLabels(vec.labelNames, values))
+		vec.histograms[key] = histogram
+	}
+	return histogram
+}
+
+func (vec *HistogramVec) Samples() []Sample {
+	vec.lock.RLock()
+	histograms := make([]*Histogram, 0, len(vec.histograms))
+	for _, histogram := range vec.histograms {
+		histograms = append(histograms, histogram)
+	}
+	vec.lock.RUnlock()
+
+	sort.Slice(histograms, func(i, j int) bool {
+		return labelsKey(histograms[i].labels) < labelsKey(histograms[j].labels)
+	})
+	samples := []Sample{}
+	for _, histogram := range histograms {
+		samples = append(samples, histogram.Samples()...)
+	}
+	return samples
+}
+
+func makeLabels(names []string, values []string) []Label {
+	labels := make([]Label, len(names))
+	for i, name := range names {
+		labels[i].Name = name
+		if i < len(values) {
+			labels[i].Value = values[i]
+		}
+	}
+	return labels
+}
+
+func withLabel(labels []Label, name, value string) []Label {
+	newLabels := make([]Label, 0, len(labels)+1)
+	newLabels = append(newLabels, labels...)
+	return append(newLabels, Label{name, value})
+}
+
+func labelsKey(labels []Label) string {
+	values := make([]string, len(labels))
+	for i, label := range labels {
+		values[i] = label.Value
+	}
+	return strings.Join(values, "\xff")
+}
+
+func sortSamples(samples []Sample) {
+	sort.Slice(samples, func(i, j int) bool {
+		return labelsKey(samples[i].Labels) < labelsKey(samples[j].Labels)
+	})
+}
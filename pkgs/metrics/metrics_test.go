@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.Register(&Counter{desc: desc{"test_total", "a counter"}}).(*Counter)
+	gauge := registry.Register(&Gauge{desc: desc{"test_gauge", "a gauge"}}).(*Gauge)
+	histogram := registry.Register(newHistogram("test_seconds", "a histogram", []float64{1, 2}, nil)).(*Histogram)
+
+	counter.Add(3)
+	gauge.Set(1.5)
+	gauge.Add(-0.5)
+	histogram.Observe(0.5)
+	histogram.Observe(1.5)
+	histogram.Observe(10)
+
+	buf := bytes.NewBuffer(nil)
+	if err := registry.WriteText(buf); err != nil {
+		t.Fatal(err)
+	}
+	expect := strings.Join([]string{
+		"# HELP test_gauge a gauge",
+		"# TYPE test_gauge gauge",
+		"test_gauge 1",
+		"# HELP test_seconds a histogram",
+		"# TYPE test_seconds histogram",
+		`test_seconds_bucket{le="1"} 1`,
+		`test_seconds_bucket{le="2"} 2`,
+		`test_seconds_bucket{le="+Inf"} 3`,
+		"test_seconds_sum 12",
+		"test_seconds_count 3",
+		"# HELP test_total a counter",
+		"# TYPE test_total counter",
+		"test_total 3",
+		"",
+	}, "\n")
+	if buf.String() != expect {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), expect)
+	}
+}
+
+func TestCounterVec(t *testing.T) {
+	vec := NewCounterVec("test_vec_total", "a counter vec", "result")
+	defer DefaultRegistry.Unregister("test_vec_total")
+
+	vec.WithLabelValues("success").Inc()
+	vec.WithLabelValues("success").Inc()
+	vec.WithLabelValues("fail").Inc()
+
+	samples := vec.Samples()
+	if len(samples) != 2 {
+		t.Fatalf("expect 2 samples, got %d", len(samples))
+	}
+	if samples[0].Labels[0].Value != "fail" || samples[0].Value != 1 {
+		t.Errorf("unexpected sample %v", samples[0])
+	}
+	if samples[1].Labels[0].Value != "success" || samples[1].Value != 2 {
+		t.Errorf("unexpected sample %v", samples[1])
+	}
+}
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/app"
+	"github.com/drep-project/DREP-Chain/types"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// ChainReader is the part of chain service used to report the current height
+type ChainReader interface {
+	GetCurrentHeader() *types.BlockHeader
+}
+
+// SyncReader is the part of blockmgr used to report sync status
+type SyncReader interface {
+	IsSyncing() bool
+}
+
+// HealthStatus is the body returned by the health and readiness endpoints
+type HealthStatus struct {
+	Status  string `json:"status"`
+	Height  uint64 `json:"height"`
+	Syncing bool   `json:"syncing"`
+	Uptime  string `json:"uptime"`
+}
+
+// MetricsService serves the metrics registered by all services in prometheus text format,
+// together with /healthz and /ready endpoints for orchestration.
+type MetricsService struct {
+	ChainService ChainReader `service:"chain"`
+	SyncState    SyncReader  `service:"blockmgr"`
+	Config       *MetricsConfig
+
+	startTime time.Time
+	listener  net.Listener
+	server    *http.Server
+}
+
+func (metricsService *MetricsService) Name() string {
+	return MODULENAME
+}
+
+func (metricsService *MetricsService) Api() []app.API {
+	return []app.API{}
+}
+
+func (metricsService *MetricsService) CommandFlags() ([]cli.Command, []cli.Flag) {
+	return nil, []cli.Flag{EnableMetricsFlag, MetricsAddrFlag, MetricsPortFlag}
+}
+
+func (metricsService *MetricsService) ApplyFlags(executeContext *app.ExecuteContext) {
+	ctx := executeContext.Cli
+	if ctx.GlobalIsSet(EnableMetricsFlag.Name) {
+		metricsService.Config.Enable = ctx.GlobalBool(EnableMetricsFlag.Name)
+	}
+	if ctx.GlobalIsSet(MetricsAddrFlag.Name) {
+		metricsService.Config.ListenAddr = ctx.GlobalString(MetricsAddrFlag.Name)
+	}
+	if ctx.GlobalIsSet(MetricsPortFlag.Name) {
+		metricsService.Config.Port = ctx.GlobalInt(MetricsPortFlag.Name)
+	}
+}
+
+// ListenPorts report the port of metrics server if enabled
+func (metricsService *MetricsService) ListenPorts() map[string]int {
+	if !metricsService.Config.Enable {
+		return nil
+	}
+	return map[string]int{"/" + MODULENAME + "/port": metricsService.Config.Port}
+}
+
+func (metricsService *MetricsService) Init(executeContext *app.ExecuteContext) error {
+	metricsService.startTime = time.Now()
+
+	NewGaugeFunc("node_uptime_seconds", "Seconds since the node process started", func() float64 {
+		return time.Since(metricsService.startTime).Seconds()
+	})
+	return nil
+}
+
+func (metricsService *MetricsService) Start(executeContext *app.ExecuteContext) error {
+	if !metricsService.Config.Enable {
+		return nil
+	}
+	endpoint := fmt.Sprintf("%s:%d", metricsService.Config.ListenAddr, metricsService.Config.Port)
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", metricsService.handleMetrics)
+	mux.HandleFunc("/healthz", metricsService.handleHealth)
+	mux.HandleFunc("/ready", metricsService.handleReady)
+
+	metricsService.listener = listener
+	metricsService.server = &http.Server{Handler: mux}
+	go metricsService.server.Serve(listener)
+	log.WithField("url", fmt.Sprintf("http://%s/metrics", endpoint)).Info("Metrics endpoint opened")
+	return nil
+}
+
+func (metricsService *MetricsService) Stop(executeContext *app.ExecuteContext) error {
+	if metricsService.server == nil {
+		return nil
+	}
+	err := metricsService.server.Close()
+	metricsService.server = nil
+	metricsService.listener = nil
+	log.Info("Metrics endpoint closed")
+	return err
+}
+
+func (metricsService *MetricsService) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	if err := DefaultRegistry.WriteText(w); err != nil {
+		log.WithField("Reason", err).Warn("write metrics")
+	}
+}
+
+// handleHealth report the process is alive and able to answer requests
+func (metricsService *MetricsService) handleHealth(w http.ResponseWriter, r *http.Request) {
+	status := metricsService.status()
+	status.Status = "ok"
+	writeStatus(w, http.StatusOK, status)
+}
+
+// handleReady report whether the node has caught up with its peers and can serve requests
+func (metricsService *MetricsService) handleReady(w http.ResponseWriter, r *http.Request) {
+	status := metricsService.status()
+	if metricsService.ChainService == nil || metricsService.ChainService.GetCurrentHeader() == nil {
+		status.Status = "chain not ready"
+		writeStatus(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	if status.Syncing {
+		status.Status = "syncing"
+		writeStatus(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	status.Status = "ready"
+	writeStatus(w, http.StatusOK, status)
+}
+
+func (metricsService *MetricsService) status() *HealthStatus {
+	status := &HealthStatus{
+		Uptime: time.Since(metricsService.startTime).Round(time.Second).String(),
+	}
+	if metricsService.ChainService != nil {
+		if header := metricsService.ChainService.GetCurrentHeader(); header != nil {
+			status.Height = header.Height
+		}
+	}
+	if metricsService.SyncState != nil {
+		status.Syncing = metricsService.SyncState.IsSyncing()
+	}
+	return status
+}
+
+func writeStatus(w http.ResponseWriter, code int, status *HealthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
+
+func (metricsService *MetricsService) DefaultConfig() *MetricsConfig {
+	return DefaultConfig
+}
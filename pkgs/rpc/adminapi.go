@@ -0,0 +1,31 @@
+package rpc
+
+import (
+	"github.com/drep-project/DREP-Chain/app"
+)
+
+/*
+name: 节点管理rpc接口
+usage: 节点运行时管理
+prefix:admin
+*/
+type AdminApi struct {
+	executeContext *app.ExecuteContext
+}
+
+func NewAdminApi(executeContext *app.ExecuteContext) *AdminApi {
+	return &AdminApi{executeContext}
+}
+
+/*
+ name: reloadConfig
+ usage: 重新读取配置文件，支持热更新的服务(log, rpc, blockmgr)立即应用改变的配置，与发送SIGHUP信号效果相同
+ params: 无
+ return: 重新加载了配置的服务
+ example:  curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"admin_reloadConfig","params":[], "id": 3}' -H "Content-Type:application/json"
+ response:
+  {"jsonrpc":"2.0","id":3,"result":["log","blockmgr"]}
+*/
+func (adminApi *AdminApi) ReloadConfig() ([]string, error) {
+	return adminApi.executeContext.ReloadConfig()
+}
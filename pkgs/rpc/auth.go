@@ -0,0 +1,315 @@
+package rpc
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/app"
+)
+
+const (
+	// json rpc error codes of requests rejected before reaching the rpc server
+	ErrCodeUnauthorized = -32001
+	ErrCodeForbidden    = -32003
+
+	methodSeparator = "_"
+	allNamespaces   = "*"
+)
+
+var (
+	ErrCredentialRequired = errors.New("credential required")
+	ErrInvalidCredential  = errors.New("invalid credential")
+	ErrTokenExpired       = errors.New("token expired")
+	ErrRequestTooLarge    = errors.New("request too large")
+)
+
+// permission is the set of namespaces and methods granted to a credential
+type permission struct {
+	name       string
+	all        bool
+	namespaces map[string]bool
+	methods    map[string]bool
+}
+
+func newPermission(credential *Credential) *permission {
+	perm := &permission{
+		name:       credential.Name,
+		namespaces: map[string]bool{},
+		methods:    map[string]bool{},
+	}
+	for _, namespace := range credential.Namespaces {
+		if namespace == allNamespaces {
+			perm.all = true
+		}
+		perm.namespaces[namespace] = true
+	}
+	for _, method := range credential.Methods {
+		perm.methods[method] = true
+	}
+	return perm
+}
+
+// authenticator checks the credential of requests to http and websocket endpoints and whether
+// the methods called are granted to it. Methods of public apis are granted to every credential,
+// and to requests without credential if anonymous access is allowed.
+type authenticator struct {
+	config      AuthConfig
+	public      map[string]bool
+	keys        map[string]*permission
+	subjects    map[string]*permission
+	credentials []*permission
+}
+
+func newAuthenticator(config AuthConfig, apis []app.API) *authenticator {
+	auth := &authenticator{
+		config:   config,
+		public:   map[string]bool{},
+		keys:     map[string]*permission{},
+		subjects: map[string]*permission{},
+	}
+	for _, api := range apis {
+		if api.Public {
+			auth.public[api.Namespace] = true
+		}
+	}
+	for _, credential := range config.Credentials {
+		perm := newPermission(credential)
+		auth.credentials = append(auth.credentials, perm)
+		auth.subjects[credential.Name] = perm
+		if credential.Key != "" {
+			auth.keys[credential.Key] = perm
+		}
+	}
+	return auth
+}
+
+// authenticate return the permission of the credential carried by request, nil if there is none
+func (auth *authenticator) authenticate(r *http.Request) (*permission, error) {
+	token := ""
+	if header := r.Header.Get("Authorization"); header != "" {
+		if !strings.HasPrefix(header, "Bearer ") {
+			return nil, ErrInvalidCredential
+		}
+		token = strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	} else {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		if !auth.config.AllowAnonymous {
+			return nil, ErrCredentialRequired
+		}
+		return nil, nil
+	}
+	if strings.Count(token, ".") == 2 {
+		return auth.verifyJWT(token)
+	}
+	for key, perm := range auth.keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			return perm, nil
+		}
+	}
+	return nil, ErrInvalidCredential
+}
+
+// verifyJWT check a HS256 token signed with JwtSecret, its subject must be a configured credential
+func (auth *authenticator) verifyJWT(token string) (*permission, error) {
+	if auth.config.JwtSecret == "" {
+		return nil, ErrInvalidCredential
+	}
+	parts := strings.Split(token, ".")
+	header := struct {
+		Alg string `json:"alg"`
+	}{}
+	if err := decodeJWTPart(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return nil, ErrInvalidCredential
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCredential
+	}
+	mac := hmac.New(sha256.New, []byte(auth.config.JwtSecret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, ErrInvalidCredential
+	}
+
+	claims := struct {
+		Subject   string `json:"sub"`
+		ExpiresAt int64  `json:"exp"`
+		NotBefore int64  `json:"nbf"`
+	}{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, ErrInvalidCredential
+	}
+	now := time.Now().Unix()
+	if claims.ExpiresAt != 0 && now >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return nil, ErrInvalidCredential
+	}
+	perm, ok := auth.subjects[claims.Subject]
+	if !ok {
+		return nil, ErrInvalidCredential
+	}
+	return perm, nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	content, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, v)
+}
+
+// allowMethod check whether method can be called with perm, perm is nil for anonymous request
+func (auth *authenticator) allowMethod(perm *permission, method string) bool {
+	namespace := method
+	if index := strings.Index(method, methodSeparator); index >= 0 {
+		namespace = method[:index]
+	}
+	if auth.public[namespace] {
+		return true
+	}
+	if perm == nil {
+		return false
+	}
+	return perm.all || perm.namespaces[namespace] || perm.methods[method]
+}
+
+// allowNamespace check whether every method of namespace can be called with perm
+func (auth *authenticator) allowNamespace(perm *permission, namespace string) bool {
+	if auth.public[namespace] {
+		return true
+	}
+	return perm != nil && (perm.all || perm.namespaces[namespace])
+}
+
+// httpHandler rejects requests with invalid credential or calling methods not granted to the
+// credential, batch requests are rejected as a whole
+func (auth *authenticator) httpHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		perm, err := auth.authenticate(r)
+		if err != nil {
+			writeRPCError(w, http.StatusUnauthorized, nil, ErrCodeUnauthorized, err.Error())
+			return
+		}
+		body, ok := inspectBody(w, r)
+		if !ok {
+			return
+		}
+		for _, msg := range requestMessages(body) {
+			if !auth.allowMethod(perm, msg.Method) {
+				writeRPCError(w, http.StatusForbidden, msg.ID, ErrCodeForbidden, fmt.Sprintf("method %s not allowed", msg.Method))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wsHandler rejects websocket handshakes with invalid credential, the methods called on the
+// connection are checked by the messageCheck of wsCheck
+func (auth *authenticator) wsHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := auth.authenticate(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wsCheck return the check of the messages of a websocket connection opened by r, messages
+// calling methods not granted to its credential are rejected like http requests
+func (auth *authenticator) wsCheck(r *http.Request) messageCheck {
+	perm, err := auth.authenticate(r)
+	return func(msgs []rpcMessage) *rpcError {
+		if err != nil {
+			return &rpcError{Code: ErrCodeUnauthorized, Message: err.Error()}
+		}
+		for _, msg := range msgs {
+			if !auth.allowMethod(perm, msg.Method) {
+				return &rpcError{Code: ErrCodeForbidden, Message: fmt.Sprintf("method %s not allowed", msg.Method)}
+			}
+		}
+		return nil
+	}
+}
+
+// inspectBody read the body of r and put it back for the next handler, an error response is
+// written if it can not be read or is too large
+func inspectBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxInspectSize+1))
+	r.Body.Close()
+	if err != nil {
+		writeRPCError(w, http.StatusBadRequest, nil, ErrCodeLimitExceeded, err.Error())
+		return nil, false
+	}
+	if len(body) > maxInspectSize {
+		writeRPCError(w, http.StatusRequestEntityTooLarge, nil, ErrCodeLimitExceeded, ErrRequestTooLarge.Error())
+		return nil, false
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, true
+}
+
+// rpcMessage is the part of a json rpc request checked before it is served
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+// requestMessages parse a single or batch json rpc request, an invalid body yields a message
+// without method which is only allowed to credentials granted all namespaces
+func requestMessages(body []byte) []rpcMessage {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		msgs := []rpcMessage{}
+		if err := json.Unmarshal(body, &msgs); err != nil {
+			return []rpcMessage{{}}
+		}
+		return msgs
+	}
+	msg := rpcMessage{}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return []rpcMessage{{}}
+	}
+	return []rpcMessage{msg}
+}
+
+// writeRPCError reply a json rpc error response
+func writeRPCError(w http.ResponseWriter, status int, id json.RawMessage, code int, message string) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	resp := struct {
+		Version string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Error   struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}{Version: "2.0", ID: id}
+	resp.Error.Code = code
+	resp.Error.Message = message
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drep-project/DREP-Chain/app"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func testAuthenticator() *authenticator {
+	return newAuthenticator(AuthConfig{
+		Enable:         true,
+		AllowAnonymous: true,
+		JwtSecret:      testSecret,
+		Credentials: Credentials{
+			{Name: "admin", Key: "adminkey", Namespaces: []string{"*"}},
+			{Name: "wallet", Methods: []string{"account_listAddress"}},
+		},
+	}, []app.API{
+		{Namespace: "chain", Public: true},
+		{Namespace: "account", Public: false},
+	})
+}
+
+func signJWT(claims string) string {
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + encoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + encoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyJWT(t *testing.T) {
+	auth := testAuthenticator()
+	exp := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	perm, err := auth.verifyJWT(signJWT(`{"sub":"wallet","exp":` + exp + `}`))
+	if err != nil || perm.name != "wallet" {
+		t.Fatalf("expect wallet permission, got %v %v", perm, err)
+	}
+	if _, err = auth.verifyJWT(signJWT(`{"sub":"wallet","exp":1}`)); err != ErrTokenExpired {
+		t.Errorf("expect expired token, got %v", err)
+	}
+	if _, err = auth.verifyJWT(signJWT(`{"sub":"nobody"}`)); err != ErrInvalidCredential {
+		t.Errorf("expect unknown subject rejected, got %v", err)
+	}
+	token := signJWT(`{"sub":"admin"}`)
+	if _, err = auth.verifyJWT(token[:len(token)-2] + "xx"); err != ErrInvalidCredential {
+		t.Errorf("expect bad signature rejected, got %v", err)
+	}
+}
+
+func TestAuthHTTPHandler(t *testing.T) {
+	auth := testAuthenticator()
+	handler := auth.httpHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	cases := []struct {
+		token  string
+		body   string
+		status int
+	}{
+		{"", `{"id":1,"method":"chain_getBalance"}`, http.StatusOK},
+		{"", `{"id":1,"method":"account_listAddress"}`, http.StatusForbidden},
+		{"badkey", `{"id":1,"method":"chain_getBalance"}`, http.StatusUnauthorized},
+		{"adminkey", `{"id":1,"method":"account_dumpPrivkey"}`, http.StatusOK},
+		{signJWT(`{"sub":"wallet"}`), `[{"id":1,"method":"account_listAddress"},{"id":2,"method":"chain_getBalance"}]`, http.StatusOK},
+		{signJWT(`{"sub":"wallet"}`), `[{"id":1,"method":"account_listAddress"},{"id":2,"method":"account_dumpPrivkey"}]`, http.StatusForbidden},
+	}
+	for i, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.body))
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if recorder.Code != c.status {
+			t.Errorf("case %d: expect status %d, got %d %s", i, c.status, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestAuthWSCheck(t *testing.T) {
+	auth := testAuthenticator()
+	cases := []struct {
+		token string
+		body  string
+		code  int
+	}{
+		{"", `{"id":1,"method":"chain_getBalance"}`, 0},
+		{"", `{"id":1,"method":"account_listAddress"}`, ErrCodeForbidden},
+		{"", `{"id":1,"method":"account_subscribe"}`, ErrCodeForbidden},
+		{"badkey", `{"id":1,"method":"chain_getBalance"}`, ErrCodeUnauthorized},
+		{signJWT(`{"sub":"wallet"}`), `{"id":1,"method":"account_listAddress"}`, 0},
+		{signJWT(`{"sub":"wallet"}`), `[{"id":1,"method":"chain_getBalance"},{"id":2,"method":"account_dumpPrivkey"}]`, ErrCodeForbidden},
+	}
+	for i, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/?token="+c.token, nil)
+		reject := auth.wsCheck(req)(requestMessages([]byte(c.body)))
+		if c.code == 0 && reject != nil {
+			t.Errorf("case %d: expect message allowed, got %v", i, reject)
+		}
+		if c.code != 0 && (reject == nil || reject.Code != c.code) {
+			t.Errorf("case %d: expect error code %d, got %v", i, c.code, reject)
+		}
+	}
+}
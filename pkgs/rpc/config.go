@@ -0,0 +1,124 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/drep-project/DREP-Chain/app"
+	"github.com/drep-project/rpc"
+)
+
+// RpcConfig extends the endpoint config of rpc library with access control and request limits of
+// the http and websocket endpoints, the embedded fields keep their keys in config file
+type RpcConfig struct {
+	rpc.RpcConfig
+	Auth   AuthConfig  `json:"auth"`
+	Limits LimitConfig `json:"limits"`
+}
+
+// LimitConfig bounds the load a client can put on http and websocket endpoints. Requests with a
+// credential are limited per credential, others per client ip. Each call of a batch counts as
+// one request, a zero rate or size disables the limit.
+type LimitConfig struct {
+	// requests per second and burst allowed to a client ip
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	Burst             int     `json:"burst"`
+	// requests per second and burst allowed to a credential
+	CredentialRequestsPerSecond float64 `json:"credentialRequestsPerSecond"`
+	CredentialBurst             int     `json:"credentialBurst"`
+	// max number of calls in a batch request
+	MaxBatchSize int `json:"maxBatchSize"`
+	// max number of open websocket connections of a client ip
+	MaxWSConnections int `json:"maxWSConnections"`
+}
+
+// AuthConfig controls who can call the apis served on http and websocket endpoints.
+// Requests carry a static api key or a HS256 JWT signed with JwtSecret in the Authorization
+// header ("Bearer <token>"), websocket clients may also pass it in the token query parameter.
+type AuthConfig struct {
+	Enable bool `json:"enable"`
+	// requests without credential are allowed to call the methods of public apis
+	AllowAnonymous bool        `json:"allowAnonymous"`
+	JwtSecret      string      `json:"jwtSecret,omitempty"`
+	Credentials    Credentials `json:"credentials,omitempty"`
+}
+
+// Credential is a static api key or the subject of JWT with the namespaces and methods it may call
+type Credential struct {
+	Name string `json:"name"`
+	// static api key, empty if the credential is only used as JWT subject
+	Key string `json:"key,omitempty"`
+	// namespaces allowed, "*" allows all
+	Namespaces []string `json:"namespaces,omitempty"`
+	// full method names allowed, eg "account_listAddress"
+	Methods []string `json:"methods,omitempty"`
+}
+
+type Credentials []*Credential
+
+const (
+	minJwtSecretLength = 32
+)
+
+// ValidateConfig check the request limits and the credentials of rpc auth
+func (rpcService *RpcService) ValidateConfig(executeContext *app.ExecuteContext) app.ConfigErrors {
+	return rpcService.Config.validate()
+}
+
+// validate check the config loaded at start or reload
+func (config *RpcConfig) validate() app.ConfigErrors {
+	errs := validateLimits(config.Limits)
+	auth := config.Auth
+	if !auth.Enable {
+		return errs
+	}
+	if auth.JwtSecret != "" && len(auth.JwtSecret) < minJwtSecretLength {
+		errs = append(errs, app.NewConfigError(fmt.Sprintf("must be at least %d characters", minJwtSecretLength), MODULENAME, "auth", "jwtSecret"))
+	}
+	if !auth.AllowAnonymous && len(auth.Credentials) == 0 {
+		errs = append(errs, app.NewConfigError("no credential can access rpc while anonymous access is not allowed", MODULENAME, "auth", "credentials"))
+	}
+	names := map[string]int{}
+	keys := map[string]int{}
+	for i, credential := range auth.Credentials {
+		if credential.Name == "" {
+			errs = append(errs, app.NewConfigError("name is required", MODULENAME, "auth", "credentials", i, "name"))
+		} else if j, ok := names[credential.Name]; ok {
+			errs = append(errs, app.NewConfigError(fmt.Sprintf("duplicate name of /%s/auth/credentials/%d", MODULENAME, j), MODULENAME, "auth", "credentials", i, "name"))
+		} else {
+			names[credential.Name] = i
+		}
+		if credential.Key == "" && auth.JwtSecret == "" {
+			errs = append(errs, app.NewConfigError("key is required when jwtSecret is not set", MODULENAME, "auth", "credentials", i, "key"))
+		}
+		if credential.Key != "" {
+			if j, ok := keys[credential.Key]; ok {
+				errs = append(errs, app.NewConfigError(fmt.Sprintf("duplicate key of /%s/auth/credentials/%d", MODULENAME, j), MODULENAME, "auth", "credentials", i, "key"))
+			}
+			keys[credential.Key] = i
+		}
+	}
+	return errs
+}
+
+func validateLimits(limits LimitConfig) app.ConfigErrors {
+	var errs app.ConfigErrors
+	if limits.RequestsPerSecond < 0 {
+		errs = append(errs, app.NewConfigError("must not be negative", MODULENAME, "limits", "requestsPerSecond"))
+	}
+	if limits.RequestsPerSecond > 0 && limits.Burst < 1 {
+		errs = append(errs, app.NewConfigError("must be at least 1 when requestsPerSecond is set", MODULENAME, "limits", "burst"))
+	}
+	if limits.CredentialRequestsPerSecond < 0 {
+		errs = append(errs, app.NewConfigError("must not be negative", MODULENAME, "limits", "credentialRequestsPerSecond"))
+	}
+	if limits.CredentialRequestsPerSecond > 0 && limits.CredentialBurst < 1 {
+		errs = append(errs, app.NewConfigError("must be at least 1 when credentialRequestsPerSecond is set", MODULENAME, "limits", "credentialBurst"))
+	}
+	if limits.MaxBatchSize < 0 {
+		errs = append(errs, app.NewConfigError("must not be negative", MODULENAME, "limits", "maxBatchSize"))
+	}
+	if limits.MaxWSConnections < 0 {
+		errs = append(errs, app.NewConfigError("must not be negative", MODULENAME, "limits", "maxWSConnections"))
+	}
+	return errs
+}
@@ -0,0 +1,28 @@
+package rpc
+
+import (
+	"testing"
+)
+
+func TestValidateCredentials(t *testing.T) {
+	config := &RpcConfig{Auth: AuthConfig{
+		Enable: true,
+		Credentials: Credentials{
+			{Name: "admin", Key: "adminkey", Namespaces: []string{"*"}},
+			{Name: "admin", Key: "adminkey"},
+			{Name: "wallet"},
+		},
+	}}
+	paths := map[string]bool{}
+	for _, err := range config.validate() {
+		paths[err.Path] = true
+	}
+	for _, path := range []string{"/rpc/auth/credentials/1/name", "/rpc/auth/credentials/1/key", "/rpc/auth/credentials/2/key"} {
+		if !paths[path] {
+			t.Errorf("expect error of %s, got %v", path, paths)
+		}
+	}
+	if len(paths) != 3 {
+		t.Errorf("expect 3 errors, got %v", paths)
+	}
+}
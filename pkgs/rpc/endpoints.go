@@ -18,13 +18,17 @@ package rpc
 
 import (
 	"net"
+	"net/http"
 
 	"github.com/drep-project/DREP-Chain/app"
 	"github.com/drep-project/rpc"
 )
 
-// StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules
-func StartHTTPEndpoint(endpoint string, apis []app.API, modules []string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts) (net.Listener, *rpc.Server, error) {
+// StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules.
+// Without modules only public apis are served, unless auth is enabled in which case all apis
+// are served and each request is checked against the permission of its credential. Requests over
+// the limits are rejected before they are authorized.
+func StartHTTPEndpoint(endpoint string, apis []app.API, modules []string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts, auth AuthConfig, limits LimitConfig) (net.Listener, *rpc.Server, error) {
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
 	for _, module := range modules {
@@ -32,11 +36,13 @@ func StartHTTPEndpoint(endpoint string, apis []app.API, modules []string, cors [
 	}
 	// Register all the APIs exposed by the services
 	handler := rpc.NewServer()
+	registered := []app.API{}
 	for _, api := range apis {
-		if whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
+		if whitelist[api.Namespace] || (len(whitelist) == 0 && (api.Public || auth.Enable)) {
 			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
 				return nil, nil, err
 			}
+			registered = append(registered, api)
 			log.WithField("namespace", api.Namespace).Debug("HTTP registered")
 		}
 	}
@@ -48,12 +54,24 @@ func StartHTTPEndpoint(endpoint string, apis []app.API, modules []string, cors [
 	if listener, err = net.Listen("tcp", endpoint); err != nil {
 		return nil, nil, err
 	}
-	go rpc.NewHTTPServer(cors, vhosts, timeouts, handler).Serve(listener)
+	httpServer := rpc.NewHTTPServer(cors, vhosts, timeouts, handler)
+	httpServer.Handler = withClientIP(httpServer.Handler)
+	var authenticator *authenticator
+	if auth.Enable {
+		authenticator = newAuthenticator(auth, apis)
+		httpServer.Handler = authenticator.httpHandler(httpServer.Handler)
+	}
+	httpServer.Handler = newRequestLimiter(limits, authenticator).httpHandler(httpServer.Handler)
+	httpServer.Handler = newRequestMetrics(registered).instrumentHandler(httpServer.Handler)
+	go httpServer.Serve(listener)
 	return listener, handler, err
 }
 
-// StartWSEndpoint starts a websocket endpoint
-func StartWSEndpoint(endpoint string, apis []app.API, modules []string, wsOrigins []string, exposeAll bool) (net.Listener, *rpc.Server, error) {
+// StartWSEndpoint starts a websocket endpoint, every message received is counted in the request
+// metrics like the http requests. If auth is enabled the credential is checked at handshake and
+// every message is checked against the permission of the credential of its connection.
+// Handshakes and messages over the limits are rejected like http requests.
+func StartWSEndpoint(endpoint string, apis []app.API, modules []string, wsOrigins []string, exposeAll bool, auth AuthConfig, limits LimitConfig) (net.Listener, *rpc.Server, error) {
 
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
@@ -62,23 +80,44 @@ func StartWSEndpoint(endpoint string, apis []app.API, modules []string, wsOrigin
 	}
 	// Register all the APIs exposed by the services
 	handler := rpc.NewServer()
+	exposed := []app.API{}
 	for _, api := range apis {
-		if exposeAll || whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
+		if exposeAll || whitelist[api.Namespace] || (len(whitelist) == 0 && (api.Public || auth.Enable)) {
 			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+				handler.Stop()
 				return nil, nil, err
 			}
+			exposed = append(exposed, api)
 			log.WithField("service", api.Service).WithField("namespace", api.Namespace).Debug("WebSocket registered")
 		}
 	}
+
+	var (
+		authenticator *authenticator
+		wsHandler     http.Handler
+	)
+	if auth.Enable {
+		authenticator = newAuthenticator(auth, apis)
+	}
+	limiter := newRequestLimiter(limits, authenticator)
+	if auth.Enable {
+		wsHandler = newWSHandler(wsOrigins, handler, newRequestMetrics(exposed), limiter.wsCheck, authenticator.wsCheck)
+		wsHandler = authenticator.wsHandler(wsHandler)
+	} else {
+		wsHandler = newWSHandler(wsOrigins, handler, newRequestMetrics(exposed), limiter.wsCheck)
+	}
+	wsHandler = limiter.wsHandler(wsHandler)
+
 	// All APIs registered, start the HTTP listener
 	var (
 		listener net.Listener
 		err      error
 	)
 	if listener, err = net.Listen("tcp", endpoint); err != nil {
+		handler.Stop()
 		return nil, nil, err
 	}
-	go rpc.NewWSServer(wsOrigins, handler).Serve(listener)
+	go (&http.Server{Handler: wsHandler}).Serve(listener)
 	return listener, handler, err
 
 }
@@ -0,0 +1,151 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/drep-project/DREP-Chain/app"
+	"github.com/drep-project/DREP-Chain/pkgs/metrics"
+)
+
+const (
+	maxInspectSize = 5 * 1024 * 1024
+
+	// websocket requests timed at once by a connection, later ones are only counted
+	maxTimedRequests = 1024
+
+	unknownMethod = "unknown"
+	batchMethod   = "batch"
+)
+
+var (
+	rpcRequestTimer   = metrics.NewHistogramVec("rpc_request_seconds", "Latency of rpc requests by method", nil, "method")
+	rpcRequestCounter = metrics.NewCounterVec("rpc_requests_total", "Number of rpc requests by method", "method")
+)
+
+// requestMetrics labels the requests of an endpoint by the rpc method called. Only the methods
+// registered on the endpoint are used as label, anything else is counted as "unknown" so that
+// clients can not create unbounded label values.
+type requestMetrics struct {
+	methods map[string]bool
+}
+
+// newRequestMetrics collect the method names the rpc server derives from the exported methods
+// of the services of apis
+func newRequestMetrics(apis []app.API) *requestMetrics {
+	m := &requestMetrics{methods: map[string]bool{"rpc_modules": true}}
+	for _, api := range apis {
+		m.methods[api.Namespace+methodSeparator+"subscribe"] = true
+		m.methods[api.Namespace+methodSeparator+"unsubscribe"] = true
+		serviceType := reflect.TypeOf(api.Service)
+		if serviceType == nil {
+			continue
+		}
+		for i := 0; i < serviceType.NumMethod(); i++ {
+			m.methods[api.Namespace+methodSeparator+formatMethodName(serviceType.Method(i).Name)] = true
+		}
+	}
+	return m
+}
+
+// formatMethodName lower the first letter of a go method name like the rpc server does
+func formatMethodName(name string) string {
+	runes := []rune(name)
+	if len(runes) > 0 {
+		runes[0] = unicode.ToLower(runes[0])
+	}
+	return string(runes)
+}
+
+// label return the method of a json rpc request body, "batch" for batch requests
+func (m *requestMetrics) label(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		return batchMethod
+	}
+	msg := rpcMessage{}
+	if err := json.Unmarshal(body, &msg); err != nil || !m.methods[msg.Method] {
+		return unknownMethod
+	}
+	return msg.Method
+}
+
+// instrumentHandler wraps the json rpc http handler, it records the latency of every request
+// labeled by the rpc method called (or "batch" for batch requests).
+func (m *requestMetrics) instrumentHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := unknownMethod
+		if r.Method == http.MethodPost {
+			// requests too large to inspect are left whole to the next handler
+			body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxInspectSize+1))
+			if err == nil && len(body) <= maxInspectSize {
+				method = m.label(body)
+			}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		}
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		rpcRequestTimer.WithLabelValues(method).ObserveSince(start)
+		rpcRequestCounter.WithLabelValues(method).Inc()
+	})
+}
+
+// timedRequest is a websocket request waiting for its response
+type timedRequest struct {
+	method string
+	start  time.Time
+}
+
+// wsRequestTimer records the requests of a websocket connection. Responses are written
+// asynchronously, so a request is timed until the response carrying its id is written.
+type wsRequestTimer struct {
+	metrics *requestMetrics
+	lock    sync.Mutex
+	pending map[string]timedRequest
+}
+
+func (m *requestMetrics) newWSRequestTimer() *wsRequestTimer {
+	return &wsRequestTimer{metrics: m, pending: map[string]timedRequest{}}
+}
+
+// received count a request message and start its timer, batches are timed by their first id
+func (timer *wsRequestTimer) received(body []byte, msgs []rpcMessage) {
+	method := timer.metrics.label(body)
+	rpcRequestCounter.WithLabelValues(method).Inc()
+	for _, msg := range msgs {
+		if len(msg.ID) == 0 || string(msg.ID) == "null" {
+			continue
+		}
+		timer.lock.Lock()
+		if len(timer.pending) < maxTimedRequests {
+			timer.pending[string(msg.ID)] = timedRequest{method: method, start: time.Now()}
+		}
+		timer.lock.Unlock()
+		return
+	}
+}
+
+// sent stop the timer of the request answered by a response message
+func (timer *wsRequestTimer) sent(body []byte) {
+	msgs := requestMessages(body)
+	if len(msgs) == 0 || len(msgs[0].ID) == 0 {
+		return
+	}
+	timer.lock.Lock()
+	request, ok := timer.pending[string(msgs[0].ID)]
+	delete(timer.pending, string(msgs[0].ID))
+	timer.lock.Unlock()
+	if ok {
+		rpcRequestTimer.WithLabelValues(request.method).ObserveSince(request.start)
+	}
+}
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/app"
+)
+
+type testMetricsApi struct{}
+
+func (api *testMetricsApi) GetBalance() error { return nil }
+
+func TestRequestMetricsLabel(t *testing.T) {
+	m := newRequestMetrics([]app.API{{Namespace: "chain", Service: &testMetricsApi{}}})
+	cases := map[string]string{
+		`{"id":1,"method":"chain_getBalance"}`:    "chain_getBalance",
+		`{"id":1,"method":"chain_subscribe"}`:     "chain_subscribe",
+		`{"id":1,"method":"chain_notRegistered"}`: unknownMethod,
+		`{"id":1,"method":"random1234"}`:          unknownMethod,
+		`[{"id":1,"method":"chain_getBalance"}]`:  batchMethod,
+		`not json`:                                unknownMethod,
+	}
+	for body, want := range cases {
+		if got := m.label([]byte(body)); got != want {
+			t.Errorf("label of %s: got %s, want %s", body, got, want)
+		}
+	}
+}
+
+func TestInstrumentHandlerLargeBody(t *testing.T) {
+	body := bytes.Repeat([]byte{' '}, maxInspectSize+10)
+	var served int
+	handler := newRequestMetrics(nil).instrumentHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := ioutil.ReadAll(r.Body)
+		served = len(content)
+	}))
+	// a chunked request has no content length
+	req := httptest.NewRequest(http.MethodPost, "/", ioutil.NopCloser(bytes.NewReader(body)))
+	req.ContentLength = -1
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if served != len(body) {
+		t.Errorf("next handler read %d bytes, want %d", served, len(body))
+	}
+}
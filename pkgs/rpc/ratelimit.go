@@ -0,0 +1,253 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// json rpc error code of requests rejected by request limits
+	ErrCodeLimitExceeded = -32005
+
+	// buckets are swept for idle clients once there are more of them than this
+	maxIdleBuckets = 4096
+)
+
+// tokenBucket holds the tokens left to a client, refilled at the rate of its limiter
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is a token bucket rate limiter keyed by client ip or credential name
+type rateLimiter struct {
+	rate    float64
+	burst   int
+	lock    sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   burst,
+		buckets: map[string]*tokenBucket{},
+		now:     time.Now,
+	}
+}
+
+// allow take n tokens from the bucket of key, it returns the time to wait before retry if
+// there are not enough tokens. Requests larger than burst take the whole bucket.
+func (limiter *rateLimiter) allow(key string, n int) (bool, time.Duration) {
+	if limiter.rate <= 0 {
+		return true, 0
+	}
+	need := math.Min(float64(n), float64(limiter.burst))
+
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+	now := limiter.now()
+	if len(limiter.buckets) > maxIdleBuckets {
+		limiter.sweep(now)
+	}
+	bucket, ok := limiter.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limiter.burst), last: now}
+		limiter.buckets[key] = bucket
+	}
+	limiter.refill(bucket, now)
+	if bucket.tokens < need {
+		return false, time.Duration((need - bucket.tokens) / limiter.rate * float64(time.Second))
+	}
+	bucket.tokens -= need
+	return true, 0
+}
+
+func (limiter *rateLimiter) refill(bucket *tokenBucket, now time.Time) {
+	bucket.tokens = math.Min(float64(limiter.burst), bucket.tokens+now.Sub(bucket.last).Seconds()*limiter.rate)
+	bucket.last = now
+}
+
+// sweep drop the buckets refilled to full, they are the same as new ones
+func (limiter *rateLimiter) sweep(now time.Time) {
+	for key, bucket := range limiter.buckets {
+		limiter.refill(bucket, now)
+		if bucket.tokens >= float64(limiter.burst) {
+			delete(limiter.buckets, key)
+		}
+	}
+}
+
+// connCounter counts the open websocket connections of each client ip
+type connCounter struct {
+	max   int
+	lock  sync.Mutex
+	conns map[string]int
+}
+
+func (counter *connCounter) acquire(key string) bool {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+	if counter.max > 0 && counter.conns[key] >= counter.max {
+		return false
+	}
+	counter.conns[key]++
+	return true
+}
+
+func (counter *connCounter) release(key string) {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+	if counter.conns[key] <= 1 {
+		delete(counter.conns, key)
+		return
+	}
+	counter.conns[key]--
+}
+
+// requestLimiter applies LimitConfig to the http and websocket endpoints. The credential of a
+// request is found with the authenticator of the endpoint, nil if auth is disabled.
+type requestLimiter struct {
+	config     LimitConfig
+	auth       *authenticator
+	ips        *rateLimiter
+	credential *rateLimiter
+	wsConns    *connCounter
+}
+
+func newRequestLimiter(config LimitConfig, auth *authenticator) *requestLimiter {
+	return &requestLimiter{
+		config:     config,
+		auth:       auth,
+		ips:        newRateLimiter(config.RequestsPerSecond, config.Burst),
+		credential: newRateLimiter(config.CredentialRequestsPerSecond, config.CredentialBurst),
+		wsConns:    &connCounter{max: config.MaxWSConnections, conns: map[string]int{}},
+	}
+}
+
+// allow take n requests from the limit of the credential carried by r, or of its client ip
+// if there is none. Invalid credentials are left to the authenticator and limited by ip.
+func (limiter *requestLimiter) allow(r *http.Request, n int) (bool, time.Duration) {
+	bucket, key := limiter.bucket(r)
+	return bucket.allow(key, n)
+}
+
+// bucket return the rate limiter and key limiting the requests of r
+func (limiter *requestLimiter) bucket(r *http.Request) (*rateLimiter, string) {
+	if limiter.auth != nil {
+		if perm, err := limiter.auth.authenticate(r); err == nil && perm != nil {
+			return limiter.credential, perm.name
+		}
+	}
+	return limiter.ips, clientIP(r)
+}
+
+// checkBatch return the error of a batch with too many calls
+func (limiter *requestLimiter) checkBatch(msgs []rpcMessage) error {
+	if limiter.config.MaxBatchSize > 0 && len(msgs) > limiter.config.MaxBatchSize {
+		return fmt.Errorf("batch of %d requests exceeds limit %d", len(msgs), limiter.config.MaxBatchSize)
+	}
+	return nil
+}
+
+// httpHandler rejects batch requests with too many calls and requests over the rate limit
+func (limiter *requestLimiter) httpHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, ok := inspectBody(w, r)
+		if !ok {
+			return
+		}
+		msgs := requestMessages(body)
+		if err := limiter.checkBatch(msgs); err != nil {
+			writeRPCError(w, http.StatusRequestEntityTooLarge, nil, ErrCodeLimitExceeded, err.Error())
+			return
+		}
+		if ok, wait := limiter.allow(r, len(msgs)); !ok {
+			var id json.RawMessage
+			if len(msgs) == 1 {
+				id = msgs[0].ID
+			}
+			w.Header().Set("Retry-After", retryAfter(wait))
+			writeRPCError(w, http.StatusTooManyRequests, id, ErrCodeLimitExceeded, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wsHandler limits the rate of websocket handshakes and the open connections of each client ip,
+// next serves a connection until it is closed
+func (limiter *requestLimiter) wsHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := limiter.allow(r, 1); !ok {
+			w.Header().Set("Retry-After", retryAfter(wait))
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		ip := clientIP(r)
+		if !limiter.wsConns.acquire(ip) {
+			http.Error(w, fmt.Sprintf("too many websocket connections, limit %d", limiter.config.MaxWSConnections), http.StatusTooManyRequests)
+			return
+		}
+		defer limiter.wsConns.release(ip)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wsCheck return the check of the messages of a websocket connection opened by r, each message
+// is limited like a http request by the credential of the connection or its client ip
+func (limiter *requestLimiter) wsCheck(r *http.Request) messageCheck {
+	bucket, key := limiter.bucket(r)
+	return func(msgs []rpcMessage) *rpcError {
+		if err := limiter.checkBatch(msgs); err != nil {
+			return &rpcError{Code: ErrCodeLimitExceeded, Message: err.Error()}
+		}
+		if ok, wait := bucket.allow(key, len(msgs)); !ok {
+			return &rpcError{Code: ErrCodeLimitExceeded, Message: fmt.Sprintf("rate limit exceeded, retry after %ss", retryAfter(wait))}
+		}
+		return nil
+	}
+}
+
+// retryAfter format the wait as whole seconds for Retry-After header
+func retryAfter(wait time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(wait.Seconds())))
+}
+
+// clientIP return the ip of the peer of r, forwarded headers are not trusted
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientIPKey is the context key of the client ip of http requests
+type clientIPKey struct{}
+
+// withClientIP put the client ip of http requests into their context, the rpc server passes it on
+// to the methods taking a context
+func withClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPKey{}, clientIP(r))))
+	})
+}
+
+// ClientIPFromContext return the client ip of a call served on the http endpoint
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPKey{}).(string)
+	return ip, ok
+}
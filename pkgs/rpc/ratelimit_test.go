@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := newRateLimiter(2, 4)
+	limiter.now = func() time.Time { return now }
+
+	if ok, _ := limiter.allow("a", 3); !ok {
+		t.Fatal("expect burst allowed")
+	}
+	if ok, wait := limiter.allow("a", 2); ok || wait != 500*time.Millisecond {
+		t.Fatalf("expect rejected with 500ms wait, got %v %v", ok, wait)
+	}
+	if ok, _ := limiter.allow("b", 4); !ok {
+		t.Error("expect buckets of other keys not affected")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := limiter.allow("a", 2); !ok {
+		t.Error("expect allowed after refill")
+	}
+	now = now.Add(time.Hour)
+	if ok, _ := limiter.allow("a", 10); !ok {
+		t.Error("expect request larger than burst allowed with full bucket")
+	}
+}
+
+func TestLimitHandler(t *testing.T) {
+	limiter := newRequestLimiter(LimitConfig{RequestsPerSecond: 1, Burst: 2, MaxBatchSize: 2}, nil)
+	handler := limiter.httpHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	call := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.RemoteAddr = "10.0.0.1:5000"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := call(`[{"id":1,"method":"a"},{"id":2,"method":"b"},{"id":3,"method":"c"}]`); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expect batch over limit rejected, got %d", rec.Code)
+	}
+	if rec := call(`[{"id":1,"method":"a"},{"id":2,"method":"b"}]`); rec.Code != http.StatusOK {
+		t.Errorf("expect batch allowed, got %d", rec.Code)
+	}
+	rec := call(`{"id":3,"method":"a"}`)
+	if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), "-32005") || rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("expect rate limited, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWithClientIP(t *testing.T) {
+	var ip string
+	handler := withClientIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _ = ClientIPFromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req.RemoteAddr = "10.0.0.1:5000"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if ip != "10.0.0.1" {
+		t.Errorf("expect client ip 10.0.0.1 in context, got %q", ip)
+	}
+}
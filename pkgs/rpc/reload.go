@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/drep-project/DREP-Chain/app"
+)
+
+// Reload apply a changed rpc config, a config that does not validate is rejected. The HTTP and
+// websocket endpoints are restarted when their address, modules, cors, virtual hosts, origins,
+// auth or limits changed, the old endpoint is brought back if the new one fails to start. IPC and
+// REST settings take effect after restart.
+func (rpcService *RpcService) Reload(executeContext *app.ExecuteContext, phase json.RawMessage) error {
+	config := rpcService.DefaultConfig()
+	if phase != nil {
+		if err := json.Unmarshal(phase, config); err != nil {
+			return err
+		}
+	}
+	if errs := config.validate(); len(errs) > 0 {
+		return errs
+	}
+
+	rpcService.lock.Lock()
+	defer rpcService.lock.Unlock()
+	oldConfig := rpcService.Config
+	rpcService.Config = config
+	rpcService.setRpcLog(executeContext.Cli, executeContext.CommonConfig.HomeDir)
+	config.IPCEnabled, config.IPCPath = oldConfig.IPCEnabled, oldConfig.IPCPath
+
+	if httpChanged(oldConfig, config) {
+		rpcService.StopHTTP()
+		if err := rpcService.StartHTTP(config.HTTPEndpoint(), rpcService.RpcAPIs, config.HTTPModules, config.HTTPCors, config.HTTPVirtualHosts, config.HTTPTimeouts); err != nil {
+			log.WithField("err", err).Error("restart HTTP endpoint fail, restore the old one")
+			rpcService.Config = oldConfig
+			rpcService.StartHTTP(oldConfig.HTTPEndpoint(), rpcService.RpcAPIs, oldConfig.HTTPModules, oldConfig.HTTPCors, oldConfig.HTTPVirtualHosts, oldConfig.HTTPTimeouts)
+			return err
+		}
+	}
+
+	if wsChanged(oldConfig, config) {
+		rpcService.StopWS()
+		if err := rpcService.StartWS(config.WSEndpoint(), rpcService.RpcAPIs, config.WSModules, config.WSOrigins, config.WSExposeAll); err != nil {
+			log.WithField("err", err).Error("restart WebSocket endpoint fail, restore the old one")
+			rpcService.Config = oldConfig
+			rpcService.StartWS(oldConfig.WSEndpoint(), rpcService.RpcAPIs, oldConfig.WSModules, oldConfig.WSOrigins, oldConfig.WSExposeAll)
+			return err
+		}
+	}
+	return nil
+}
+
+func httpChanged(oldConfig, newConfig *RpcConfig) bool {
+	return oldConfig.HTTPEnabled != newConfig.HTTPEnabled ||
+		oldConfig.HTTPEndpoint() != newConfig.HTTPEndpoint() ||
+		!reflect.DeepEqual(oldConfig.HTTPModules, newConfig.HTTPModules) ||
+		!reflect.DeepEqual(oldConfig.HTTPCors, newConfig.HTTPCors) ||
+		!reflect.DeepEqual(oldConfig.HTTPVirtualHosts, newConfig.HTTPVirtualHosts) ||
+		!reflect.DeepEqual(oldConfig.Auth, newConfig.Auth) ||
+		oldConfig.Limits != newConfig.Limits
+}
+
+func wsChanged(oldConfig, newConfig *RpcConfig) bool {
+	return oldConfig.WSEnabled != newConfig.WSEnabled ||
+		oldConfig.WSEndpoint() != newConfig.WSEndpoint() ||
+		oldConfig.WSExposeAll != newConfig.WSExposeAll ||
+		!reflect.DeepEqual(oldConfig.WSModules, newConfig.WSModules) ||
+		!reflect.DeepEqual(oldConfig.WSOrigins, newConfig.WSOrigins) ||
+		!reflect.DeepEqual(oldConfig.Auth, newConfig.Auth) ||
+		oldConfig.Limits != newConfig.Limits
+}
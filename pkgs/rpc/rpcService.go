@@ -42,7 +42,8 @@ type RpcService struct {
 	RestController *rpc.RestController // Websocket RPC listener socket to server API requests
 
 	lock   sync.RWMutex
-	Config *rpc.RpcConfig
+	Config *RpcConfig
+	apis   []app.API
 }
 
 func (rpcService *RpcService) Name() string {
@@ -50,7 +51,7 @@ func (rpcService *RpcService) Name() string {
 }
 
 func (rpcService *RpcService) Api() []app.API {
-	return nil
+	return rpcService.apis
 }
 
 func (rpcService *RpcService) CommandFlags() ([]cli.Command, []cli.Flag) {
@@ -66,12 +67,36 @@ func (rpcService *RpcService) P2pMessages() map[int]interface{} {
 	return map[int]interface{}{}
 }
 
-func (rpcService *RpcService) Init(executeContext *app.ExecuteContext) error {
+// ApplyFlags set the endpoints of ipc, http, websocket and rest from command line flags
+func (rpcService *RpcService) ApplyFlags(executeContext *app.ExecuteContext) {
 	rpcService.setRpcLog(executeContext.Cli, executeContext.CommonConfig.HomeDir)
+}
+
+// ListenPorts report the ports of enabled http and websocket endpoints
+func (rpcService *RpcService) ListenPorts() map[string]int {
+	ports := map[string]int{}
+	if rpcService.Config.HTTPEnabled {
+		ports["/"+MODULENAME+"/HTTPPort"] = rpcService.Config.HTTPPort
+	}
+	if rpcService.Config.WSEnabled {
+		ports["/"+MODULENAME+"/WSPort"] = rpcService.Config.WSPort
+	}
+	return ports
+}
+
+func (rpcService *RpcService) Init(executeContext *app.ExecuteContext) error {
 	rpcService.IpcEndpoint = rpcService.Config.IPCEndpoint()
 	rpcService.HttpEndpoint = rpcService.Config.HTTPEndpoint()
 	rpcService.WsEndpoint = rpcService.Config.WSEndpoint()
 	rpcService.RestEndpoint = rpcService.Config.RestEndpoint()
+	rpcService.apis = []app.API{
+		app.API{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewAdminApi(executeContext),
+			Public:    false,
+		},
+	}
 	return nil
 }
 
@@ -209,11 +234,11 @@ func (rpcService *RpcService) StartHTTP(endpoint string, apis []app.API, modules
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, rpc.HTTPTimeouts{})
+	listener, handler, err := StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, rpc.HTTPTimeouts{}, rpcService.Config.Auth, rpcService.Config.Limits)
 	if err != nil {
 		return err
 	}
-	log.WithField("url", fmt.Sprintf("http://%s", endpoint)).WithField("cors", strings.Join(cors, ",")).WithField("vhosts", strings.Join(vhosts, ",")).Info("HTTP endpoint opened")
+	log.WithField("url", fmt.Sprintf("http://%s", endpoint)).WithField("cors", strings.Join(cors, ",")).WithField("vhosts", strings.Join(vhosts, ",")).WithField("auth", rpcService.Config.Auth.Enable).Info("HTTP endpoint opened")
 	// All listeners booted successfully
 	rpcService.HttpEndpoint = endpoint
 	rpcService.HttpListener = listener
@@ -244,11 +269,11 @@ func (rpcService *RpcService) StartWS(endpoint string, apis []app.API, modules [
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll)
+	listener, handler, err := StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll, rpcService.Config.Auth, rpcService.Config.Limits)
 	if err != nil {
 		return err
 	}
-	log.WithField("url", fmt.Sprintf("ws://%s", listener.Addr())).Info("WebSocket endpoint opened")
+	log.WithField("url", fmt.Sprintf("ws://%s", listener.Addr())).WithField("auth", rpcService.Config.Auth.Enable).Info("WebSocket endpoint opened")
 	// All listeners booted successfully
 	rpcService.WsEndpoint = endpoint
 	rpcService.WsListener = listener
@@ -461,8 +486,17 @@ func DefaultIPCEndpoint(clientIdentifier string) string {
 	return clientIdentifier + ".ipc"
 }
 
-func (rpcService *RpcService) DefaultConfig() *rpc.RpcConfig {
-	return &rpc.RpcConfig{
-		HTTPTimeouts: &rpc.DefaultHTTPTimeouts,
+func (rpcService *RpcService) DefaultConfig() *RpcConfig {
+	return &RpcConfig{
+		RpcConfig: rpc.RpcConfig{
+			HTTPTimeouts: &rpc.DefaultHTTPTimeouts,
+		},
+		Auth: AuthConfig{
+			AllowAnonymous: true,
+		},
+		Limits: LimitConfig{
+			MaxBatchSize:     100,
+			MaxWSConnections: 32,
+		},
 	}
 }
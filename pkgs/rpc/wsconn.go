@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/drep-project/rpc"
+	"golang.org/x/net/websocket"
+)
+
+// messageCheck is run on every json rpc message received on a websocket connection before it is
+// served, a non nil error rejects the whole message
+type messageCheck func(msgs []rpcMessage) *rpcError
+
+// rpcError is the json rpc error replied to a rejected message
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// newWSHandler serves json rpc over websocket with server. Unlike the handler of the rpc library
+// every message is read whole by the endpoint, it is counted in the request metrics and passed to
+// the checks of its connection, made from the handshake request by connChecks in order.
+func newWSHandler(origins []string, server *rpc.Server, metrics *requestMetrics, connChecks ...func(r *http.Request) messageCheck) http.Handler {
+	return websocket.Server{
+		Handshake: wsHandshakeValidator(origins),
+		Handler: func(conn *websocket.Conn) {
+			conn.MaxPayloadBytes = maxInspectSize
+			wsConn := &wsCodecConn{conn: conn, timer: metrics.newWSRequestTimer()}
+			for _, connCheck := range connChecks {
+				wsConn.checks = append(wsConn.checks, connCheck(conn.Request()))
+			}
+			server.ServeCodec(rpc.NewJSONCodec(wsConn), rpc.OptionMethodInvocation|rpc.OptionSubscriptions)
+		},
+	}
+}
+
+// wsHandshakeValidator accepts the origins configured, "*" allows any. Without origins only
+// localhost is allowed, like the websocket handler of the rpc library.
+func wsHandshakeValidator(allowedOrigins []string) func(*websocket.Config, *http.Request) error {
+	origins := map[string]bool{}
+	allowAll := false
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		if origin != "" {
+			origins[strings.ToLower(origin)] = true
+		}
+	}
+	if len(origins) == 0 {
+		origins["http://localhost"] = true
+		if hostname, err := os.Hostname(); err == nil {
+			origins["http://"+strings.ToLower(hostname)] = true
+		}
+	}
+	return func(config *websocket.Config, r *http.Request) error {
+		origin := strings.ToLower(r.Header.Get("Origin"))
+		if allowAll || origins[origin] {
+			return nil
+		}
+		return fmt.Errorf("origin %s not allowed", origin)
+	}
+}
+
+// wsCodecConn is the connection read by the json codec of the rpc server. Messages rejected by
+// a check are answered here and never reach the server.
+type wsCodecConn struct {
+	conn      *websocket.Conn
+	checks    []messageCheck
+	timer     *wsRequestTimer
+	reader    bytes.Reader
+	writeLock sync.Mutex
+}
+
+func (c *wsCodecConn) Read(p []byte) (int, error) {
+	for c.reader.Len() == 0 {
+		var body []byte
+		if err := websocket.Message.Receive(c.conn, &body); err != nil {
+			return 0, err
+		}
+		msgs := requestMessages(body)
+		c.timer.received(body, msgs)
+		if reject := c.checkMessages(msgs); reject != nil {
+			if err := c.reject(msgs, reject); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		c.reader.Reset(body)
+	}
+	return c.reader.Read(p)
+}
+
+// checkMessages return the error of the first check rejecting msgs
+func (c *wsCodecConn) checkMessages(msgs []rpcMessage) *rpcError {
+	for _, check := range c.checks {
+		if reject := check(msgs); reject != nil {
+			return reject
+		}
+	}
+	return nil
+}
+
+// reject reply the error of a rejected message, with the id of a single request
+func (c *wsCodecConn) reject(msgs []rpcMessage, reject *rpcError) error {
+	id := json.RawMessage("null")
+	if len(msgs) == 1 && len(msgs[0].ID) > 0 {
+		id = msgs[0].ID
+	}
+	resp, err := json.Marshal(struct {
+		Version string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Error   *rpcError       `json:"error"`
+	}{"2.0", id, reject})
+	if err != nil {
+		return err
+	}
+	_, err = c.Write(resp)
+	return err
+}
+
+func (c *wsCodecConn) Write(p []byte) (int, error) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	c.timer.sent(p)
+	return c.conn.Write(p)
+}
+
+func (c *wsCodecConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,113 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/app"
+	"github.com/drep-project/rpc"
+	"golang.org/x/net/websocket"
+)
+
+type testWSApi struct{}
+
+func (api *testWSApi) Hello() string { return "hello" }
+
+// dialTestWS start a websocket endpoint serving the chain and account namespaces of testWSApi
+func dialTestWS(t *testing.T, query string, connChecks ...func(r *http.Request) messageCheck) (*websocket.Conn, func()) {
+	apis := []app.API{
+		{Namespace: "chain", Service: &testWSApi{}, Public: true},
+		{Namespace: "account", Service: &testWSApi{}},
+	}
+	server := rpc.NewServer()
+	for _, api := range apis {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			t.Fatal(err)
+		}
+	}
+	httpServer := httptest.NewServer(newWSHandler([]string{"*"}, server, newRequestMetrics(apis), connChecks...))
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/" + query
+	conn, err := websocket.Dial(url, "", "http://localhost")
+	if err != nil {
+		httpServer.Close()
+		t.Fatal(err)
+	}
+	return conn, func() {
+		conn.Close()
+		server.Stop()
+		httpServer.Close()
+	}
+}
+
+// wsCall send a request and return the error code of the response, 0 if it succeeded
+func wsCall(t *testing.T, conn *websocket.Conn, request string) int {
+	if err := websocket.Message.Send(conn, request); err != nil {
+		t.Fatal(err)
+	}
+	resp := struct {
+		Result interface{} `json:"result"`
+		Error  *rpcError   `json:"error"`
+	}{}
+	if err := websocket.JSON.Receive(conn, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != nil {
+		return resp.Error.Code
+	}
+	return 0
+}
+
+func TestWSHandlerChecksMethodGrants(t *testing.T) {
+	conn, stop := dialTestWS(t, "?token="+signJWT(`{"sub":"wallet"}`), testAuthenticator().wsCheck)
+	defer stop()
+	cases := []struct {
+		request string
+		code    int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"chain_hello"}`, 0},
+		{`{"jsonrpc":"2.0","id":2,"method":"account_hello"}`, ErrCodeForbidden},
+		// the connection still serves the methods granted after a rejected message
+		{`{"jsonrpc":"2.0","id":3,"method":"chain_hello"}`, 0},
+	}
+	for i, c := range cases {
+		if code := wsCall(t, conn, c.request); code != c.code {
+			t.Errorf("case %d: expect error code %d, got %d", i, c.code, code)
+		}
+	}
+}
+
+func TestWSHandlerAnswersRejectedRequestID(t *testing.T) {
+	conn, stop := dialTestWS(t, "", testAuthenticator().wsCheck)
+	defer stop()
+	websocket.Message.Send(conn, `{"jsonrpc":"2.0","id":"abc","method":"account_hello"}`)
+	resp := struct {
+		ID json.RawMessage `json:"id"`
+	}{}
+	if err := websocket.JSON.Receive(conn, &resp); err != nil || string(resp.ID) != `"abc"` {
+		t.Errorf("expect the id of the rejected request, got %s %v", resp.ID, err)
+	}
+}
+
+func TestWSHandlerLimitsMessages(t *testing.T) {
+	limiter := newRequestLimiter(LimitConfig{RequestsPerSecond: 0.001, Burst: 3, MaxBatchSize: 2}, nil)
+	conn, stop := dialTestWS(t, "", limiter.wsCheck)
+	defer stop()
+	cases := []struct {
+		request string
+		code    int
+	}{
+		{`[{"jsonrpc":"2.0","id":1,"method":"chain_hello"},{"jsonrpc":"2.0","id":2,"method":"chain_hello"},{"jsonrpc":"2.0","id":3,"method":"chain_hello"}]`, ErrCodeLimitExceeded},
+		{`{"jsonrpc":"2.0","id":4,"method":"chain_hello"}`, 0},
+		{`{"jsonrpc":"2.0","id":5,"method":"chain_hello"}`, 0},
+		{`{"jsonrpc":"2.0","id":6,"method":"chain_hello"}`, 0},
+		{`{"jsonrpc":"2.0","id":7,"method":"chain_hello"}`, ErrCodeLimitExceeded},
+	}
+	for i, c := range cases {
+		if code := wsCall(t, conn, c.request); code != c.code {
+			t.Errorf("case %d: expect error code %d, got %d", i, c.code, code)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package rpcclient is a typed go client of the json rpc of a node. The namespace clients are
+// generated from the service apis by cmds/genapicode, run go generate after an api changed.
+package rpcclient
+
+import (
+	"context"
+
+	"github.com/drep-project/rpc"
+)
+
+//go:generate go run ../../cmds/genapicode -go .
+
+// Dial connect to a node, rawurl is a http or ws url or the path of the ipc file. Subscriptions
+// need a ws or ipc connection.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+// DialContext connect to a node, ctx only bounds the connection setup
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// Close the connection, active subscriptions are ended
+func (client *Client) Close() {
+	client.c.Close()
+}
+
+// Raw return the underlying rpc client, for methods not covered by the generated clients
+func (client *Client) Raw() *rpc.Client {
+	return client.c
+}
@@ -0,0 +1,87 @@
+package token
+
+import (
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+/*
+name: 代币接口
+usage: 查询按erc20 Transfer事件索引的代币余额和转账记录（需要开启代币和链索引模块，只统计已确认section中的区块，索引落后链最多一个section加确认数的区块，结果中的IndexedHeight为第一个未统计的区块高度）
+prefix: token
+*/
+type TokenApi struct {
+	tokenService *TokenService
+}
+
+/*
+ name: balanceOf
+ usage: 查询地址持有的代币余额
+ params:
+	1. 代币合约地址
+	2. 持有者地址
+ return: 代币余额，只统计IndexedHeight之前的区块
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"token_balanceOf","params":["0x1ad3b7f2b5e08ef43d7c9a8f3a64c74ca3d7c3b1","0x7923a30bbfbcb998a6534d56b313e68c8e0c594a"], "id": 3}' -H "Content-Type:application/json"
+ response:
+   {"jsonrpc":"2.0","id":3,"result":{"Balance":"0xde0b6b3a7640000","IndexedHeight":8192}}
+*/
+func (api *TokenApi) BalanceOf(contract, holder crypto.CommonAddress) (*TokenBalance, error) {
+	return api.tokenService.BalanceOf(contract, holder)
+}
+
+/*
+ name: transfers
+ usage: 查询地址转出和转入的代币转账记录，按区块顺序排列，支持分页
+ params:
+	1. 地址
+	2. 分页号（从1开始）
+	3. 页大小，须大于0，超过maxPageSize时按maxPageSize返回
+ return: IndexedHeight之前区块中的转账列表，Index为日志在区块所有日志中的序号
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"token_transfers","params":["0x7923a30bbfbcb998a6534d56b313e68c8e0c594a",1,10], "id": 3}' -H "Content-Type:application/json"
+ response:
+   {
+	  "jsonrpc": "2.0",
+	  "id": 3,
+	  "result": {
+		"Transfers": [
+		  {
+			"Token": "0x1ad3b7f2b5e08ef43d7c9a8f3a64c74ca3d7c3b1",
+			"From": "0x7923a30bbfbcb998a6534d56b313e68c8e0c594a",
+			"To": "0x3ebcbe7cb440dd8c52940a2963472380afbb56c5",
+			"Value": "0xde0b6b3a7640000",
+			"TxHash": "0x3d3e7da272a5128bec6fd7ad10d8557b08e0fb9de4af6753641e29740eb7054e",
+			"Height": 4410,
+			"Index": 0
+		  }
+		],
+		"IndexedHeight": 8192
+	  }
+	}
+*/
+func (api *TokenApi) Transfers(addr crypto.CommonAddress, pageIndex, pageSize int) (*TokenTransfers, error) {
+	return api.tokenService.Transfers(addr, pageIndex, pageSize)
+}
+
+/*
+ name: list
+ usage: 查询已索引转账的代币合约
+ params:
+	无
+ return: 代币列表，Height为第一笔转账所在区块，Transfers为转账数目，Holders为余额大于0的地址数目
+ example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"token_list","params":[], "id": 3}' -H "Content-Type:application/json"
+ response:
+   {
+	  "jsonrpc": "2.0",
+	  "id": 3,
+	  "result": [
+		{
+		  "Address": "0x1ad3b7f2b5e08ef43d7c9a8f3a64c74ca3d7c3b1",
+		  "Height": 4102,
+		  "Transfers": 25,
+		  "Holders": 7
+		}
+	  ]
+	}
+*/
+func (api *TokenApi) List() []*TokenInfo {
+	return api.tokenService.List()
+}
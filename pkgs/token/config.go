@@ -0,0 +1,14 @@
+package token
+
+type TokenConfig struct {
+	Enable bool `json:"enable"`
+	// max number of transfers returned by one page of token_transfers, 0 for no limit
+	MaxPageSize int `json:"maxPageSize"`
+}
+
+var (
+	DefaultConfig = &TokenConfig{
+		Enable:      false,
+		MaxPageSize: 100,
+	}
+)
@@ -0,0 +1,112 @@
+package token
+
+import (
+	bin "encoding/binary"
+	"math/big"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/binary"
+)
+
+var (
+	tokenInfoPrefix    = []byte("token_info_")
+	tokenBalancePrefix = []byte("token_balance_")
+	tokenHistoryPrefix = []byte("token_history_")
+	tokenSectionPrefix = []byte("token_section_")
+)
+
+// tokenInfoKey = tokenInfoPrefix + token
+func tokenInfoKey(token crypto.CommonAddress) []byte {
+	return append(append([]byte{}, tokenInfoPrefix...), token[:]...)
+}
+
+// balanceKey = tokenBalancePrefix + token + holder
+func balanceKey(token, holder crypto.CommonAddress) []byte {
+	return append(append(append([]byte{}, tokenBalancePrefix...), token[:]...), holder[:]...)
+}
+
+// historyKey = tokenHistoryPrefix + holder + height (uint64 big endian) + index (uint32 big endian),
+// the transfers of a holder are iterated in chain order
+func historyKey(holder crypto.CommonAddress, height uint64, index uint32) []byte {
+	key := append(append(append([]byte{}, tokenHistoryPrefix...), holder[:]...), make([]byte, 12)...)
+	bin.BigEndian.PutUint64(key[len(key)-12:], height)
+	bin.BigEndian.PutUint32(key[len(key)-4:], index)
+	return key
+}
+
+// sectionKey = tokenSectionPrefix + section (uint64 big endian), the transfers indexed in a section
+// are journaled to roll back their balances when the section is reorganized
+func sectionKey(section uint64) []byte {
+	key := append(append([]byte{}, tokenSectionPrefix...), make([]byte, 8)...)
+	bin.BigEndian.PutUint64(key[len(key)-8:], section)
+	return key
+}
+
+func readTokenInfo(db *database.DatabaseService, token crypto.CommonAddress) (*TokenInfo, error) {
+	value, err := db.Get(tokenInfoKey(token))
+	if err != nil {
+		return nil, err
+	}
+	info := &TokenInfo{}
+	if err := binary.Unmarshal(value, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func readTokenInfos(db *database.DatabaseService) []*TokenInfo {
+	infos := []*TokenInfo{}
+	iter := db.NewIteratorWithPrefix(tokenInfoPrefix)
+	defer iter.Release()
+	for iter.Next() {
+		info := &TokenInfo{}
+		if err := binary.Unmarshal(iter.Value(), info); err != nil {
+			break
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// readBalance return the balance of holder, balances are kept as decimal strings as tokens
+// emitting transfers not covered by the balance of the sender give negative balances
+func readBalance(db *database.DatabaseService, token, holder crypto.CommonAddress) *big.Int {
+	balance := new(big.Int)
+	value, err := db.Get(balanceKey(token, holder))
+	if err != nil {
+		return balance
+	}
+	balance.SetString(string(value), 10)
+	return balance
+}
+
+// readTransfers return the transfers from or to holder, skipping the first skip ones
+func readTransfers(db *database.DatabaseService, holder crypto.CommonAddress, skip, count int) []*TokenTransfer {
+	transfers := []*TokenTransfer{}
+	iter := db.NewIteratorWithPrefix(append(append([]byte{}, tokenHistoryPrefix...), holder[:]...))
+	defer iter.Release()
+	for index := 0; iter.Next() && len(transfers) < count; index++ {
+		if index < skip {
+			continue
+		}
+		transfer := &TokenTransfer{}
+		if err := binary.Unmarshal(iter.Value(), transfer); err != nil {
+			break
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
+
+func readJournal(db *database.DatabaseService, section uint64) ([]*TokenTransfer, error) {
+	value, err := db.Get(sectionKey(section))
+	if err != nil {
+		return nil, err
+	}
+	var transfers []*TokenTransfer
+	if err := binary.Unmarshal(value, &transfers); err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
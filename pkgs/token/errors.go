@@ -0,0 +1,10 @@
+package token
+
+import (
+	"errors"
+)
+
+var (
+	ErrTokenNotFound = errors.New("token not indexed")
+	ErrInvalidPage   = errors.New("page index and page size must be positive")
+)
@@ -0,0 +1,12 @@
+package token
+
+import (
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	EnableTokenFlag = cli.BoolFlag{
+		Name:  "enableToken",
+		Usage: "enable token transfer index, requires the chain indexer",
+	}
+)
@@ -0,0 +1,233 @@
+package token
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/database/drepdb"
+	"github.com/drep-project/DREP-Chain/types"
+	"github.com/drep-project/binary"
+)
+
+const (
+	// TokenIndexerName is the name of the token transfer indexer in the chain indexer
+	TokenIndexerName = "token"
+)
+
+var (
+	// transferEventId is the first topic of the logs of the erc20 Transfer(address,address,uint256) event
+	transferEventId = crypto.Bytes2Hash(sha3.Keccak256([]byte("Transfer(address,address,uint256)")))
+)
+
+// tokenIndexer collects the erc20 transfers of the confirmed sections of the chain into per holder
+// balances and transfer history
+type tokenIndexer struct {
+	db *database.DatabaseService
+
+	section   uint64           // section being processed
+	transfers []*TokenTransfer // transfers of the section, in chain order
+}
+
+// Reset starts a new section, the transfers of a section committed before the progress of the
+// indexer was stored are rolled back first so that they are not counted twice
+func (t *tokenIndexer) Reset(ctx context.Context, section uint64, prevHead crypto.Hash) error {
+	if _, err := readJournal(t.db, section); err == nil {
+		if err := t.Rollback(section); err != nil {
+			return err
+		}
+	}
+	t.section, t.transfers = section, []*TokenTransfer{}
+	return nil
+}
+
+// Process collects the transfers of the successful transactions of the block
+func (t *tokenIndexer) Process(ctx context.Context, header *types.BlockHeader) error {
+	index := uint32(0)
+	for _, receipt := range t.db.GetReceipts(*header.Hash()) {
+		for _, l := range receipt.Logs {
+			if transfer := parseTransfer(l); transfer != nil && receipt.Status == types.ReceiptStatusSuccessful {
+				transfer.TxHash = receipt.TxHash
+				transfer.Height = header.Height
+				transfer.Index = index
+				t.transfers = append(t.transfers, transfer)
+			}
+			index++
+		}
+	}
+	return nil
+}
+
+// Commit applies the transfers of the section to the balances and writes the history of the
+// holders and the journal of the section
+func (t *tokenIndexer) Commit() error {
+	batch := t.db.NewBatch()
+	state := newTokenState(t.db)
+	for _, transfer := range t.transfers {
+		state.apply(transfer)
+		value, err := binary.Marshal(transfer)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(historyKey(transfer.From, transfer.Height, transfer.Index), value); err != nil {
+			return err
+		}
+		if err := batch.Put(historyKey(transfer.To, transfer.Height, transfer.Index), value); err != nil {
+			return err
+		}
+	}
+	journal, err := binary.Marshal(t.transfers)
+	if err != nil {
+		return err
+	}
+	if err := batch.Put(sectionKey(t.section), journal); err != nil {
+		return err
+	}
+	if err := state.write(batch); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Rollback reverts the transfers of section and every later committed section, newest first
+func (t *tokenIndexer) Rollback(section uint64) error {
+	batch := t.db.NewBatch()
+	state := newTokenState(t.db)
+	for ; ; section++ {
+		transfers, err := readJournal(t.db, section)
+		if err != nil {
+			break
+		}
+		for i := len(transfers) - 1; i >= 0; i-- {
+			transfer := transfers[i]
+			state.revert(transfer)
+			if err := batch.Delete(historyKey(transfer.From, transfer.Height, transfer.Index)); err != nil {
+				return err
+			}
+			if err := batch.Delete(historyKey(transfer.To, transfer.Height, transfer.Index)); err != nil {
+				return err
+			}
+		}
+		if err := batch.Delete(sectionKey(section)); err != nil {
+			return err
+		}
+	}
+	if err := state.write(batch); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// parseTransfer return the transfer of an erc20 Transfer event, nil for other logs. erc721 shares the
+// event signature but indexes the token id, its logs have one more topic and are skipped.
+func parseTransfer(l *types.Log) *TokenTransfer {
+	if len(l.Topics) != 3 || l.Topics[0] != transferEventId || len(l.Data) != 32 {
+		return nil
+	}
+	return &TokenTransfer{
+		Token: l.Address,
+		From:  crypto.BytesToAddress(l.Topics[1][12:]),
+		To:    crypto.BytesToAddress(l.Topics[2][12:]),
+		Value: common.Big(*new(big.Int).SetBytes(l.Data)),
+	}
+}
+
+// tokenState accumulates the changes of a batch to the balances and infos of tokens
+type tokenState struct {
+	db       *database.DatabaseService
+	balances map[string]*big.Int // keyed by balanceKey
+	infos    map[crypto.CommonAddress]*TokenInfo
+}
+
+func newTokenState(db *database.DatabaseService) *tokenState {
+	return &tokenState{
+		db:       db,
+		balances: make(map[string]*big.Int),
+		infos:    make(map[crypto.CommonAddress]*TokenInfo),
+	}
+}
+
+func (s *tokenState) info(token crypto.CommonAddress) *TokenInfo {
+	if info, ok := s.infos[token]; ok {
+		return info
+	}
+	info, err := readTokenInfo(s.db, token)
+	if err != nil {
+		info = &TokenInfo{Address: token}
+	}
+	s.infos[token] = info
+	return info
+}
+
+// add amount to the balance of holder, the zero address mints and burns tokens and keeps no balance
+func (s *tokenState) add(info *TokenInfo, holder crypto.CommonAddress, amount *big.Int) {
+	if holder == (crypto.CommonAddress{}) {
+		return
+	}
+	key := string(balanceKey(info.Address, holder))
+	balance, ok := s.balances[key]
+	if !ok {
+		balance = readBalance(s.db, info.Address, holder)
+		s.balances[key] = balance
+	}
+
+	held := balance.Sign() > 0
+	balance.Add(balance, amount)
+	if !held && balance.Sign() > 0 {
+		info.Holders++
+	} else if held && balance.Sign() <= 0 {
+		info.Holders--
+	}
+}
+
+func (s *tokenState) apply(transfer *TokenTransfer) {
+	info := s.info(transfer.Token)
+	if info.Transfers == 0 {
+		info.Height = transfer.Height
+	}
+	info.Transfers++
+	value := transfer.Value.ToInt()
+	s.add(info, transfer.From, new(big.Int).Neg(value))
+	s.add(info, transfer.To, value)
+}
+
+func (s *tokenState) revert(transfer *TokenTransfer) {
+	info := s.info(transfer.Token)
+	info.Transfers--
+	value := transfer.Value.ToInt()
+	s.add(info, transfer.To, new(big.Int).Neg(value))
+	s.add(info, transfer.From, value)
+}
+
+func (s *tokenState) write(batch drepdb.Batch) error {
+	for key, balance := range s.balances {
+		var err error
+		if balance.Sign() == 0 {
+			err = batch.Delete([]byte(key))
+		} else {
+			err = batch.Put([]byte(key), []byte(balance.String()))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for token, info := range s.infos {
+		if info.Transfers == 0 {
+			if err := batch.Delete(tokenInfoKey(token)); err != nil {
+				return err
+			}
+			continue
+		}
+		value, err := binary.Marshal(info)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(tokenInfoKey(token), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
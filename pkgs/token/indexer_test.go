@@ -0,0 +1,113 @@
+package token
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/types"
+)
+
+func transferLog(token, from, to crypto.CommonAddress, value int64) *types.Log {
+	return &types.Log{
+		Address: token,
+		Topics:  []crypto.Hash{transferEventId, crypto.Bytes2Hash(common.LeftPadBytes(from[:], 32)), crypto.Bytes2Hash(common.LeftPadBytes(to[:], 32))},
+		Data:    common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+	}
+}
+
+func TestTokenIndexer(t *testing.T) {
+	defer os.RemoveAll("./test")
+	diskDb, err := database.NewDatabase("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer diskDb.Close()
+	db := database.NewDatabaseService(diskDb)
+
+	token := crypto.CommonAddress{1}
+	alice, bob := crypto.CommonAddress{2}, crypto.CommonAddress{3}
+	header := &types.BlockHeader{Height: 5}
+	receipts := []*types.Receipt{
+		{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{transferLog(token, crypto.CommonAddress{}, alice, 100)}},
+		{Status: types.ReceiptStatusFailed, Logs: []*types.Log{transferLog(token, alice, bob, 50)}},
+		{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{transferLog(token, alice, bob, 30)}},
+	}
+	if err := db.PutReceipts(*header.Hash(), receipts); err != nil {
+		t.Fatal(err)
+	}
+
+	indexer := &tokenIndexer{db: db}
+	process := func() {
+		if err := indexer.Reset(context.Background(), 0, crypto.Hash{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := indexer.Process(context.Background(), header); err != nil {
+			t.Fatal(err)
+		}
+		if err := indexer.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a section committed twice is only counted once
+	process()
+	process()
+
+	if balance := readBalance(db, token, alice); balance.Cmp(big.NewInt(70)) != 0 {
+		t.Fatal("alice balance err", balance)
+	}
+	if balance := readBalance(db, token, bob); balance.Cmp(big.NewInt(30)) != 0 {
+		t.Fatal("bob balance err", balance)
+	}
+	transfers := readTransfers(db, alice, 0, 10)
+	if len(transfers) != 2 || transfers[1].To != bob || transfers[1].Index != 2 || transfers[1].Height != 5 {
+		t.Fatal("alice transfers err", transfers)
+	}
+	if transfers := readTransfers(db, alice, 1, 10); len(transfers) != 1 {
+		t.Fatal("alice transfers page err", transfers)
+	}
+	infos := readTokenInfos(db)
+	if len(infos) != 1 || infos[0].Transfers != 2 || infos[0].Holders != 2 || infos[0].Height != 5 {
+		t.Fatal("token list err", infos)
+	}
+
+	if err := indexer.Rollback(0); err != nil {
+		t.Fatal(err)
+	}
+	if balance := readBalance(db, token, alice); balance.Sign() != 0 {
+		t.Fatal("rollback balance err", balance)
+	}
+	if transfers := readTransfers(db, bob, 0, 10); len(transfers) != 0 {
+		t.Fatal("rollback transfers err", transfers)
+	}
+	if infos := readTokenInfos(db); len(infos) != 0 {
+		t.Fatal("rollback token list err", infos)
+	}
+}
+
+func TestTransfersPage(t *testing.T) {
+	tests := []struct {
+		pageIndex, pageSize, maxPageSize int
+		skip, count                      int
+		err                              bool
+	}{
+		{1, 10, 100, 0, 10, false},
+		{3, 10, 100, 20, 10, false},
+		{2, 500, 100, 100, 100, false},
+		{2, 500, 0, 500, 500, false},
+		{0, 10, 100, 0, 0, true},
+		{1, 0, 100, 0, 0, true},
+		{1, -1, 0, 0, 0, true},
+		{1 << 30, 1 << 30, 0, 0, 0, true},
+	}
+	for _, test := range tests {
+		skip, count, err := page(test.pageIndex, test.pageSize, test.maxPageSize)
+		if (err != nil) != test.err || skip != test.skip || count != test.count {
+			t.Errorf("page(%d, %d, %d) = %d, %d, %v", test.pageIndex, test.pageSize, test.maxPageSize, skip, count, err)
+		}
+	}
+}
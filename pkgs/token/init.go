@@ -0,0 +1,13 @@
+package token
+
+import (
+	dlog "github.com/drep-project/DREP-Chain/pkgs/log"
+)
+
+const (
+	MODULENAME = "token"
+)
+
+var (
+	log = dlog.EnsureLogger(MODULENAME)
+)
@@ -0,0 +1,131 @@
+package token
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/drep-project/DREP-Chain/app"
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/database"
+	"github.com/drep-project/DREP-Chain/pkgs/chain_indexer"
+)
+
+// TokenService tracks the erc20 tokens of the chain from their Transfer events, it keeps the balance
+// of every holder and the transfers from or to every address. The transfers are indexed by the chain
+// indexer, only the blocks of confirmed sections are counted.
+type TokenService struct {
+	DatabaseService     *database.DatabaseService                  `service:"database"`
+	ChainIndexerService chain_indexer.ChainIndexerServiceInterface `service:"chain_indexer"`
+	Config              *TokenConfig
+
+	apis []app.API
+}
+
+func (service *TokenService) Name() string {
+	return MODULENAME
+}
+
+func (service *TokenService) Api() []app.API {
+	return service.apis
+}
+
+func (service *TokenService) CommandFlags() ([]cli.Command, []cli.Flag) {
+	return nil, []cli.Flag{EnableTokenFlag}
+}
+
+func (service *TokenService) ApplyFlags(executeContext *app.ExecuteContext) {
+	if executeContext.Cli.GlobalIsSet(EnableTokenFlag.Name) {
+		service.Config.Enable = executeContext.Cli.GlobalBool(EnableTokenFlag.Name)
+	}
+}
+
+func (service *TokenService) Init(executeContext *app.ExecuteContext) error {
+	if !service.Config.Enable {
+		return nil
+	}
+
+	indexer := &tokenIndexer{db: service.DatabaseService}
+	if err := service.ChainIndexerService.RegisterIndexer(TokenIndexerName, indexer); err != nil {
+		return errors.Wrapf(err, "register token indexer")
+	}
+
+	service.apis = []app.API{
+		app.API{
+			Namespace: MODULENAME,
+			Version:   "1.0",
+			Service: &TokenApi{
+				tokenService: service,
+			},
+			Public: true,
+		},
+	}
+	return nil
+}
+
+func (service *TokenService) Start(executeContext *app.ExecuteContext) error {
+	return nil
+}
+
+func (service *TokenService) Stop(executeContext *app.ExecuteContext) error {
+	return nil
+}
+
+// BalanceOf return the balance of holder in token
+func (service *TokenService) BalanceOf(token, holder crypto.CommonAddress) (*TokenBalance, error) {
+	if _, err := readTokenInfo(service.DatabaseService, token); err != nil {
+		return nil, errors.Wrapf(ErrTokenNotFound, "%s", token.String())
+	}
+	return &TokenBalance{
+		Balance:       common.Big(*readBalance(service.DatabaseService, token, holder)),
+		IndexedHeight: service.indexedHeight(),
+	}, nil
+}
+
+// Transfers return a page of the transfers from or to addr in chain order, page index starts from 1
+// and page size is cut to MaxPageSize
+func (service *TokenService) Transfers(addr crypto.CommonAddress, pageIndex, pageSize int) (*TokenTransfers, error) {
+	skip, count, err := page(pageIndex, pageSize, service.Config.MaxPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenTransfers{
+		Transfers:     readTransfers(service.DatabaseService, addr, skip, count),
+		IndexedHeight: service.indexedHeight(),
+	}, nil
+}
+
+// page return the number of transfers skipped before a page and the size of the page
+func page(pageIndex, pageSize, maxPageSize int) (int, int, error) {
+	if pageIndex < 1 || pageSize < 1 {
+		return 0, 0, errors.Wrapf(ErrInvalidPage, "page %d size %d", pageIndex, pageSize)
+	}
+	if maxPageSize > 0 && pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	if pageIndex-1 > math.MaxInt32/pageSize {
+		return 0, 0, errors.Wrapf(ErrInvalidPage, "page %d size %d", pageIndex, pageSize)
+	}
+	return (pageIndex - 1) * pageSize, pageSize, nil
+}
+
+// indexedHeight return the height of the first block not counted by the token index
+func (service *TokenService) indexedHeight() uint64 {
+	for _, status := range service.ChainIndexerService.Status() {
+		if status.Name == TokenIndexerName {
+			return status.Sections * service.ChainIndexerService.GetConfig().SectionSize
+		}
+	}
+	return 0
+}
+
+// List return the tokens which have emitted indexed transfers
+func (service *TokenService) List() []*TokenInfo {
+	return readTokenInfos(service.DatabaseService)
+}
+
+func (service *TokenService) DefaultConfig() *TokenConfig {
+	return DefaultConfig
+}
@@ -0,0 +1,39 @@
+package token
+
+import (
+	"github.com/drep-project/DREP-Chain/common"
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+// TokenTransfer is a Transfer(address,address,uint256) event emitted by a token contract
+type TokenTransfer struct {
+	Token  crypto.CommonAddress
+	From   crypto.CommonAddress
+	To     crypto.CommonAddress
+	Value  common.Big
+	TxHash crypto.Hash
+	Height uint64
+	Index  uint32 // position of the log in the logs of the block
+}
+
+// TokenInfo is a contract which has emitted indexed transfers
+type TokenInfo struct {
+	Address   crypto.CommonAddress
+	Height    uint64 // block of the first indexed transfer
+	Transfers uint64 // number of indexed transfers
+	Holders   uint64 // number of addresses with a positive balance
+}
+
+// TokenBalance is the balance of a holder counted from the indexed blocks. The blocks are indexed
+// by sections once a section is confirmed, the blocks from IndexedHeight on are not counted yet.
+type TokenBalance struct {
+	Balance       common.Big
+	IndexedHeight uint64
+}
+
+// TokenTransfers is a page of the transfers from or to an address found in the blocks below
+// IndexedHeight
+type TokenTransfers struct {
+	Transfers     []*TokenTransfer
+	IndexedHeight uint64
+}
@@ -115,3 +115,21 @@ func (blockAnalysis *BlockAnalysis) Rebuild(from, end int) error {
 	}
 	return nil
 }
+
+// SentTransactions return the hashes of a page of the transactions sent by addr
+func (blockAnalysis *BlockAnalysis) SentTransactions(addr *crypto.CommonAddress, pageIndex, pageSize int) []crypto.Hash {
+	return txHashes(blockAnalysis.store.GetSendTransactionsByAddr(addr, pageIndex, pageSize))
+}
+
+// ReceivedTransactions return the hashes of a page of the transactions received by addr
+func (blockAnalysis *BlockAnalysis) ReceivedTransactions(addr *crypto.CommonAddress, pageIndex, pageSize int) []crypto.Hash {
+	return txHashes(blockAnalysis.store.GetReceiveTransactionsByAddr(addr, pageIndex, pageSize))
+}
+
+func txHashes(rpcTxs []*RpcTransaction) []crypto.Hash {
+	hashes := make([]crypto.Hash, len(rpcTxs))
+	for i, rpcTx := range rpcTxs {
+		hashes[i] = rpcTx.Hash
+	}
+	return hashes
+}
@@ -6,4 +6,6 @@ type HistoryConfig struct {
 	Url        string `json:"url"`
 	DbType     string `json:"dbtype"`
 	Enable     bool   `json:"enable"`
+	// max page size of paged transaction queries, larger pages are cut to it
+	MaxPageSize int `json:"maxPageSize"`
 }
@@ -16,6 +16,8 @@ type RpcTransaction struct {
 	From                  crypto.CommonAddress
 	types.TransactionData `bson:",inline"`
 	Sig                   common.Bytes
+	// Input is decoded with the abi registered for the called contract when queried over rpc
+	Input *types.DecodedCall `json:",omitempty" bson:"-"`
 }
 
 type RpcBlock struct {
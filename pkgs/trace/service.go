@@ -7,15 +7,18 @@ import (
 	"github.com/AsynkronIT/protoactor-go/actor"
 	"github.com/drep-project/DREP-Chain/app"
 	chainService "github.com/drep-project/DREP-Chain/chain"
+	accountService "github.com/drep-project/DREP-Chain/pkgs/accounts/service"
 	consensusService "github.com/drep-project/DREP-Chain/pkgs/consensus/service"
+	"github.com/drep-project/DREP-Chain/pkgs/evm"
 	"gopkg.in/urfave/cli.v1"
 )
 
 var (
 	DefaultHistoryConfig = &HistoryConfig{
-		Enable: false,
-		DbType: "leveldb",
-		Url:    "mongodb://localhost:27017",
+		Enable:      false,
+		DbType:      "leveldb",
+		Url:         "mongodb://localhost:27017",
+		MaxPageSize: 100,
 	}
 
 	EnableTraceFlag = cli.BoolFlag{
@@ -33,6 +36,8 @@ type TraceService struct {
 	Config           *HistoryConfig
 	ChainService     chainService.ChainServiceInterface `service:"chain"`
 	ConsensusService *consensusService.ConsensusService `service:"consensus"`
+	VmService        evm.Vm                             `service:"vm"`
+	AccountService   *accountService.AccountService     `service:"accounts,optional"`
 	apis             []app.API
 	blockAnalysis    *BlockAnalysis
 }
@@ -53,8 +58,8 @@ func (traceService *TraceService) P2pMessages() map[int]interface{} {
 	return map[int]interface{}{}
 }
 
-// Init used to create connection to storage(leveldb and mongo)
-func (traceService *TraceService) Init(executeContext *app.ExecuteContext) error {
+// ApplyFlags set history dir and enable trace from command line flags
+func (traceService *TraceService) ApplyFlags(executeContext *app.ExecuteContext) {
 	homeDir := executeContext.CommonConfig.HomeDir
 	if len(traceService.Config.HistoryDir) == 0 {
 		traceService.Config.HistoryDir = path.Join(homeDir, "trace")
@@ -67,7 +72,10 @@ func (traceService *TraceService) Init(executeContext *app.ExecuteContext) error
 	if ctx.GlobalIsSet(HistoryDirFlag.Name) {
 		traceService.Config.HistoryDir = ctx.GlobalString(HistoryDirFlag.Name)
 	}
+}
 
+// Init used to create connection to storage(leveldb and mongo)
+func (traceService *TraceService) Init(executeContext *app.ExecuteContext) error {
 	if !traceService.Config.Enable {
 		return nil
 	}
@@ -94,7 +102,11 @@ func (traceService *TraceService) Start(executeContext *app.ExecuteContext) erro
 	if traceService.Config == nil || !traceService.Config.Enable {
 		return nil
 	}
-	traceService.blockAnalysis.Start(traceService.ChainService.NewBlockFeed(), traceService.ChainService.DetachBlockFeed())
+	err := traceService.blockAnalysis.Start(traceService.ChainService.NewBlockFeed(), traceService.ChainService.DetachBlockFeed())
+	// watch-only addresses of the wallet are listed with their history once the store is open
+	if err == nil && traceService.AccountService != nil {
+		traceService.AccountService.SetTxHistory(traceService.blockAnalysis)
+	}
 	return nil
 }
 
@@ -102,6 +114,9 @@ func (traceService *TraceService) Stop(executeContext *app.ExecuteContext) error
 	if traceService.Config == nil || !traceService.Config.Enable {
 		return nil
 	}
+	if traceService.AccountService != nil {
+		traceService.AccountService.SetTxHistory(nil)
+	}
 	traceService.blockAnalysis.Close()
 	return nil
 }
@@ -44,7 +44,7 @@ func (traceApi *TraceApi) GetRawTransaction(txHash *crypto.Hash) (string, error)
  usage: 根据交易hash查询交易详细信息
  params:
 	1. 交易hash
- return: 交易详细信息
+ return: 交易详细信息，调用的合约注册了abi时Input为解码后的方法名和参数
  example: curl http://localhost:15645 -X POST --data '{"jsonrpc":"2.0","method":"trace_getTransaction","params":["0x00001c9b8c8fdb1f53faf02321f76253704123e2b56cce065852bab93e526ae2"], "id": 3}' -H "Content-Type:application/json"
  response:
    {
@@ -72,6 +72,7 @@ func (traceApi *TraceApi) GetTransaction(txHash *crypto.Hash) (*RpcTransaction,
 	if err != nil {
 		return nil, err
 	}
+	traceApi.decodeInput(rpcTx)
 	return rpcTx, nil
 }
 
@@ -111,6 +112,7 @@ func (traceApi *TraceApi) DecodeTrasnaction(bytes common.Bytes) (*RpcTransaction
 	}
 	rpcTx := &RpcTransaction{}
 	rpcTx.FromTx(tx)
+	traceApi.decodeInput(rpcTx)
 	return rpcTx, nil
 }
 
@@ -147,7 +149,10 @@ func (traceApi *TraceApi) DecodeTrasnaction(bytes common.Bytes) (*RpcTransaction
 	}
 */
 func (traceApi *TraceApi) GetSendTransactionByAddr(addr *crypto.CommonAddress, pageIndex, pageSize int) []*RpcTransaction {
-	return traceApi.blockAnalysis.store.GetSendTransactionsByAddr(addr, pageIndex, pageSize)
+	pageIndex, pageSize = traceApi.page(pageIndex, pageSize)
+	rpcTxs := traceApi.blockAnalysis.store.GetSendTransactionsByAddr(addr, pageIndex, pageSize)
+	traceApi.decodeInput(rpcTxs...)
+	return rpcTxs
 }
 
 /*
@@ -183,7 +188,10 @@ func (traceApi *TraceApi) GetSendTransactionByAddr(addr *crypto.CommonAddress, p
 	}
 */
 func (traceApi *TraceApi) GetReceiveTransactionByAddr(addr *crypto.CommonAddress, pageIndex, pageSize int) []*RpcTransaction {
-	return traceApi.blockAnalysis.store.GetReceiveTransactionsByAddr(addr, pageIndex, pageSize)
+	pageIndex, pageSize = traceApi.page(pageIndex, pageSize)
+	rpcTxs := traceApi.blockAnalysis.store.GetReceiveTransactionsByAddr(addr, pageIndex, pageSize)
+	traceApi.decodeInput(rpcTxs...)
+	return rpcTxs
 }
 
 /*
@@ -209,3 +217,21 @@ func (traceApi *TraceApi) Rebuild(from, end int) error {
 	}
 	return traceApi.blockAnalysis.Rebuild(from, end)
 }
+
+// page bound the page of paged queries, page index starts from 1 and page size is cut to MaxPageSize
+func (traceApi *TraceApi) page(pageIndex, pageSize int) (int, int) {
+	if pageIndex < 1 {
+		pageIndex = 1
+	}
+	if maxPageSize := traceApi.traceService.Config.MaxPageSize; maxPageSize > 0 && (pageSize <= 0 || pageSize > maxPageSize) {
+		pageSize = maxPageSize
+	}
+	return pageIndex, pageSize
+}
+
+// decodeInput fill the decoded input of the calls to contracts with a registered abi
+func (traceApi *TraceApi) decodeInput(rpcTxs ...*RpcTransaction) {
+	for _, rpcTx := range rpcTxs {
+		rpcTx.Input = traceApi.traceService.VmService.DecodeInput(rpcTx.ToTx())
+	}
+}
@@ -20,6 +20,9 @@ type Node struct {
 	PrivateKey *secp256k1.PrivateKey
 	ChainId    ChainIdType
 	ChainCode  []byte
+	// BIP32 derivation path of keys derived from a mnemonic, "m" for the master key and empty
+	// for imported keys and keys created by NewNode
+	Path string
 }
 
 func NewNode(parent *Node, chainId ChainIdType) *Node {
@@ -66,6 +69,10 @@ type Storage struct {
 
 	Alias      string
 	BalanceMap map[string]big.Int
+
+	//root of the storage trie of a contract, keep it last so accounts written before it was
+	//added still decode
+	StorageRoot crypto.Hash
 }
 
 func NewStorage() *Storage {
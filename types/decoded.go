@@ -0,0 +1,22 @@
+package types
+
+// DecodedArg is an argument of an event or a call decoded with the abi of the contract
+type DecodedArg struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+// DecodedEvent is a log decoded with the abi registered for the contract emitting it
+type DecodedEvent struct {
+	Name      string
+	Signature string
+	Args      []DecodedArg
+}
+
+// DecodedCall is the input of a contract call decoded with the abi registered for the contract
+type DecodedCall struct {
+	Method    string
+	Signature string
+	Args      []DecodedArg
+}
@@ -3,5 +3,11 @@ package types
 import "errors"
 
 var (
-	ErrOutOfGas = errors.New("out of gas")
+	ErrOutOfGas         = errors.New("out of gas")
+	ErrTxHashMismatch   = errors.New("transaction hash does not match its data")
+	ErrTxSenderMismatch = errors.New("key does not belong to the sender of transaction")
+
+	ErrInvalidMultiSig   = errors.New("invalid multi signature")
+	ErrMultiSigThreshold = errors.New("multi signature has less signers than the threshold")
+	ErrMultiSigInactive  = errors.New("multi signatures are not active at this height")
 )
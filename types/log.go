@@ -25,4 +25,7 @@ type Log struct {
 	// The Removed field is true if this log was reverted due to a chain reorganisation.
 	// You must pay attention to this field if you receive logs through a filter query.
 	Removed bool
+
+	// Event is decoded with the abi registered for Address when the log is queried over rpc
+	Event *DecodedEvent `json:",omitempty" binary:"ignore"`
 }
@@ -0,0 +1,186 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
+	"github.com/drep-project/DREP-Chain/crypto/sha3"
+	"github.com/drep-project/binary"
+)
+
+const (
+	// MultiSigFlag is the first byte of multi signatures, compact signatures start from 27 so
+	// the two can not be confused
+	MultiSigFlag = byte(0x01)
+
+	// MaxMultiSigKeys is the largest number of keys of a multi signature account
+	MaxMultiSigKeys = 16
+)
+
+var (
+	multiSigAddressPrefix     = []byte("drep multisig")
+	multiSigCoefficientPrefix = []byte("drep multisig coefficient")
+)
+
+// MultiSigAccount is an m-of-n account, a transaction from it must be signed by Threshold of
+// PubKeys. The address is derived from the threshold and the keys in order. Every key is weighted
+// by a coefficient hashed from all keys of the account like MuSig, so a key chosen after seeing
+// the others can not cancel them out.
+type MultiSigAccount struct {
+	Threshold uint32                 `json:"threshold"`
+	PubKeys   []*secp256k1.PublicKey `json:"pubkeys"`
+}
+
+// Validate check the threshold and that keys are not repeated
+func (account *MultiSigAccount) Validate() error {
+	if len(account.PubKeys) == 0 || len(account.PubKeys) > MaxMultiSigKeys {
+		return fmt.Errorf("multisig account must have 1 to %d keys", MaxMultiSigKeys)
+	}
+	if account.Threshold == 0 || int(account.Threshold) > len(account.PubKeys) {
+		return fmt.Errorf("threshold must be between 1 and %d", len(account.PubKeys))
+	}
+	for i, pubkey := range account.PubKeys {
+		if pubkey == nil {
+			return fmt.Errorf("pubkey %d is missing", i)
+		}
+		for j := 0; j < i; j++ {
+			if account.PubKeys[j].IsEqual(pubkey) {
+				return fmt.Errorf("pubkey %d repeats pubkey %d", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Address of the account, keccak256 of the threshold and the compressed keys like
+// PubkeyToAddress takes the last 20 bytes
+func (account *MultiSigAccount) Address() crypto.CommonAddress {
+	content := append([]byte{}, multiSigAddressPrefix...)
+	threshold := account.Threshold
+	content = append(content, byte(threshold>>24), byte(threshold>>16), byte(threshold>>8), byte(threshold))
+	for _, pubkey := range account.PubKeys {
+		content = append(content, pubkey.SerializeCompressed()...)
+	}
+	return crypto.BytesToAddress(sha3.Keccak256(content)[12:])
+}
+
+// IndexOf return the index of pubkey in the account, -1 if it is not a key of the account
+func (account *MultiSigAccount) IndexOf(pubkey *secp256k1.PublicKey) int {
+	for i, key := range account.PubKeys {
+		if key.IsEqual(pubkey) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Coefficient return the weight of the key at index, keccak256 of all keys and the key itself
+func (account *MultiSigAccount) Coefficient(index int) *big.Int {
+	keys := []byte{}
+	for _, pubkey := range account.PubKeys {
+		keys = append(keys, pubkey.SerializeCompressed()...)
+	}
+	content := append([]byte{}, multiSigCoefficientPrefix...)
+	content = append(content, sha3.Keccak256(keys)...)
+	content = append(content, account.PubKeys[index].SerializeCompressed()...)
+	coefficient := new(big.Int).SetBytes(sha3.Keccak256(content))
+	return coefficient.Mod(coefficient, secp256k1.S256().N)
+}
+
+// SigningKey return the key the owner of the key at index signs with, the private key multiplied
+// by the coefficient of the key
+func (account *MultiSigAccount) SigningKey(index int, key *secp256k1.PrivateKey) *secp256k1.PrivateKey {
+	d := new(big.Int).Mul(key.D, account.Coefficient(index))
+	return secp256k1.NewPrivateKey(d.Mod(d, secp256k1.S256().N))
+}
+
+// weightedKey return the public key of the key at index multiplied by its coefficient
+func (account *MultiSigAccount) weightedKey(index int) *secp256k1.PublicKey {
+	pubkey := account.PubKeys[index]
+	x, y := secp256k1.S256().ScalarMult(pubkey.GetX(), pubkey.GetY(), account.Coefficient(index).Bytes())
+	return secp256k1.NewPublicKey(x, y)
+}
+
+// multiSigProof is the signature of a multisig transaction after MultiSigFlag
+type multiSigProof struct {
+	Threshold uint32
+	PubKeys   [][]byte
+	// Bitmap has 1 at the index of each key which signed
+	Bitmap []byte
+	Sig    []byte
+}
+
+// NewMultiSig encode the schnorr signature of the keys of account marked in bitmap as
+// transaction signature
+func NewMultiSig(account *MultiSigAccount, bitmap []byte, sig *schnorr.Signature) ([]byte, error) {
+	proof := &multiSigProof{
+		Threshold: account.Threshold,
+		Bitmap:    bitmap,
+		Sig:       sig.Serialize(),
+	}
+	for _, pubkey := range account.PubKeys {
+		proof.PubKeys = append(proof.PubKeys, pubkey.SerializeCompressed())
+	}
+	content, err := binary.Marshal(proof)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{MultiSigFlag}, content...), nil
+}
+
+// IsMultiSig check whether sig is a multi signature
+func IsMultiSig(sig []byte) bool {
+	return len(sig) > 0 && sig[0] == MultiSigFlag
+}
+
+// VerifyMultiSig check that sig is made over hash by enough keys of its account and return
+// the account, the keys which signed are weighted by their coefficients
+func VerifyMultiSig(sig, hash []byte) (*MultiSigAccount, error) {
+	if !IsMultiSig(sig) {
+		return nil, ErrInvalidMultiSig
+	}
+	proof := &multiSigProof{}
+	if err := binary.Unmarshal(sig[1:], proof); err != nil {
+		return nil, err
+	}
+	account := &MultiSigAccount{Threshold: proof.Threshold}
+	for _, keyBytes := range proof.PubKeys {
+		pubkey, err := secp256k1.ParsePubKey(keyBytes)
+		if err != nil {
+			return nil, err
+		}
+		account.PubKeys = append(account.PubKeys, pubkey)
+	}
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
+	if len(proof.Bitmap) != len(account.PubKeys) {
+		return nil, ErrInvalidMultiSig
+	}
+	signers := []*secp256k1.PublicKey{}
+	for i, val := range proof.Bitmap {
+		switch val {
+		case 0:
+		case 1:
+			signers = append(signers, account.weightedKey(i))
+		default:
+			return nil, ErrInvalidMultiSig
+		}
+	}
+	if len(signers) < int(account.Threshold) {
+		return nil, ErrMultiSigThreshold
+	}
+	schnorrSig, err := schnorr.ParseSignature(proof.Sig)
+	if err != nil {
+		return nil, err
+	}
+	// keys summing to infinity make no valid key
+	signersKey := schnorr.CombinePubkeys(signers)
+	if signersKey == nil || !schnorr.Verify(signersKey, hash, schnorrSig.R, schnorrSig.S) {
+		return nil, ErrInvalidMultiSig
+	}
+	return account, nil
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1/schnorr"
+	"github.com/drep-project/DREP-Chain/params"
+)
+
+func multiSign(t *testing.T, account *MultiSigAccount, keys []*secp256k1.PrivateKey, hash []byte) *schnorr.Signature {
+	nonces := []*secp256k1.PrivateKey{}
+	pubNonces := []*secp256k1.PublicKey{}
+	for i, key := range keys {
+		nonce, pubNonce, err := schnorr.GenerateNoncePair(secp256k1.S256(), hash, account.SigningKey(i, key), nil, schnorr.Sha256VersionStringRFC6979)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonces = append(nonces, nonce)
+		pubNonces = append(pubNonces, pubNonce)
+	}
+	sigs := []*schnorr.Signature{}
+	for i, key := range keys {
+		others := append(append([]*secp256k1.PublicKey{}, pubNonces[:i]...), pubNonces[i+1:]...)
+		sig, err := schnorr.PartialSign(secp256k1.S256(), hash, account.SigningKey(i, key), nonces[i], schnorr.CombinePubkeys(others))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+	}
+	sig, err := schnorr.CombineSigs(secp256k1.S256(), sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestMultiSigFrom(t *testing.T) {
+	keys := []*secp256k1.PrivateKey{}
+	account := &MultiSigAccount{Threshold: 2}
+	for i := 0; i < 2; i++ {
+		key, _ := secp256k1.GeneratePrivateKey(nil)
+		keys = append(keys, key)
+		account.PubKeys = append(account.PubKeys, key.PubKey())
+	}
+	tx := NewTransaction(crypto.CommonAddress{1}, big.NewInt(10), big.NewInt(1), big.NewInt(21000), 0)
+	sig, err := NewMultiSig(account, []byte{1, 1}, multiSign(t, account, keys, tx.TxHash().Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Sig = sig
+
+	if _, err := tx.FromAt(params.Rules{}); err != ErrMultiSigInactive {
+		t.Fatalf("expect %v, got %v", ErrMultiSigInactive, err)
+	}
+	from, err := tx.FromAt(params.Rules{IsMultiSig: true})
+	if err != nil || *from != account.Address() {
+		t.Fatalf("expect sender %s, got %v %v", account.Address().String(), from, err)
+	}
+}
+
+func TestMultiSigRogueKey(t *testing.T) {
+	honest, _ := secp256k1.GeneratePrivateKey(nil)
+	attacker, _ := secp256k1.GeneratePrivateKey(nil)
+
+	// the rogue key is the attacker key minus the honest key, the plain sum of the two keys is
+	// the attacker key
+	curve := secp256k1.S256()
+	honestKey := honest.PubKey()
+	negY := new(big.Int).Sub(curve.P, honestKey.GetY())
+	x, y := curve.Add(attacker.PubKey().GetX(), attacker.PubKey().GetY(), honestKey.GetX(), negY)
+	account := &MultiSigAccount{Threshold: 2, PubKeys: []*secp256k1.PublicKey{honestKey, secp256k1.NewPublicKey(x, y)}}
+
+	tx := NewTransaction(crypto.CommonAddress{1}, big.NewInt(10), big.NewInt(1), big.NewInt(21000), 0)
+	r, s, err := schnorr.Sign(attacker, tx.TxHash().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := NewMultiSig(account, []byte{1, 1}, schnorr.NewSignature(r, s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyMultiSig(sig, tx.TxHash().Bytes()); err != ErrInvalidMultiSig {
+		t.Fatalf("expect %v, got %v", ErrInvalidMultiSig, err)
+	}
+}
@@ -49,6 +49,8 @@ type Receipt struct {
 	// transaction corresponding to this receipt.
 	BlockHash   crypto.Hash
 	BlockNumber uint64
+	// filled from the tx lookup index when the receipt is read, not part of the receipt root
+	TxIndex uint `binary:"ignore"`
 }
 
 // NewReceipt creates a barebone transaction receipt, copying the init fields.
@@ -48,16 +48,36 @@ func (tx *Transaction) Gas() uint64 {
 	return (&bigInt).Uint64()
 }
 
+// FromAt return the sender of tx executed under rules, multi signatures are rejected before the
+// multisig fork so that blocks below it are validated as before
+func (tx *Transaction) FromAt(rules params.Rules) (*crypto.CommonAddress, error) {
+	if IsMultiSig(tx.Sig) && !rules.IsMultiSig {
+		return nil, ErrMultiSigInactive
+	}
+	return tx.From()
+}
+
+// From return the sender of tx whichever the signature format, transactions of blocks and of the
+// pool are checked by FromAt first
 func (tx *Transaction) From() (*crypto.CommonAddress, error) {
 	if sc := tx.from.Load(); sc != nil {
 		return sc.(*crypto.CommonAddress), nil
 	}
 
-	pk, _, err := secp256k1.RecoverCompact(tx.Sig, tx.TxHash().Bytes())
-	if err != nil {
-		return nil, err
+	var addr crypto.CommonAddress
+	if IsMultiSig(tx.Sig) {
+		account, err := VerifyMultiSig(tx.Sig, tx.TxHash().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		addr = account.Address()
+	} else {
+		pk, _, err := secp256k1.RecoverCompact(tx.Sig, tx.TxHash().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		addr = crypto.PubkeyToAddress(pk)
 	}
-	addr := crypto.PubkeyToAddress(pk)
 	tx.from.Store(&addr)
 	return &addr, nil
 }
@@ -0,0 +1,12 @@
+package types
+
+import (
+	"github.com/drep-project/DREP-Chain/crypto"
+)
+
+// TxLookupEntry locate a transaction of the main chain by its hash
+type TxLookupEntry struct {
+	BlockHash crypto.Hash
+	Height    uint64
+	Index     uint64
+}
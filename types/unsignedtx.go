@@ -0,0 +1,41 @@
+package types
+
+import (
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+)
+
+// UnsignedTransaction is a transaction built by a node without keys, it is signed offline with
+// the key of From and submitted as raw transaction
+type UnsignedTransaction struct {
+	From crypto.CommonAddress `json:"from"`
+	Data TransactionData      `json:"data"`
+	// hash to sign, checked against Data before signing
+	Hash crypto.Hash `json:"hash"`
+}
+
+func NewUnsignedTransaction(from crypto.CommonAddress, tx *Transaction) *UnsignedTransaction {
+	return &UnsignedTransaction{
+		From: from,
+		Data: tx.Data,
+		Hash: *tx.TxHash(),
+	}
+}
+
+// Sign check that the hash matches the transaction and key belongs to From, then return the
+// transaction signed with key
+func (utx *UnsignedTransaction) Sign(key *secp256k1.PrivateKey) (*Transaction, error) {
+	tx := &Transaction{Data: utx.Data}
+	if *tx.TxHash() != utx.Hash {
+		return nil, ErrTxHashMismatch
+	}
+	if crypto.PubkeyToAddress(key.PubKey()) != utx.From {
+		return nil, ErrTxSenderMismatch
+	}
+	sig, err := secp256k1.SignCompact(key, tx.TxHash().Bytes(), true)
+	if err != nil {
+		return nil, err
+	}
+	tx.Sig = sig
+	return tx, nil
+}
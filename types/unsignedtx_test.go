@@ -0,0 +1,35 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/drep-project/DREP-Chain/crypto"
+	"github.com/drep-project/DREP-Chain/crypto/secp256k1"
+)
+
+func TestUnsignedTransactionSign(t *testing.T) {
+	key, _ := secp256k1.GeneratePrivateKey(nil)
+	from := crypto.PubkeyToAddress(key.PubKey())
+	to := crypto.CommonAddress{1}
+	tx := NewTransaction(to, new(big.Int).SetUint64(10), new(big.Int).SetUint64(1), new(big.Int).SetUint64(21000), 3)
+	utx := NewUnsignedTransaction(from, tx)
+
+	signed, err := utx.Sign(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := signed.From()
+	if err != nil || *sender != from {
+		t.Fatalf("expect sender %s, got %v %v", from.String(), sender, err)
+	}
+
+	other, _ := secp256k1.GeneratePrivateKey(nil)
+	if _, err := utx.Sign(other); err != ErrTxSenderMismatch {
+		t.Fatalf("expect %v, got %v", ErrTxSenderMismatch, err)
+	}
+	utx.Data.Nonce++
+	if _, err := utx.Sign(key); err != ErrTxHashMismatch {
+		t.Fatalf("expect %v, got %v", ErrTxHashMismatch, err)
+	}
+}